@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"github.com/noneymous/go-sqlfmt/sqlfmt"
 	"github.com/noneymous/go-sqlfmt/sqlfmt/formatters"
+	"github.com/noneymous/go-sqlfmt/sqlfmt/lexer"
 	"io"
 	"log"
 	"os"
@@ -33,6 +34,23 @@ func init() {
 	flag.StringVar(&options.Indent, "indent", "", "define a string to use for indentation.")
 	flag.StringVar(&options.Newline, "newline", "", "define a string to use for line breaks.")
 	flag.StringVar(&options.Whitespace, "whitespace", "", "define a string to use as a whitespace between values.")
+	flag.BoolVar(&options.Recover, "recover", false, "write unsupported parts of queries verbatim instead of skipping the whole query.")
+	flag.BoolVar(&options.DisableFunctionKeywords, "nofunctionkeywords", false, "treat parenthesis-less functions, such as USER or CURRENT_DATE, as common names.")
+	flag.Func("dialect", "define the SQL dialect to format (generic, ansi, postgresql, mysql, sqlite, sqlserver, oracle).", func(name string) error {
+		dialect, errDialect := lexer.ParseDialect(name)
+		options.Dialect = dialect
+		return errDialect
+	})
+	flag.Func("registry", "define a JSON file with additional functions, types, function keywords and keywords.", func(path string) error {
+		f, errOpen := os.Open(path)
+		if errOpen != nil {
+			return errOpen
+		}
+		defer func() { _ = f.Close() }()
+		registry, errLoad := lexer.LoadRegistry(f)
+		options.Registry = registry
+		return errLoad
+	})
 }
 
 func main() {
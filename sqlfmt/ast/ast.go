@@ -0,0 +1,101 @@
+// Package ast declares the types used to represent syntax trees of SQL statements. The parser groups tokens
+// into nested segments, such as clauses, parentheses or function calls, which the formatters render. Typed
+// statement and expression nodes are built from these segments, allowing tools to analyze statements, e.g. to
+// look up the tables read by a SELECT statement or its WHERE conditions.
+package ast
+
+import (
+	"strings"
+
+	"github.com/noneymous/go-sqlfmt/sqlfmt/lexer"
+)
+
+// Node is implemented by all nodes of the syntax tree
+type Node interface {
+	Tokens() []lexer.Token // Tokens the node is comprised out of, in order of appearance
+}
+
+// Token is a leaf of the syntax tree holding a single token
+type Token struct {
+	lexer.Token
+}
+
+// Tokens returns the token of the leaf
+func (t *Token) Tokens() []lexer.Token {
+	return []lexer.Token{t.Token}
+}
+
+// Segment is a logical group of tokens, such as a clause introduced by a keyword, a parenthesis or a function
+// call. Segments may be nested arbitrarily. Segments with an end token, e.g. ")" closing "(" or "END" closing
+// "CASE", contain it as their last child.
+type Segment struct {
+	Children []Node // Tokens and nested segments, starting with the token introducing the segment
+	Err      error  // Reason why the region could not be parsed, if the segment holds its tokens as they are
+}
+
+// Kind returns the type of the token introducing the segment
+func (s *Segment) Kind() lexer.TokenType {
+	if len(s.Children) == 0 {
+		return 0
+	}
+	if token, ok := s.Children[0].(*Token); ok {
+		return token.Type
+	}
+	return 0
+}
+
+// Tokens returns the tokens of the segment, including the ones of nested segments
+func (s *Segment) Tokens() []lexer.Token {
+	return tokensOf(s.Children)
+}
+
+// span is embedded by typed nodes, remembering the nodes of the segment tree they were built from
+type span struct {
+	nodes []Node
+}
+
+// Tokens returns the tokens the typed node was built from
+func (s span) Tokens() []lexer.Token {
+	return tokensOf(s.nodes)
+}
+
+// Start returns the location of the first token of the node. The zero position is returned for empty nodes.
+func Start(node Node) lexer.Position {
+	tokens := node.Tokens()
+	if len(tokens) == 0 {
+		return lexer.Position{}
+	}
+	return tokens[0].Start
+}
+
+// End returns the location directly after the last token of the node. The zero position is returned for
+// empty nodes.
+func End(node Node) lexer.Position {
+	tokens := node.Tokens()
+	if len(tokens) == 0 {
+		return lexer.Position{}
+	}
+	return tokens[len(tokens)-1].End
+}
+
+// Text returns the original text of the node's tokens separated by single white-spaces, e.g. "a = 1"
+func Text(node Node) string {
+	var parts []string
+	for _, token := range node.Tokens() {
+		if token.Raw != "" {
+			parts = append(parts, token.Raw)
+		} else {
+			parts = append(parts, token.Value)
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// tokensOf returns the tokens of a sequence of nodes
+func tokensOf(nodes []Node) []lexer.Token {
+	var tokens []lexer.Token
+	for _, node := range nodes {
+		tokens = append(tokens, node.Tokens()...)
+	}
+	return tokens
+}
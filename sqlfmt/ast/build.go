@@ -0,0 +1,802 @@
+package ast
+
+import (
+	"strings"
+
+	"github.com/noneymous/go-sqlfmt/sqlfmt/lexer"
+)
+
+// NewStatement builds a typed statement from the segments of a single statement, as returned by the parser.
+// Statements other than SELECT, INSERT, UPDATE and DELETE are returned as GenericStmt.
+func NewStatement(segments []*Segment) Statement {
+	nodes := make([]Node, 0, len(segments))
+	for _, segment := range segments {
+		nodes = append(nodes, segment)
+	}
+	return buildStatement(nodes)
+}
+
+// clause is a sequence of nodes introduced by a clause keyword, e.g. WHERE. The segment tree nests some clauses
+// into each other, e.g. the FROM clause of a DELETE statement, which are flattened into a sequence of clauses.
+type clause struct {
+	kind  lexer.TokenType // Type of the token introducing the clause
+	nodes []Node          // Nodes of the clause, starting with the token introducing it
+}
+
+// buildStatement builds a typed statement from a sequence of nodes
+func buildStatement(nodes []Node) Statement {
+
+	// Split nodes into clauses
+	clauses := splitClauses(nodes)
+	if len(clauses) == 0 {
+		return &GenericStmt{span: span{nodes}, Nodes: nodes}
+	}
+
+	// Build common table expressions preceding the statement
+	var with *WithClause
+	if clauses[0].kind == lexer.WITH {
+		with = buildWith(clauses[0].nodes)
+		clauses = clauses[1:]
+	}
+	if len(clauses) == 0 {
+		return &GenericStmt{span: span{nodes}, Nodes: nodes}
+	}
+
+	// Build statement depending on the first clause
+	switch clauses[0].kind {
+	case lexer.SELECT:
+		stmt := buildSelect(clauses)
+		stmt.span, stmt.With = span{nodes}, with
+		return stmt
+	case lexer.INSERT:
+		stmt := buildInsert(clauses)
+		stmt.span, stmt.With = span{nodes}, with
+		return stmt
+	case lexer.UPDATE:
+		stmt := buildUpdate(clauses)
+		stmt.span, stmt.With = span{nodes}, with
+		return stmt
+	case lexer.DELETE:
+		stmt := buildDelete(clauses)
+		stmt.span, stmt.With = span{nodes}, with
+		return stmt
+	default:
+		return &GenericStmt{span: span{nodes}, Nodes: nodes}
+	}
+}
+
+// splitClauses flattens a sequence of nodes into a sequence of clauses
+func splitClauses(nodes []Node) []*clause {
+
+	// Prepare process variables
+	var clauses []*clause
+	var current *clause
+
+	// Iterate nodes and recursively step into clause segments
+	var split func(nodes []Node)
+	split = func(nodes []Node) {
+		for _, node := range significant(nodes) {
+			segment, isSegment := node.(*Segment)
+			switch {
+			case isSegment && isClause(segment.Kind()):
+				current = &clause{kind: segment.Kind(), nodes: []Node{segment.Children[0]}}
+				clauses = append(clauses, current)
+				split(segment.Children[1:])
+			case isSegment && current != nil && (segment.Kind() == lexer.AND || segment.Kind() == lexer.OR):
+				split(segment.Children) // Logical segments might contain subsequent clauses, e.g. JOIN ... AND ... WHERE
+			case current == nil,
+				isToken(node, lexer.RETURNING),
+				current.kind == lexer.WITH && isToken(node, lexer.SELECT, lexer.INSERT, lexer.UPDATE, lexer.DELETE):
+				current = &clause{kind: kindOf(node), nodes: []Node{node}}
+				clauses = append(clauses, current)
+			default:
+				current.nodes = append(current.nodes, node)
+			}
+		}
+	}
+	split(nodes)
+
+	// Return clauses
+	return clauses
+}
+
+// buildWith builds the common table expressions of a WITH clause
+func buildWith(nodes []Node) *WithClause {
+
+	// Prepare WITH clause and skip RECURSIVE keyword
+	with := &WithClause{span: span{nodes}}
+	i := 1
+	if i < len(nodes) && strings.EqualFold(textOf(nodes[i]), "RECURSIVE") {
+		with.Recursive = true
+		i++
+	}
+
+	// Iterate common table expressions separated by commas
+	for i < len(nodes) {
+
+		// Search AS keyword following the name
+		start := i
+		for i < len(nodes) && !isToken(nodes[i], lexer.AS) {
+			i++
+		}
+		if i == start || i >= len(nodes) {
+			break
+		}
+
+		// Build name and columns, e.g. x(a, b)
+		cte := &CTE{}
+		for _, node := range nodes[start:i] {
+			if segment, ok := node.(*Segment); ok && segment.Kind() == lexer.FUNCTION {
+				cte.Name = textOf(segment.Children[0])
+				cte.Columns = buildExprs(inner(segment))
+			} else if ok && segment.Kind() == lexer.STARTPARENTHESIS {
+				cte.Columns = buildExprs(inner(segment))
+			} else if cte.Name == "" {
+				cte.Name = textOf(node)
+			}
+		}
+
+		// Search parenthesis containing the statement, skipping keywords such as MATERIALIZED
+		for i < len(nodes) && kindOf(nodes[i]) != lexer.STARTPARENTHESIS {
+			i++
+		}
+		if i >= len(nodes) {
+			break
+		}
+		cte.Stmt = buildStatement(inner(nodes[i].(*Segment)))
+		cte.span = span{nodes[start : i+1]}
+		with.CTEs = append(with.CTEs, cte)
+
+		// Continue with the next common table expression, if there is one
+		i++
+		if i < len(nodes) && isToken(nodes[i], lexer.COMMA) {
+			i++
+			continue
+		}
+		break
+	}
+
+	// Return WITH clause
+	return with
+}
+
+// buildSelect builds a SELECT statement from its clauses
+func buildSelect(clauses []*clause) *SelectStmt {
+	stmt := &SelectStmt{span: span{clauseNodes(clauses)}}
+	for i, c := range clauses {
+		body := c.nodes[1:]
+		switch c.kind {
+		case lexer.SELECT:
+
+			// Skip keywords preceding the columns, e.g. DISTINCT or DISTINCT ON (a)
+			if len(body) > 0 && isToken(body[0], lexer.DISTINCT, lexer.DISTINCTROW) {
+				stmt.Distinct = true
+				body = body[1:]
+				if len(body) > 1 && isToken(body[0], lexer.ON) {
+					body = body[2:]
+				}
+			} else if len(body) > 0 && isToken(body[0], lexer.ALL) {
+				body = body[1:]
+			}
+			stmt.Columns = buildList(body)
+
+		case lexer.FROM:
+			stmt.From = buildTables(body)
+		case lexer.JOIN, lexer.INNER, lexer.OUTER, lexer.LEFT, lexer.RIGHT, lexer.NATURAL, lexer.CROSS:
+			stmt.Joins = append(stmt.Joins, buildJoin(c.nodes))
+		case lexer.WHERE:
+			stmt.Where = buildExpr(body)
+		case lexer.GROUP:
+			stmt.GroupBy = buildList(skipBy(body))
+		case lexer.HAVING:
+			stmt.Having = buildExpr(body)
+		case lexer.ORDER:
+			stmt.OrderBy = buildList(skipBy(body))
+		case lexer.LIMIT, lexer.OFFSET, lexer.FETCH:
+			buildLimit(stmt, c.nodes)
+		case lexer.UNION, lexer.INTERSECT, lexer.EXCEPT:
+
+			// Build operator, e.g. UNION ALL, and the combined statement, which is either in parentheses or
+			// comprised out of the subsequent clauses
+			setOp := &SetOp{span: span{clauseNodes(clauses[i:])}}
+			var operands []Node
+			for _, node := range c.nodes {
+				if token, ok := node.(*Token); ok && setOp.Select == nil && len(operands) == 0 {
+					setOp.Op = strings.TrimSpace(setOp.Op + " " + strings.ToUpper(token.Value))
+				} else {
+					operands = append(operands, node)
+				}
+			}
+			if len(operands) == 1 && kindOf(operands[0]) == lexer.STARTPARENTHESIS {
+				setOp.Select, _ = buildStatement(inner(operands[0].(*Segment))).(*SelectStmt)
+			} else if i+1 < len(clauses) {
+				setOp.Select = buildSelect(clauses[i+1:])
+			}
+			stmt.SetOp = setOp
+			return stmt
+		}
+	}
+	return stmt
+}
+
+// buildLimit builds the row limit and offset of a SELECT statement, e.g. LIMIT 10 OFFSET 5 or FETCH FIRST 10 ROWS
+func buildLimit(stmt *SelectStmt, nodes []Node) {
+	for _, part := range splitBefore(nodes, lexer.LIMIT, lexer.OFFSET, lexer.FETCH) {
+		body := part[1:]
+		switch kindOf(part[0]) {
+		case lexer.LIMIT:
+
+			// MySQL allows to define the offset in front of the limit, e.g. LIMIT 5, 10
+			if items := splitComma(body); len(items) == 2 {
+				stmt.Offset = buildExpr(items[0])
+				stmt.Limit = buildExpr(items[1])
+			} else {
+				stmt.Limit = buildExpr(body)
+			}
+
+		case lexer.OFFSET:
+			if len(body) > 1 && isToken(body[len(body)-1], lexer.ROWS) {
+				body = body[:len(body)-1]
+			}
+			stmt.Offset = buildExpr(body)
+		case lexer.FETCH:
+			for _, node := range body {
+				if isToken(node, lexer.NUMBER, lexer.PLACEHOLDER) {
+					stmt.Limit = buildExpr([]Node{node})
+					break
+				}
+			}
+		}
+	}
+}
+
+// buildInsert builds an INSERT statement from its clauses
+func buildInsert(clauses []*clause) *InsertStmt {
+	stmt := &InsertStmt{span: span{clauseNodes(clauses)}}
+	for i := 0; i < len(clauses); i++ {
+		c := clauses[i]
+		body := c.nodes[1:]
+		switch c.kind {
+		case lexer.INSERT:
+
+			// Skip keywords preceding the table
+			for len(body) > 0 && !isToken(body[0], lexer.IDENT, lexer.QUOTED_IDENT) && kindOf(body[0]) != lexer.FUNCTION {
+				body = body[1:]
+			}
+
+			// Build table and columns, either following the table in parentheses or parsed like a function
+			// call, e.g. t(a, b)
+			if len(body) > 0 && kindOf(body[0]) == lexer.FUNCTION {
+				segment := body[0].(*Segment)
+				stmt.Table = &Table{span: span{body[:1]}, Expr: buildIdent(segment.Children[0])}
+				stmt.Columns = buildExprs(inner(segment))
+				continue
+			}
+			end := 0
+			for end < len(body) && kindOf(body[end]) != lexer.STARTPARENTHESIS {
+				end++
+			}
+			if tables := buildTables(body[:end]); len(tables) > 0 {
+				stmt.Table = tables[0]
+			}
+			if end < len(body) {
+				stmt.Columns = buildExprs(inner(body[end].(*Segment)))
+			}
+
+		case lexer.VALUES:
+			for _, row := range splitComma(body) {
+				if len(row) == 1 && kindOf(row[0]) == lexer.STARTPARENTHESIS {
+					stmt.Values = append(stmt.Values, buildExprs(inner(row[0].(*Segment))))
+				}
+			}
+
+		case lexer.SELECT:
+
+			// Build SELECT statement from subsequent clauses up to the conflict or RETURNING clause
+			end := i
+			for end < len(clauses) && clauses[end].kind != lexer.ON && clauses[end].kind != lexer.RETURNING {
+				end++
+			}
+			stmt.Select = buildSelect(clauses[i:end])
+			i = end - 1
+
+		case lexer.ON:
+			stmt.Conflict = buildConflict(c.nodes)
+
+		case lexer.RETURNING:
+			stmt.Returning = buildList(body)
+		}
+	}
+	return stmt
+}
+
+// buildConflict builds the conflict clause of an INSERT statement, e.g. ON CONFLICT (id) DO UPDATE SET a = 1
+// WHERE b = 2
+func buildConflict(nodes []Node) *ConflictClause {
+	conflict := &ConflictClause{span: span{nodes}}
+
+	// Build conflict target given in parentheses
+	body := nodes[1:]
+	for i, node := range body {
+		if kindOf(node) == lexer.STARTPARENTHESIS {
+			conflict.Target = buildExprs(inner(node.(*Segment)))
+			body = body[i+1:]
+			break
+		}
+		if isToken(node, lexer.DO, lexer.SET, lexer.UPDATE) {
+			break
+		}
+	}
+
+	// Build action, the assignments start after SET of DO UPDATE SET or UPDATE of ON DUPLICATE KEY UPDATE
+	start, end := -1, len(body)
+	for i, node := range body {
+		switch {
+		case isToken(node, lexer.NOTHING):
+			conflict.DoNothing = true
+		case start < 0 && isToken(node, lexer.SET),
+			start < 0 && isToken(node, lexer.UPDATE) && i > 0 && isToken(body[i-1], lexer.KEY):
+			start = i + 1
+		case start >= 0 && isToken(node, lexer.WHERE):
+			end = i
+			conflict.Where = buildExpr(body[i+1:])
+		}
+		if end < len(body) {
+			break
+		}
+	}
+	if start >= 0 {
+		conflict.Set = buildAssignments(body[start:end])
+	}
+	return conflict
+}
+
+// buildAssignments builds the comma separated column assignments of a SET clause
+func buildAssignments(nodes []Node) []*Assignment {
+	var assignments []*Assignment
+	for _, item := range splitComma(nodes) {
+		assignment := &Assignment{span: span{item}}
+		for j, node := range item {
+			if isToken(node, lexer.COMPARATOR) {
+				assignment.Column = buildExpr(item[:j])
+				assignment.Value = buildExpr(item[j+1:])
+				break
+			}
+		}
+		assignments = append(assignments, assignment)
+	}
+	return assignments
+}
+
+// buildUpdate builds an UPDATE statement from its clauses
+func buildUpdate(clauses []*clause) *UpdateStmt {
+	stmt := &UpdateStmt{span: span{clauseNodes(clauses)}}
+	for _, c := range clauses {
+		body := c.nodes[1:]
+		switch c.kind {
+		case lexer.UPDATE:
+			if tables := buildTables(body); len(tables) > 0 {
+				stmt.Table = tables[0]
+			}
+		case lexer.SET:
+			stmt.Set = buildAssignments(body)
+		case lexer.FROM:
+			stmt.From = buildTables(body)
+		case lexer.JOIN, lexer.INNER, lexer.OUTER, lexer.LEFT, lexer.RIGHT, lexer.NATURAL, lexer.CROSS:
+			stmt.Joins = append(stmt.Joins, buildJoin(c.nodes))
+		case lexer.WHERE:
+			stmt.Where = buildExpr(body)
+		case lexer.RETURNING:
+			stmt.Returning = buildList(body)
+		}
+	}
+	return stmt
+}
+
+// buildDelete builds a DELETE statement from its clauses
+func buildDelete(clauses []*clause) *DeleteStmt {
+	stmt := &DeleteStmt{span: span{clauseNodes(clauses)}}
+	for _, c := range clauses {
+		body := c.nodes[1:]
+		switch c.kind {
+		case lexer.FROM:
+
+			// Cut off tables following USING, which are not deleted from
+			for j, node := range body {
+				if isToken(node, lexer.USING) {
+					body = body[:j]
+					break
+				}
+			}
+			if tables := buildTables(body); len(tables) > 0 {
+				stmt.Table = tables[0]
+			}
+
+		case lexer.WHERE:
+			stmt.Where = buildExpr(body)
+		case lexer.RETURNING:
+			stmt.Returning = buildList(body)
+		}
+	}
+	return stmt
+}
+
+// buildJoin builds a join clause, e.g. LEFT JOIN b ON a.id = b.id
+func buildJoin(nodes []Node) *JoinClause {
+	join := &JoinClause{span: span{nodes}}
+
+	// Build join type from keywords up to JOIN
+	i := 0
+	for i < len(nodes) {
+		token, ok := nodes[i].(*Token)
+		if !ok || !isJoinKeyword(token.Type) {
+			break
+		}
+		join.Type = strings.TrimSpace(join.Type + " " + strings.ToUpper(token.Value))
+		i++
+		if token.Type == lexer.JOIN {
+			break
+		}
+	}
+
+	// Build joined table and join condition
+	end := i
+	for end < len(nodes) && !isToken(nodes[end], lexer.ON, lexer.USING) {
+		end++
+	}
+	if tables := buildTables(nodes[i:end]); len(tables) > 0 {
+		join.Table = tables[0]
+	}
+	if end < len(nodes) && isToken(nodes[end], lexer.ON) {
+		join.On = buildExpr(nodes[end+1:])
+	} else if end+1 < len(nodes) && kindOf(nodes[end+1]) == lexer.STARTPARENTHESIS {
+		join.Using = buildExprs(inner(nodes[end+1].(*Segment)))
+	}
+
+	// Return join clause
+	return join
+}
+
+// buildTables builds a comma separated list of tables, each of them with an optional alias
+func buildTables(nodes []Node) []*Table {
+	var tables []*Table
+	for _, item := range splitComma(nodes) {
+		table := &Table{span: span{item}}
+		if aliased, ok := buildAliased(item).(*AliasedExpr); ok {
+			table.Expr, table.Alias = aliased.Expr, aliased.Alias
+		} else {
+			table.Expr = buildAliased(item)
+		}
+		tables = append(tables, table)
+	}
+	return tables
+}
+
+// buildList builds a comma separated list of expressions, each of them with an optional alias
+func buildList(nodes []Node) []Expr {
+	var exprs []Expr
+	for _, item := range splitComma(nodes) {
+		exprs = append(exprs, buildAliased(item))
+	}
+	return exprs
+}
+
+// buildExprs builds a comma separated list of expressions
+func buildExprs(nodes []Node) []Expr {
+	var exprs []Expr
+	for _, item := range splitComma(nodes) {
+		exprs = append(exprs, buildExpr(item))
+	}
+	return exprs
+}
+
+// buildAliased builds an expression with an optional alias, e.g. COUNT(*) AS total or users u
+func buildAliased(nodes []Node) Expr {
+	n := len(nodes)
+	switch {
+	case n > 2 && isToken(nodes[n-2], lexer.AS) && isToken(nodes[n-1], lexer.IDENT, lexer.QUOTED_IDENT):
+		return &AliasedExpr{span: span{nodes}, Expr: buildExpr(nodes[:n-2]), Alias: textOf(nodes[n-1])}
+	case n == 2 && isToken(nodes[1], lexer.IDENT, lexer.QUOTED_IDENT) &&
+		(isToken(nodes[0], lexer.IDENT, lexer.QUOTED_IDENT, lexer.STRING, lexer.NUMBER) || isGroup(nodes[0])):
+		return &AliasedExpr{span: span{nodes}, Expr: buildExpr(nodes[:1]), Alias: textOf(nodes[1])}
+	default:
+		return buildExpr(nodes)
+	}
+}
+
+// buildExpr builds an expression from a sequence of nodes. Logical combinations and comparisons are built as
+// BinaryExpr, any other sequence of multiple nodes as RawExpr.
+func buildExpr(nodes []Node) Expr {
+
+	// Flatten AND and OR segments, which only exist for formatting
+	nodes = flattenLogical(significant(nodes))
+	if len(nodes) == 0 {
+		return nil
+	}
+
+	// Build logical combinations, OR has a lower precedence than AND
+	for _, ttype := range []lexer.TokenType{lexer.OR, lexer.AND} {
+		if separators := logicalSeparators(nodes, ttype); len(separators) > 0 {
+			expr := buildExpr(nodes[:separators[0]])
+			for k, separator := range separators {
+				end := len(nodes)
+				if k+1 < len(separators) {
+					end = separators[k+1]
+				}
+				expr = &BinaryExpr{
+					span:  span{nodes[:end]},
+					Left:  expr,
+					Op:    strings.ToUpper(textOf(nodes[separator])),
+					Right: buildExpr(nodes[separator+1 : end]),
+				}
+			}
+			return expr
+		}
+	}
+
+	// Build negation
+	if len(nodes) > 1 && isToken(nodes[0], lexer.NOT) {
+		return &UnaryExpr{span: span{nodes}, Op: "NOT", X: buildExpr(nodes[1:])}
+	}
+
+	// Build comparison, e.g. a = 1, a NOT IN (1, 2) or a IS NOT NULL
+	for i := 1; i < len(nodes); i++ {
+		start, end := i, i+1
+		switch {
+		case isToken(nodes[i], lexer.COMPARATOR):
+		case isToken(nodes[i], lexer.IN, lexer.LIKE, lexer.ILIKE):
+			if isToken(nodes[i-1], lexer.NOT) {
+				start--
+			}
+		case isToken(nodes[i], lexer.IS):
+			if end < len(nodes) && isToken(nodes[end], lexer.NOT) {
+				end++
+			}
+		default:
+			continue
+		}
+		if start == 0 || end >= len(nodes) {
+			continue
+		}
+		var op []string
+		for _, node := range nodes[start:end] {
+			op = append(op, strings.ToUpper(textOf(node)))
+		}
+		return &BinaryExpr{
+			span:  span{nodes},
+			Left:  buildExpr(nodes[:start]),
+			Op:    strings.Join(op, " "),
+			Right: buildExpr(nodes[end:]),
+		}
+	}
+
+	// Build single value or group
+	if len(nodes) == 1 {
+		return buildPrimary(nodes[0])
+	}
+
+	// Build raw expression otherwise
+	return buildRaw(nodes)
+}
+
+// buildPrimary builds an expression from a single node, e.g. an identifier, a literal or a function call
+func buildPrimary(node Node) Expr {
+	switch n := node.(type) {
+	case *Token:
+		switch {
+		case n.Type == lexer.IDENT, n.Type == lexer.QUOTED_IDENT, n.Type == lexer.OPERATOR && n.Value == "*":
+			return buildIdent(n)
+		case n.Type == lexer.STRING, n.Type == lexer.NUMBER, n.Type == lexer.NULL:
+			return &Literal{span: span{[]Node{n}}, Value: textOf(n)}
+		case n.Type == lexer.PLACEHOLDER:
+			return &Placeholder{span: span{[]Node{n}}, Name: textOf(n)}
+		case n.Type == lexer.FUNCTIONKEYWORD:
+			return &FuncCall{span: span{[]Node{n}}, Name: textOf(n)}
+		}
+	case *Segment:
+		switch n.Kind() {
+		case lexer.FUNCTION:
+			return &FuncCall{span: span{[]Node{n}}, Name: textOf(n.Children[0]), Args: buildExprs(inner(n))}
+		case lexer.STARTPARENTHESIS:
+			content := significant(inner(n))
+			if len(content) > 0 && (kindOf(content[0]) == lexer.SELECT || kindOf(content[0]) == lexer.WITH) {
+				return &Subquery{span: span{[]Node{n}}, Stmt: buildStatement(content)}
+			}
+			return &ParenExpr{span: span{[]Node{n}}, List: buildExprs(content)}
+		case lexer.CASE, lexer.TYPE:
+			raw := buildRaw(n.Children)
+			raw.span = span{[]Node{n}}
+			return raw
+		}
+	}
+	return buildRaw([]Node{node})
+}
+
+// buildRaw builds a raw expression, converting nodes representing values or groups into typed expressions
+func buildRaw(nodes []Node) *RawExpr {
+	raw := &RawExpr{span: span{nodes}}
+	for _, node := range nodes {
+		switch {
+		case isGroup(node), isToken(node, lexer.IDENT, lexer.QUOTED_IDENT, lexer.STRING, lexer.NUMBER, lexer.NULL, lexer.PLACEHOLDER, lexer.FUNCTIONKEYWORD):
+			raw.Parts = append(raw.Parts, buildPrimary(node))
+		default:
+			raw.Parts = append(raw.Parts, node)
+		}
+	}
+	return raw
+}
+
+// buildIdent builds an identifier from a token
+func buildIdent(node Node) *Ident {
+	return &Ident{span: span{[]Node{node}}, Name: textOf(node)}
+}
+
+// flattenLogical splices the children of AND and OR segments into the sequence of nodes
+func flattenLogical(nodes []Node) []Node {
+	var flattened []Node
+	for _, node := range nodes {
+		if segment, ok := node.(*Segment); ok && (segment.Kind() == lexer.AND || segment.Kind() == lexer.OR) {
+			flattened = append(flattened, flattenLogical(significant(segment.Children))...)
+		} else {
+			flattened = append(flattened, node)
+		}
+	}
+	return flattened
+}
+
+// logicalSeparators returns the indexes of the logical operators of the given type. The AND of a BETWEEN
+// expression is not a logical operator, e.g. a BETWEEN 1 AND 2.
+func logicalSeparators(nodes []Node, ttype lexer.TokenType) []int {
+	var separators []int
+	var between bool
+	for i, node := range nodes {
+		switch {
+		case isToken(node, lexer.BETWEEN):
+			between = true
+		case isToken(node, lexer.AND) && between:
+			between = false
+		case isToken(node, ttype) && i > 0 && i < len(nodes)-1:
+			separators = append(separators, i)
+		}
+	}
+	return separators
+}
+
+// splitComma splits a sequence of nodes at commas
+func splitComma(nodes []Node) [][]Node {
+	var items [][]Node
+	var item []Node
+	for _, node := range significant(nodes) {
+		if isToken(node, lexer.COMMA) {
+			items = append(items, item)
+			item = nil
+			continue
+		}
+		item = append(item, node)
+	}
+	if len(item) > 0 {
+		items = append(items, item)
+	}
+	return items
+}
+
+// splitBefore splits a sequence of nodes in front of tokens of the given types
+func splitBefore(nodes []Node, ttypes ...lexer.TokenType) [][]Node {
+	var parts [][]Node
+	for _, node := range nodes {
+		if len(parts) == 0 || isToken(node, ttypes...) {
+			parts = append(parts, nil)
+		}
+		parts[len(parts)-1] = append(parts[len(parts)-1], node)
+	}
+	return parts
+}
+
+// skipBy removes the BY keyword following GROUP or ORDER
+func skipBy(nodes []Node) []Node {
+	if len(nodes) > 0 && isToken(nodes[0], lexer.BY) {
+		return nodes[1:]
+	}
+	return nodes
+}
+
+// inner returns the nodes of a segment within its parentheses, or between CASE and END
+func inner(segment *Segment) []Node {
+	nodes := segment.Children[1:]
+	if segment.Kind() == lexer.FUNCTION || segment.Kind() == lexer.TYPE {
+		if len(nodes) > 0 && isToken(nodes[0], lexer.STARTPARENTHESIS) {
+			nodes = nodes[1:]
+		}
+	}
+	if len(nodes) > 0 && isToken(nodes[len(nodes)-1], lexer.ENDPARENTHESIS, lexer.END) {
+		nodes = nodes[:len(nodes)-1]
+	}
+	return nodes
+}
+
+// clauseNodes returns the nodes of a sequence of clauses
+func clauseNodes(clauses []*clause) []Node {
+	var nodes []Node
+	for _, c := range clauses {
+		nodes = append(nodes, c.nodes...)
+	}
+	return nodes
+}
+
+// significant removes comments and optimizer hints from a sequence of nodes
+func significant(nodes []Node) []Node {
+	var filtered []Node
+	for _, node := range nodes {
+		if kind := kindOf(node); kind != lexer.COMMENT && kind != lexer.HINT {
+			filtered = append(filtered, node)
+		}
+	}
+	return filtered
+}
+
+// isClause returns true if segments of the given kind introduce a clause
+func isClause(kind lexer.TokenType) bool {
+	switch kind {
+	case lexer.SELECT, lexer.FROM, lexer.WHERE, lexer.GROUP, lexer.HAVING, lexer.ORDER,
+		lexer.LIMIT, lexer.OFFSET, lexer.FETCH, lexer.UNION, lexer.INTERSECT, lexer.EXCEPT,
+		lexer.SET, lexer.RETURNING, lexer.VALUES, lexer.INSERT, lexer.UPDATE, lexer.DELETE, lexer.WITH, lexer.ON, lexer.WINDOW:
+		return true
+	}
+	return isJoinKeyword(kind)
+}
+
+// isJoinKeyword returns true if the token type is a keyword of a join clause
+func isJoinKeyword(ttype lexer.TokenType) bool {
+	switch ttype {
+	case lexer.JOIN, lexer.INNER, lexer.OUTER, lexer.LEFT, lexer.RIGHT, lexer.NATURAL, lexer.CROSS:
+		return true
+	}
+	return false
+}
+
+// isGroup returns true if the node is a function call, parenthesis, CASE or type segment
+func isGroup(node Node) bool {
+	switch kindOf(node) {
+	case lexer.FUNCTION, lexer.STARTPARENTHESIS, lexer.CASE, lexer.TYPE:
+		_, ok := node.(*Segment)
+		return ok
+	}
+	return false
+}
+
+// isToken returns true if the node is a token of one of the given types
+func isToken(node Node, ttypes ...lexer.TokenType) bool {
+	token, ok := node.(*Token)
+	if !ok {
+		return false
+	}
+	for _, ttype := range ttypes {
+		if token.Type == ttype {
+			return true
+		}
+	}
+	return false
+}
+
+// kindOf returns the type of a token or the kind of a segment
+func kindOf(node Node) lexer.TokenType {
+	switch n := node.(type) {
+	case *Token:
+		return n.Type
+	case *Segment:
+		return n.Kind()
+	}
+	return 0
+}
+
+// textOf returns the original text of a token, or the one of the first token of a segment
+func textOf(node Node) string {
+	tokens := node.Tokens()
+	if len(tokens) == 0 {
+		return ""
+	}
+	if tokens[0].Raw != "" {
+		return tokens[0].Raw
+	}
+	return tokens[0].Value
+}
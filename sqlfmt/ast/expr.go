@@ -0,0 +1,85 @@
+package ast
+
+// Expr is implemented by all expression nodes
+type Expr interface {
+	Node
+	exprNode()
+}
+
+// Ident is a name of a column, table or other object, possibly qualified, e.g. "t.a", or the asterisk of "*"
+type Ident struct {
+	span
+	Name string // Name as it appeared in the input, including quotes
+}
+
+// Literal is a constant value, e.g. a string, number or NULL
+type Literal struct {
+	span
+	Value string // Value as it appeared in the input, including quotes
+}
+
+// Placeholder is a bind parameter, e.g. $1, ? or :name
+type Placeholder struct {
+	span
+	Name string
+}
+
+// FuncCall is a call of a function with arguments, e.g. COUNT(a)
+type FuncCall struct {
+	span
+	Name string
+	Args []Expr
+}
+
+// ParenExpr is a parenthesized expression or a list of them, e.g. (a + b) or (1, 2, 3)
+type ParenExpr struct {
+	span
+	List []Expr
+}
+
+// Subquery is a statement nested in parentheses, e.g. within IN (SELECT ...)
+type Subquery struct {
+	span
+	Stmt Statement
+}
+
+// BinaryExpr is a logical combination or comparison of two expressions, e.g. a = 1, a IN (1, 2) or x AND y.
+// The operator is upper-cased and may consist out of multiple words, e.g. "NOT IN" or "IS NOT".
+type BinaryExpr struct {
+	span
+	Left  Expr
+	Op    string
+	Right Expr
+}
+
+// UnaryExpr is a negation of an expression, e.g. NOT EXISTS (...)
+type UnaryExpr struct {
+	span
+	Op string
+	X  Expr
+}
+
+// AliasedExpr is an expression with an alias, e.g. COUNT(*) AS total
+type AliasedExpr struct {
+	span
+	Expr  Expr
+	Alias string
+}
+
+// RawExpr is an expression not covered by a dedicated node, such as arithmetic, CASE or casts. Its parts are
+// either typed expressions, e.g. identifiers or nested subqueries, or plain tokens, e.g. operators or keywords.
+type RawExpr struct {
+	span
+	Parts []Node
+}
+
+func (*Ident) exprNode()       {}
+func (*Literal) exprNode()     {}
+func (*Placeholder) exprNode() {}
+func (*FuncCall) exprNode()    {}
+func (*ParenExpr) exprNode()   {}
+func (*Subquery) exprNode()    {}
+func (*BinaryExpr) exprNode()  {}
+func (*UnaryExpr) exprNode()   {}
+func (*AliasedExpr) exprNode() {}
+func (*RawExpr) exprNode()     {}
@@ -0,0 +1,125 @@
+package ast
+
+// Statement is implemented by all statement nodes
+type Statement interface {
+	Node
+	stmtNode()
+}
+
+// SelectStmt is a SELECT statement, possibly combined with further ones, e.g. via UNION
+type SelectStmt struct {
+	span
+	With     *WithClause
+	Distinct bool
+	Columns  []Expr
+	From     []*Table
+	Joins    []*JoinClause
+	Where    Expr
+	GroupBy  []Expr
+	Having   Expr
+	OrderBy  []Expr
+	Limit    Expr
+	Offset   Expr
+	SetOp    *SetOp // Subsequent statement combined with this one, if any
+}
+
+// InsertStmt is an INSERT statement, inserting either VALUES or the result of a SELECT statement
+type InsertStmt struct {
+	span
+	With      *WithClause
+	Table     *Table
+	Columns   []Expr
+	Values    [][]Expr
+	Select    *SelectStmt
+	Conflict  *ConflictClause
+	Returning []Expr
+}
+
+// UpdateStmt is an UPDATE statement
+type UpdateStmt struct {
+	span
+	With      *WithClause
+	Table     *Table
+	Set       []*Assignment
+	From      []*Table
+	Joins     []*JoinClause
+	Where     Expr
+	Returning []Expr
+}
+
+// DeleteStmt is a DELETE statement
+type DeleteStmt struct {
+	span
+	With      *WithClause
+	Table     *Table
+	Where     Expr
+	Returning []Expr
+}
+
+// GenericStmt is any other statement, e.g. CREATE TABLE, which is only available as a segment tree
+type GenericStmt struct {
+	span
+	Nodes []Node
+}
+
+func (*SelectStmt) stmtNode()  {}
+func (*InsertStmt) stmtNode()  {}
+func (*UpdateStmt) stmtNode()  {}
+func (*DeleteStmt) stmtNode()  {}
+func (*GenericStmt) stmtNode() {}
+
+// WithClause is a list of common table expressions preceding a statement
+type WithClause struct {
+	span
+	Recursive bool
+	CTEs      []*CTE
+}
+
+// CTE is a common table expression, e.g. x AS (SELECT ...)
+type CTE struct {
+	span
+	Name    string
+	Columns []Expr
+	Stmt    Statement
+}
+
+// SetOp combines a statement with a subsequent one, e.g. UNION ALL SELECT ...
+type SetOp struct {
+	span
+	Op     string // Upper-cased operator, e.g. "UNION ALL" or "EXCEPT"
+	Select *SelectStmt
+}
+
+// Table is a table referenced by a statement, e.g. within FROM or JOIN clauses. It is either a table name as
+// *Ident, a derived table as *Subquery or a table function as *FuncCall.
+type Table struct {
+	span
+	Expr  Expr
+	Alias string
+}
+
+// JoinClause is a join of a table, e.g. LEFT JOIN b ON a.id = b.id
+type JoinClause struct {
+	span
+	Type  string // Upper-cased join keywords, e.g. "JOIN" or "LEFT OUTER JOIN"
+	Table *Table
+	On    Expr
+	Using []Expr
+}
+
+// ConflictClause is the action of an INSERT statement on conflicting rows, e.g. ON CONFLICT (id) DO UPDATE SET
+// a = excluded.a or ON DUPLICATE KEY UPDATE a = 1
+type ConflictClause struct {
+	span
+	Target    []Expr // Conflicting columns or expressions, if given in parentheses
+	DoNothing bool
+	Set       []*Assignment
+	Where     Expr
+}
+
+// Assignment is a column assignment within a SET clause, e.g. a = 1
+type Assignment struct {
+	span
+	Column Expr
+	Value  Expr
+}
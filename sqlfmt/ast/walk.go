@@ -0,0 +1,212 @@
+package ast
+
+import (
+	"maps"
+	"reflect"
+	"strings"
+)
+
+// Inspect traverses the typed nodes of a syntax tree in depth-first order. It calls f for each node, starting
+// with the given one. If f returns true, Inspect continues with the children of the node. Segments and plain
+// tokens, e.g. operators within a RawExpr, are not traversed.
+func Inspect(node Node, f func(Node) bool) {
+	if isNil(node) || !f(node) {
+		return
+	}
+	for _, child := range children(node) {
+		Inspect(child, f)
+	}
+}
+
+// Tables returns the names of all tables referenced by the node, including ones referenced by nested
+// statements, in order of appearance. Derived tables and table functions are not included, but the tables
+// they reference are. Neither are references to common table expressions defined by WITH clauses in scope.
+func Tables(node Node) []*Ident {
+	var tables []*Ident
+	collectTables(node, nil, &tables)
+	return tables
+}
+
+// collectTables appends the names of the tables referenced by the node to the list, except for the names of
+// common table expressions in scope. Common table expressions are in scope of the statement defining them and
+// of the subsequent ones of the same WITH clause, or of all of them, if the WITH clause is recursive.
+func collectTables(node Node, ctes map[string]bool, tables *[]*Ident) {
+	Inspect(node, func(n Node) bool {
+
+		// Add table, unless it references a common table expression
+		if table, ok := n.(*Table); ok {
+			if name, isIdent := table.Expr.(*Ident); isIdent && !ctes[nameKey(name.Name)] {
+				*tables = append(*tables, name)
+			}
+			return true
+		}
+
+		// Extend scope by common table expressions of the statement, before collecting its tables
+		with := withClause(n)
+		if with == nil {
+			return true
+		}
+		scope := maps.Clone(ctes)
+		if scope == nil {
+			scope = make(map[string]bool)
+		}
+		for _, cte := range with.CTEs {
+			scope[nameKey(cte.Name)] = scope[nameKey(cte.Name)] || with.Recursive
+		}
+		for _, cte := range with.CTEs {
+			collectTables(cte, scope, tables)
+			scope[nameKey(cte.Name)] = true
+		}
+		for _, child := range children(n) {
+			if child != Node(with) {
+				collectTables(child, scope, tables)
+			}
+		}
+		return false
+	})
+}
+
+// Conditions splits an expression into its conditions combined by AND, e.g. the conditions of a WHERE clause
+func Conditions(expr Expr) []Expr {
+	if isNil(expr) {
+		return nil
+	}
+	if binary, ok := expr.(*BinaryExpr); ok && binary.Op == "AND" {
+		return append(Conditions(binary.Left), Conditions(binary.Right)...)
+	}
+	return []Expr{expr}
+}
+
+// withClause returns the WITH clause of a statement node, or nil if there is none
+func withClause(node Node) *WithClause {
+	switch n := node.(type) {
+	case *SelectStmt:
+		return n.With
+	case *InsertStmt:
+		return n.With
+	case *UpdateStmt:
+		return n.With
+	case *DeleteStmt:
+		return n.With
+	}
+	return nil
+}
+
+// nameKey returns the key identifying a name regardless of its case, unless it is quoted, e.g. "x" for X
+func nameKey(name string) string {
+	if len(name) > 1 && strings.ContainsAny(name[:1], "\"`[") {
+		return name[1 : len(name)-1]
+	}
+	return strings.ToLower(name)
+}
+
+// children returns the direct child nodes of a typed node
+func children(node Node) []Node {
+	var nodes []Node
+	add := func(candidates ...Node) {
+		for _, candidate := range candidates {
+			if !isNil(candidate) {
+				nodes = append(nodes, candidate)
+			}
+		}
+	}
+	addExprs := func(exprs []Expr) {
+		for _, expr := range exprs {
+			add(expr)
+		}
+	}
+	addTables := func(tables []*Table) {
+		for _, table := range tables {
+			add(table)
+		}
+	}
+	addJoins := func(joins []*JoinClause) {
+		for _, join := range joins {
+			add(join)
+		}
+	}
+
+	// Collect children depending on node type
+	switch n := node.(type) {
+	case *SelectStmt:
+		add(n.With)
+		addExprs(n.Columns)
+		addTables(n.From)
+		addJoins(n.Joins)
+		add(n.Where)
+		addExprs(n.GroupBy)
+		add(n.Having)
+		addExprs(n.OrderBy)
+		add(n.Limit, n.Offset, n.SetOp)
+	case *InsertStmt:
+		add(n.With, n.Table)
+		addExprs(n.Columns)
+		for _, row := range n.Values {
+			addExprs(row)
+		}
+		add(n.Select, n.Conflict)
+		addExprs(n.Returning)
+	case *ConflictClause:
+		addExprs(n.Target)
+		for _, assignment := range n.Set {
+			add(assignment)
+		}
+		add(n.Where)
+	case *UpdateStmt:
+		add(n.With, n.Table)
+		for _, assignment := range n.Set {
+			add(assignment)
+		}
+		addTables(n.From)
+		addJoins(n.Joins)
+		add(n.Where)
+		addExprs(n.Returning)
+	case *DeleteStmt:
+		add(n.With, n.Table, n.Where)
+		addExprs(n.Returning)
+	case *WithClause:
+		for _, cte := range n.CTEs {
+			add(cte)
+		}
+	case *CTE:
+		addExprs(n.Columns)
+		add(n.Stmt)
+	case *SetOp:
+		add(n.Select)
+	case *Table:
+		add(n.Expr)
+	case *JoinClause:
+		add(n.Table, n.On)
+		addExprs(n.Using)
+	case *Assignment:
+		add(n.Column, n.Value)
+	case *FuncCall:
+		addExprs(n.Args)
+	case *ParenExpr:
+		addExprs(n.List)
+	case *Subquery:
+		add(n.Stmt)
+	case *BinaryExpr:
+		add(n.Left, n.Right)
+	case *UnaryExpr:
+		add(n.X)
+	case *AliasedExpr:
+		add(n.Expr)
+	case *RawExpr:
+		for _, part := range n.Parts {
+			if expr, ok := part.(Expr); ok {
+				add(expr)
+			}
+		}
+	}
+	return nodes
+}
+
+// isNil returns true if the node is nil, including nil pointers of typed nodes, e.g. a missing WITH clause
+func isNil(node Node) bool {
+	if node == nil {
+		return true
+	}
+	value := reflect.ValueOf(node)
+	return value.Kind() == reflect.Ptr && value.IsNil()
+}
@@ -0,0 +1,103 @@
+package ast
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestInspect(t *testing.T) {
+
+	// Build SELECT a FROM t WHERE b IN (SELECT c FROM u)
+	subquery := &SelectStmt{
+		Columns: []Expr{&Ident{Name: "c"}},
+		From:    []*Table{{Expr: &Ident{Name: "u"}}},
+	}
+	stmt := &SelectStmt{
+		Columns: []Expr{&Ident{Name: "a"}},
+		From:    []*Table{{Expr: &Ident{Name: "t"}}},
+		Where:   &BinaryExpr{Left: &Ident{Name: "b"}, Op: "IN", Right: &Subquery{Stmt: subquery}},
+	}
+
+	// Collect identifiers in order of appearance
+	var got []string
+	Inspect(stmt, func(node Node) bool {
+		if ident, ok := node.(*Ident); ok {
+			got = append(got, ident.Name)
+		}
+		return true
+	})
+	if want := []string{"a", "t", "b", "c", "u"}; !reflect.DeepEqual(want, got) {
+		t.Errorf("want %v, got %v", want, got)
+	}
+
+	// Verify that children are skipped if the function returns false
+	got = nil
+	Inspect(stmt, func(node Node) bool {
+		if ident, ok := node.(*Ident); ok {
+			got = append(got, ident.Name)
+		}
+		_, isSubquery := node.(*Subquery)
+		return !isSubquery
+	})
+	if want := []string{"a", "t", "b"}; !reflect.DeepEqual(want, got) {
+		t.Errorf("want %v, got %v", want, got)
+	}
+}
+
+func TestTables(t *testing.T) {
+
+	// Build SELECT * FROM t JOIN (SELECT * FROM u) x ON ...
+	stmt := &SelectStmt{
+		Columns: []Expr{&Ident{Name: "*"}},
+		From:    []*Table{{Expr: &Ident{Name: "t"}}},
+		Joins: []*JoinClause{{
+			Type:  "JOIN",
+			Table: &Table{Expr: &Subquery{Stmt: &SelectStmt{From: []*Table{{Expr: &Ident{Name: "u"}}}}}, Alias: "x"},
+		}},
+	}
+
+	// Verify that tables of nested statements are included, but not derived tables
+	var got []string
+	for _, table := range Tables(stmt) {
+		got = append(got, table.Name)
+	}
+	if want := []string{"t", "u"}; !reflect.DeepEqual(want, got) {
+		t.Errorf("want %v, got %v", want, got)
+	}
+}
+
+func TestTables_CommonTableExpressions(t *testing.T) {
+
+	// Build WITH x AS (SELECT * FROM t) SELECT * FROM x JOIN u ON ...
+	stmt := &SelectStmt{
+		With: &WithClause{CTEs: []*CTE{{
+			Name: "x",
+			Stmt: &SelectStmt{Columns: []Expr{&Ident{Name: "*"}}, From: []*Table{{Expr: &Ident{Name: "t"}}}},
+		}}},
+		Columns: []Expr{&Ident{Name: "*"}},
+		From:    []*Table{{Expr: &Ident{Name: "X"}}},
+		Joins:   []*JoinClause{{Type: "JOIN", Table: &Table{Expr: &Ident{Name: "u"}}}},
+	}
+
+	// Verify that references to common table expressions are not included
+	var got []string
+	for _, table := range Tables(stmt) {
+		got = append(got, table.Name)
+	}
+	if want := []string{"t", "u"}; !reflect.DeepEqual(want, got) {
+		t.Errorf("want %v, got %v", want, got)
+	}
+}
+
+func TestConditions(t *testing.T) {
+	a := &BinaryExpr{Left: &Ident{Name: "a"}, Op: "=", Right: &Literal{Value: "1"}}
+	b := &BinaryExpr{Left: &Ident{Name: "b"}, Op: "OR", Right: &Ident{Name: "c"}}
+	d := &Ident{Name: "d"}
+	expr := &BinaryExpr{Left: &BinaryExpr{Left: a, Op: "AND", Right: b}, Op: "AND", Right: d}
+	if got := Conditions(expr); !reflect.DeepEqual([]Expr{a, b, d}, got) {
+		t.Errorf("want conditions a, b and d, got %v", got)
+	}
+	if got := Conditions(nil); got != nil {
+		t.Errorf("want no conditions, got %v", got)
+	}
+}
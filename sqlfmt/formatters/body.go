@@ -0,0 +1,157 @@
+package formatters
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/noneymous/go-sqlfmt/sqlfmt/lexer"
+)
+
+// Body group formatter
+// The body group formatter writes the dollar-quoted body of a routine, whose statements could be parsed. The
+// delimiters enclose the lines of the body, which are indented according to its block structure, e.g.:
+//
+//	$$
+//	BEGIN
+//	  IF a > 1 THEN
+//	    RETURN a;
+//	  END IF;
+//	END;
+//	$$
+type Body struct {
+	Elements    []Formatter // Lines of the body
+	Tag         string      // Delimiter of the dollar-quoted string, e.g. "$$" or "$body$"
+	IndentLevel int
+	*Options    // Options used later to format element
+}
+
+// Format component accordingly with necessary indents, newlines,...
+func (formatter *Body) Format(buf *bytes.Buffer, parent []Formatter, parentIdx int) error {
+
+	// Prepare short variables for better visibility
+	var INDENT = formatter.Indent
+	var NEWLINE = formatter.Newline
+	var WHITESPACE = formatter.Whitespace
+
+	// Write opening delimiter on the line of the routine
+	buf.WriteString(fmt.Sprintf("%s%s", WHITESPACE, formatter.Tag))
+
+	// Iterate and write lines to the buffer. Each line starts a new line itself.
+	for i, el := range formatter.Elements {
+		_ = el.Format(buf, formatter.Elements, i)
+	}
+
+	// Write closing delimiter on a line of its own
+	buf.WriteString(fmt.Sprintf("%s%s%s", NEWLINE, strings.Repeat(INDENT, formatter.IndentLevel), formatter.Tag))
+
+	// Return nil and continue with parent Formatter
+	return nil
+}
+
+// AddIndent increments indentation level by the given amount
+func (formatter *Body) AddIndent(lev int) {
+	formatter.IndentLevel += lev
+
+	// Iterate and increase indent of child elements too
+	for _, el := range formatter.Elements {
+		el.AddIndent(lev)
+	}
+}
+
+// BodyLine group formatter
+// The body line group formatter writes a single line of a routine body, e.g. a PL/pgSQL statement, the header
+// of a control structure or an embedded SQL statement. Embedded SQL statements are nested elements, which are
+// laid out like standalone ones, e.g.:
+//
+//	RETURN QUERY
+//	SELECT
+//	  a,
+//	  b
+//	FROM t;
+type BodyLine struct {
+	Elements    []Formatter
+	IndentLevel int
+	*Options    // Options used later to format element
+}
+
+// Format component accordingly with necessary indents, newlines,...
+func (formatter *BodyLine) Format(buf *bytes.Buffer, parent []Formatter, parentIdx int) error {
+
+	// Iterate and write elements to the buffer. Recursively step into nested elements.
+	var previousToken Token
+	for i, el := range formatter.Elements {
+
+		// Write element or recursively call its Format function
+		if token, ok := el.(Token); ok {
+			formatter.writeBodyLine(buf, token, previousToken, i)
+		} else {
+
+			// Recursively format nested elements. Most of them start a new line themselves, others, like UPDATE
+			// statements, continue the current one.
+			elBuf := &bytes.Buffer{}
+			_ = el.Format(elBuf, formatter.Elements, i)
+			if !strings.HasPrefix(elBuf.String(), formatter.Newline) {
+				if i == 0 {
+					buf.WriteString(formatter.Newline + strings.Repeat(formatter.Indent, formatter.IndentLevel))
+				} else {
+					buf.WriteString(formatter.Whitespace)
+				}
+			}
+			buf.Write(elBuf.Bytes())
+		}
+
+		// Remember last Token element
+		if token, ok := el.(Token); ok {
+			previousToken = token
+		} else {
+			previousToken = Token{}
+		}
+	}
+
+	// Return nil and continue with parent Formatter
+	return nil
+}
+
+// AddIndent increments indentation level by the given amount
+func (formatter *BodyLine) AddIndent(lev int) {
+	formatter.IndentLevel += lev
+
+	// Iterate and increase indent of child elements too
+	for _, el := range formatter.Elements {
+		el.AddIndent(lev)
+	}
+}
+
+func (formatter *BodyLine) writeBodyLine(buf *bytes.Buffer, token, previousToken Token, position int) {
+
+	// Prepare short variables for better visibility
+	var INDENT = formatter.Indent
+	var NEWLINE = formatter.Newline
+	var WHITESPACE = formatter.Whitespace
+
+	// Write element
+	switch {
+
+	// Start line and any token following a line comment on a new line
+	case position == 0, previousToken.IsLineComment():
+		buf.WriteString(fmt.Sprintf("%s%s%s", NEWLINE, strings.Repeat(INDENT, formatter.IndentLevel), token.Value))
+
+	// Write arguments of function calls and subscripts directly after the name, like a function call
+	case token.Type == lexer.STARTPARENTHESIS && (previousToken.Type == lexer.FUNCTION ||
+		previousToken.Type == lexer.TYPE || previousToken.Type == lexer.IDENT):
+		buf.WriteString(token.Value)
+	case token.Type == lexer.ENDPARENTHESIS, previousToken.Type == lexer.STARTPARENTHESIS:
+		buf.WriteString(token.Value)
+
+	// Write comma, semicolon and cast tokens without whitespace
+	case token.Type == lexer.COMMA, token.Type == lexer.SEMICOLON:
+		buf.WriteString(token.Value)
+	case strings.HasPrefix(token.Value, "::"):
+		buf.WriteString(token.Value)
+
+	// Write common token values
+	default:
+		buf.WriteString(fmt.Sprintf("%s%s", WHITESPACE, token.Value))
+	}
+}
@@ -0,0 +1,78 @@
+package formatters
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/noneymous/go-sqlfmt/sqlfmt/lexer"
+)
+
+func TestFormatBody(t *testing.T) {
+	options := DefaultOptions()
+	tests := []struct {
+		name        string
+		tokenSource []Formatter
+		want        string
+	}{
+		{
+			name: "normalcase",
+			tokenSource: []Formatter{
+				&BodyLine{Options: options, Elements: []Formatter{
+					Token{Options: options, Token: lexer.Token{Type: lexer.BEGIN, Value: "BEGIN"}},
+				}},
+				&BodyLine{Options: options, IndentLevel: 1, Elements: []Formatter{
+					Token{Options: options, Token: lexer.Token{Type: lexer.IF, Value: "IF"}},
+					Token{Options: options, Token: lexer.Token{Type: lexer.IDENT, Value: "a"}},
+					Token{Options: options, Token: lexer.Token{Type: lexer.COMPARATOR, Value: ">"}},
+					Token{Options: options, Token: lexer.Token{Type: lexer.IDENT, Value: "1"}},
+					Token{Options: options, Token: lexer.Token{Type: lexer.THEN, Value: "THEN"}},
+				}},
+				&BodyLine{Options: options, IndentLevel: 2, Elements: []Formatter{
+					Token{Options: options, Token: lexer.Token{Type: lexer.IDENT, Value: "v"}},
+					Token{Options: options, Token: lexer.Token{Type: lexer.COMPARATOR, Value: ":="}},
+					Token{Options: options, Token: lexer.Token{Type: lexer.IDENT, Value: "COALESCE"}},
+					Token{Options: options, Token: lexer.Token{Type: lexer.STARTPARENTHESIS, Value: "("}},
+					Token{Options: options, Token: lexer.Token{Type: lexer.IDENT, Value: "a"}},
+					Token{Options: options, Token: lexer.Token{Type: lexer.COMMA, Value: ","}},
+					Token{Options: options, Token: lexer.Token{Type: lexer.IDENT, Value: "0"}},
+					Token{Options: options, Token: lexer.Token{Type: lexer.ENDPARENTHESIS, Value: ")"}},
+					Token{Options: options, Token: lexer.Token{Type: lexer.SEMICOLON, Value: ";"}},
+				}},
+				&BodyLine{Options: options, IndentLevel: 2, Elements: []Formatter{
+					Token{Options: options, Token: lexer.Token{Type: lexer.IDENT, Value: "RETURN"}},
+					Token{Options: options, Token: lexer.Token{Type: lexer.IDENT, Value: "QUERY"}},
+					&Select{Options: options, IndentLevel: 2, Elements: []Formatter{
+						Token{Options: options, Token: lexer.Token{Type: lexer.SELECT, Value: "SELECT"}},
+						Token{Options: options, Token: lexer.Token{Type: lexer.IDENT, Value: "v"}},
+					}},
+					Token{Options: options, Token: lexer.Token{Type: lexer.SEMICOLON, Value: ";"}},
+				}},
+				&BodyLine{Options: options, IndentLevel: 1, Elements: []Formatter{
+					Token{Options: options, Token: lexer.Token{Type: lexer.END, Value: "END"}},
+					Token{Options: options, Token: lexer.Token{Type: lexer.IF, Value: "IF"}},
+					Token{Options: options, Token: lexer.Token{Type: lexer.SEMICOLON, Value: ";"}},
+				}},
+				&BodyLine{Options: options, Elements: []Formatter{
+					Token{Options: options, Token: lexer.Token{Type: lexer.END, Value: "END"}},
+					Token{Options: options, Token: lexer.Token{Type: lexer.SEMICOLON, Value: ";"}},
+				}},
+			},
+			want: " $body$\nBEGIN\n  IF a > 1 THEN\n    v := COALESCE(a, 0);\n    RETURN QUERY\n    SELECT\n      v;\n  END IF;\nEND;\n$body$",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			el := &Body{Options: options, Tag: "$body$", Elements: tt.tokenSource}
+
+			_ = el.Format(buf, nil, 0)
+			got := buf.String()
+			if tt.want != got {
+				t.Errorf("\n=======================\n=== WANT =============>\n%s\n=======================\n=== GOT ==============>\n%s\n=======================", tt.want, got)
+			} else {
+				fmt.Println(fmt.Sprintf("%s\n%s", got, "========================================================================"))
+			}
+		})
+	}
+}
@@ -0,0 +1,160 @@
+package formatters
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/noneymous/go-sqlfmt/sqlfmt/lexer"
+)
+
+// Conflict group formatter
+// The conflict group formatter writes the ON CONFLICT clause of PostgreSQL and SQLite or the ON DUPLICATE KEY
+// UPDATE clause of MySQL. Both are laid out alike, the assignments of the update action follow SET of DO UPDATE
+// SET or UPDATE of ON DUPLICATE KEY UPDATE like they follow SET of UPDATE statements, e.g.:
+//
+//	ON CONFLICT (id) DO UPDATE SET
+//	  a = excluded.a,
+//	  b = excluded.b,
+//	  c = excluded.c
+//	WHERE t.d = 1
+type Conflict struct {
+	Elements    []Formatter
+	IndentLevel int
+	*Options    // Options used later to format element
+}
+
+// Format component accordingly with necessary indents, newlines,...
+func (formatter *Conflict) Format(buf *bytes.Buffer, parent []Formatter, parentIdx int) error {
+
+	// Preprocess punctuation and enrich with surrounding information
+	elements, err := processPunctuation(formatter.Elements, formatter.Whitespace)
+	if err != nil {
+		return err
+	}
+
+	// Determine the range of assignments of the update action. They follow SET of DO UPDATE SET or UPDATE of
+	// ON DUPLICATE KEY UPDATE and end at the condition of the update action, if there is one.
+	var assignmentsStart, assignmentsEnd = len(elements), len(elements)
+	var previousToken Token
+	for i, el := range elements {
+		if token, ok := el.(Token); ok {
+			switch {
+			case token.Type == lexer.SET, token.Type == lexer.UPDATE && previousToken.Type == lexer.KEY:
+				assignmentsStart = i
+			case token.Type == lexer.WHERE && i > assignmentsStart:
+				assignmentsEnd = i
+			}
+			previousToken = token
+		}
+	}
+
+	// Check how many assignments there are. Linebreak if too many
+	var clauses = 0
+	for _, el := range elements[min(assignmentsStart, assignmentsEnd):assignmentsEnd] {
+		switch t := el.(type) {
+		case Token:
+			if t.IsClauseValue() {
+				clauses++
+			} else if t.Type == lexer.COMMENT {
+				clauses = 999 // Format like if there were many clauses to make space for comments
+			}
+		}
+	}
+
+	// Iterate and write elements to the buffer. Recursively step into nested elements.
+	var hasMany = clauses > maxSetClausesPerLine
+	previousToken = Token{}
+	for i, el := range elements {
+
+		// Write element or recursively call its Format function
+		if token, ok := el.(Token); ok {
+			formatter.writeConflict(buf, token, previousToken, i, assignmentsStart, assignmentsEnd, hasMany)
+		} else {
+
+			// Increment indent, if assignments should be written into new lines
+			if i > assignmentsStart && i < assignmentsEnd && hasMany {
+				el.AddIndent(1)
+			}
+
+			// Recursively format nested elements
+			_ = el.Format(buf, elements, i)
+		}
+
+		// Remember last Token element
+		if token, ok := el.(Token); ok {
+			previousToken = token
+		} else {
+			previousToken = Token{}
+		}
+	}
+
+	// Return nil and continue with parent Formatter
+	return nil
+}
+
+// AddIndent increments indentation level by the given amount
+func (formatter *Conflict) AddIndent(lev int) {
+	formatter.IndentLevel += lev
+
+	// Preprocess punctuation and enrich with surrounding information
+	elements, err := processPunctuation(formatter.Elements, formatter.Whitespace)
+	if err != nil {
+		elements = formatter.Elements
+	}
+
+	// Iterate and increase indent of child elements too
+	for _, el := range elements {
+		el.AddIndent(lev)
+	}
+}
+
+func (formatter *Conflict) writeConflict(
+	buf *bytes.Buffer,
+	token,
+	previousToken Token,
+	position,
+	assignmentsStart,
+	assignmentsEnd int,
+	hasMany bool,
+) {
+
+	// Prepare short variables for better visibility
+	var INDENT = formatter.Indent
+	var NEWLINE = formatter.Newline
+	var WHITESPACE = formatter.Whitespace
+
+	// Any token following a line comment must start on a new line, assignments indented
+	if previousToken.IsLineComment() {
+		if position > assignmentsStart && position < assignmentsEnd {
+			buf.WriteString(fmt.Sprintf("%s%s%s%s", NEWLINE, strings.Repeat(INDENT, formatter.IndentLevel), INDENT, token.Value))
+		} else {
+			buf.WriteString(fmt.Sprintf("%s%s%s", NEWLINE, strings.Repeat(INDENT, formatter.IndentLevel), token.Value))
+		}
+		return
+	}
+
+	// Write element
+	switch {
+
+	// Start clause and the condition of the update action on new lines
+	case position == 0, position == assignmentsEnd:
+		buf.WriteString(fmt.Sprintf("%s%s%s", NEWLINE, strings.Repeat(INDENT, formatter.IndentLevel), token.Value))
+
+	// Write assignments on lines of their own, if there are many
+	case hasMany && position == assignmentsStart+1 && token.Type != lexer.COMMENT,
+		hasMany && position > assignmentsStart && position < assignmentsEnd && previousToken.Type == lexer.COMMA &&
+			token.Type != lexer.COMMENT:
+		buf.WriteString(fmt.Sprintf("%s%s%s%s", NEWLINE, strings.Repeat(INDENT, formatter.IndentLevel), INDENT, token.Value))
+
+	// Write comma token without whitespace
+	case token.Type == lexer.COMMA:
+		buf.WriteString(fmt.Sprintf("%s", token.Value))
+	case strings.HasPrefix(token.Value, "::"):
+		buf.WriteString(fmt.Sprintf("%s", token.Value))
+
+	// Write common token values
+	default:
+		buf.WriteString(fmt.Sprintf("%s%s", WHITESPACE, token.Value))
+	}
+}
@@ -0,0 +1,87 @@
+package formatters
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/noneymous/go-sqlfmt/sqlfmt/lexer"
+)
+
+func TestFormatConflict(t *testing.T) {
+	options := DefaultOptions()
+	tests := []struct {
+		name        string
+		tokenSource []Formatter
+		want        string
+	}{
+		{
+			name: "normalcase",
+			tokenSource: []Formatter{
+				Token{Options: options, Token: lexer.Token{Type: lexer.ON, Value: "ON"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.CONFLICT, Value: "CONFLICT"}},
+				&Parenthesis{Options: options, Elements: []Formatter{
+					Token{Options: options, Token: lexer.Token{Type: lexer.STARTPARENTHESIS, Value: "("}},
+					Token{Options: options, Token: lexer.Token{Type: lexer.IDENT, Value: "id"}},
+					Token{Options: options, Token: lexer.Token{Type: lexer.ENDPARENTHESIS, Value: ")"}},
+				}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.DO, Value: "DO"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.UPDATE, Value: "UPDATE"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.SET, Value: "SET"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.IDENT, Value: "a"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.COMPARATOR, Value: "="}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.IDENT, Value: "excluded.a"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.COMMA, Value: ","}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.IDENT, Value: "b"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.COMPARATOR, Value: "="}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.IDENT, Value: "excluded.b"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.COMMA, Value: ","}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.IDENT, Value: "c"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.COMPARATOR, Value: "="}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.IDENT, Value: "excluded.c"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.WHERE, Value: "WHERE"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.IDENT, Value: "t.d"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.COMPARATOR, Value: "="}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.IDENT, Value: "1"}},
+			},
+			want: "\nON CONFLICT (id) DO UPDATE SET\n  a = excluded.a,\n  b = excluded.b,\n  c = excluded.c\nWHERE t.d = 1",
+		},
+		{
+			name: "do nothing",
+			tokenSource: []Formatter{
+				Token{Options: options, Token: lexer.Token{Type: lexer.ON, Value: "ON"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.CONFLICT, Value: "CONFLICT"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.DO, Value: "DO"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.NOTHING, Value: "NOTHING"}},
+			},
+			want: "\nON CONFLICT DO NOTHING",
+		},
+		{
+			name: "on duplicate key update",
+			tokenSource: []Formatter{
+				Token{Options: options, Token: lexer.Token{Type: lexer.ON, Value: "ON"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.DUPLICATE, Value: "DUPLICATE"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.KEY, Value: "KEY"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.UPDATE, Value: "UPDATE"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.IDENT, Value: "a"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.COMPARATOR, Value: "="}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.IDENT, Value: "1"}},
+			},
+			want: "\nON DUPLICATE KEY UPDATE a = 1",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			el := &Conflict{Options: options, Elements: tt.tokenSource}
+
+			_ = el.Format(buf, nil, 0)
+			got := buf.String()
+			if tt.want != got {
+				t.Errorf("\n=======================\n=== WANT =============>\n%s\n=======================\n=== GOT ==============>\n%s\n=======================", tt.want, got)
+			} else {
+				fmt.Println(fmt.Sprintf("%s\n%s", got, "========================================================================"))
+			}
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package formatters
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/noneymous/go-sqlfmt/sqlfmt/lexer"
+)
+
+// CreateIndex group formatter
+// The create index group formatter writes the indexed table, method and columns of a CREATE INDEX statement on
+// the first line. Included columns, storage parameters, the tablespace and the predicate of a partial index are
+// written on lines of their own, e.g.:
+//
+//	CREATE UNIQUE INDEX CONCURRENTLY idx ON t USING btree (a, LOWER(b))
+//	INCLUDE (c)
+//	WITH (fillfactor = 70)
+//	TABLESPACE fast
+//	WHERE d IS NOT NULL
+type CreateIndex struct {
+	Elements    []Formatter
+	IndentLevel int
+	*Options    // Options used later to format element
+}
+
+// Format component accordingly with necessary indents, newlines,...
+func (formatter *CreateIndex) Format(buf *bytes.Buffer, parent []Formatter, parentIdx int) error {
+
+	// Preprocess punctuation and enrich with surrounding information
+	elements, err := processPunctuation(formatter.Elements, formatter.Whitespace)
+	if err != nil {
+		return err
+	}
+
+	// Iterate and write elements to the buffer. Recursively step into nested elements.
+	var previousToken Token
+	var depth = 0 // Depth of parentheses of the column list or options, nested parentheses are separate elements
+	for i, el := range elements {
+
+		// Write element or recursively call its Format function
+		if token, ok := el.(Token); ok {
+			formatter.writeCreateIndex(buf, token, previousToken, i, depth)
+			switch token.Type {
+			case lexer.STARTPARENTHESIS:
+				depth++
+			case lexer.ENDPARENTHESIS:
+				depth--
+			}
+		} else if previousToken.Type == lexer.STARTPARENTHESIS {
+
+			// Write first element within parenthesis without whitespace, e.g. a function call or expression
+			elBuf := &bytes.Buffer{}
+			_ = el.Format(elBuf, elements, i)
+			buf.WriteString(strings.TrimPrefix(elBuf.String(), formatter.Whitespace))
+		} else {
+
+			// Recursively format nested elements
+			_ = el.Format(buf, elements, i)
+		}
+
+		// Remember last Token element
+		if token, ok := el.(Token); ok {
+			previousToken = token
+		} else {
+			previousToken = Token{}
+		}
+	}
+
+	// Return nil and continue with parent Formatter
+	return nil
+}
+
+// AddIndent increments indentation level by the given amount
+func (formatter *CreateIndex) AddIndent(lev int) {
+	formatter.IndentLevel += lev
+
+	// Preprocess punctuation and enrich with surrounding information
+	elements, err := processPunctuation(formatter.Elements, formatter.Whitespace)
+	if err != nil {
+		elements = formatter.Elements
+	}
+
+	// Iterate and increase indent of child elements too
+	for _, el := range elements {
+		el.AddIndent(lev)
+	}
+}
+
+func (formatter *CreateIndex) writeCreateIndex(buf *bytes.Buffer, token, previousToken Token, position int, depth int) {
+
+	// Prepare short variables for better visibility
+	var INDENT = formatter.Indent
+	var NEWLINE = formatter.Newline
+	var WHITESPACE = formatter.Whitespace
+
+	// Write element
+	switch {
+
+	// Start statement, its options and any token following a line comment on a new line
+	case position == 0,
+		depth == 0 && (token.Type == lexer.INCLUDE || token.Type == lexer.WITH || token.Type == lexer.TABLESPACE),
+		previousToken.IsLineComment():
+		buf.WriteString(fmt.Sprintf("%s%s%s", NEWLINE, strings.Repeat(INDENT, formatter.IndentLevel), token.Value))
+
+	// Write parentheses of the column list and options like the ones of a function call
+	case token.Type == lexer.STARTPARENTHESIS:
+		buf.WriteString(fmt.Sprintf("%s%s", WHITESPACE, token.Value))
+	case token.Type == lexer.ENDPARENTHESIS, previousToken.Type == lexer.STARTPARENTHESIS:
+		buf.WriteString(token.Value)
+
+	// Write comma token without whitespace
+	case token.Type == lexer.COMMA:
+		buf.WriteString(token.Value)
+	case strings.HasPrefix(token.Value, "::"):
+		buf.WriteString(token.Value)
+
+	// Write common token values
+	default:
+		buf.WriteString(fmt.Sprintf("%s%s", WHITESPACE, token.Value))
+	}
+}
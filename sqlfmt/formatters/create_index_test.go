@@ -0,0 +1,87 @@
+package formatters
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/noneymous/go-sqlfmt/sqlfmt/lexer"
+)
+
+func TestFormatCreateIndex(t *testing.T) {
+	options := DefaultOptions()
+	tests := []struct {
+		name        string
+		tokenSource []Formatter
+		want        string
+	}{
+		{
+			name: "normalcase",
+			tokenSource: []Formatter{
+				Token{Options: options, Token: lexer.Token{Type: lexer.CREATE, Value: "CREATE"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.UNIQUE, Value: "UNIQUE"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.INDEX, Value: "INDEX"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.IDENT, Value: "idx"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.ON, Value: "ON"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.IDENT, Value: "t"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.USING, Value: "USING"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.IDENT, Value: "btree"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.STARTPARENTHESIS, Value: "("}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.IDENT, Value: "a"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.COMMA, Value: ","}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.IDENT, Value: "b"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.ENDPARENTHESIS, Value: ")"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.INCLUDE, Value: "INCLUDE"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.STARTPARENTHESIS, Value: "("}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.IDENT, Value: "c"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.ENDPARENTHESIS, Value: ")"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.WITH, Value: "WITH"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.STARTPARENTHESIS, Value: "("}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.IDENT, Value: "fillfactor"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.COMPARATOR, Value: "="}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.IDENT, Value: "70"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.ENDPARENTHESIS, Value: ")"}},
+				&Where{Options: options, Elements: []Formatter{
+					Token{Options: options, Token: lexer.Token{Type: lexer.WHERE, Value: "WHERE"}},
+					Token{Options: options, Token: lexer.Token{Type: lexer.IDENT, Value: "d"}},
+					Token{Options: options, Token: lexer.Token{Type: lexer.IS, Value: "IS"}},
+					Token{Options: options, Token: lexer.Token{Type: lexer.NOT, Value: "NOT"}},
+					Token{Options: options, Token: lexer.Token{Type: lexer.NULL, Value: "NULL"}},
+				}},
+			},
+			want: "\nCREATE UNIQUE INDEX idx ON t USING btree (a, b)\nINCLUDE (c)\nWITH (fillfactor = 70)\nWHERE d IS NOT NULL",
+		},
+		{
+			name: "function first",
+			tokenSource: []Formatter{
+				Token{Options: options, Token: lexer.Token{Type: lexer.CREATE, Value: "CREATE"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.INDEX, Value: "INDEX"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.ON, Value: "ON"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.IDENT, Value: "t"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.STARTPARENTHESIS, Value: "("}},
+				&Function{Options: options, Elements: []Formatter{
+					Token{Options: options, Token: lexer.Token{Type: lexer.FUNCTION, Value: "LOWER"}},
+					Token{Options: options, Token: lexer.Token{Type: lexer.STARTPARENTHESIS, Value: "("}},
+					Token{Options: options, Token: lexer.Token{Type: lexer.IDENT, Value: "a"}},
+					Token{Options: options, Token: lexer.Token{Type: lexer.ENDPARENTHESIS, Value: ")"}},
+				}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.ENDPARENTHESIS, Value: ")"}},
+			},
+			want: "\nCREATE INDEX ON t (LOWER(a))",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			el := &CreateIndex{Options: options, Elements: tt.tokenSource}
+
+			_ = el.Format(buf, nil, 0)
+			got := buf.String()
+			if tt.want != got {
+				t.Errorf("\n=======================\n=== WANT =============>\n%s\n=======================\n=== GOT ==============>\n%s\n=======================", tt.want, got)
+			} else {
+				fmt.Println(fmt.Sprintf("%s\n%s", got, "========================================================================"))
+			}
+		})
+	}
+}
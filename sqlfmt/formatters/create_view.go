@@ -0,0 +1,120 @@
+package formatters
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/noneymous/go-sqlfmt/sqlfmt/lexer"
+)
+
+// CreateView group formatter
+// The create view group formatter writes the head of a CREATE [OR REPLACE] [MATERIALIZED] VIEW statement on one
+// line, followed by the query formatted like a standalone one. Options following the query start a new line,
+// e.g.:
+//
+//	CREATE MATERIALIZED VIEW v AS
+//	SELECT
+//	  a,
+//	  b
+//	FROM t
+//	WITH NO DATA
+type CreateView struct {
+	Elements    []Formatter
+	IndentLevel int
+	*Options    // Options used later to format element
+}
+
+// Format component accordingly with necessary indents, newlines,...
+func (formatter *CreateView) Format(buf *bytes.Buffer, parent []Formatter, parentIdx int) error {
+
+	// Preprocess punctuation and enrich with surrounding information
+	elements, err := processPunctuation(formatter.Elements, formatter.Whitespace)
+	if err != nil {
+		return err
+	}
+
+	// Iterate and write elements to the buffer. Recursively step into nested elements.
+	var previousToken Token
+	var isQuery = false   // Whether the query of the view has started
+	var isOptions = false // Whether the options following the query have started
+	for i, el := range elements {
+
+		// Write element or recursively call its Format function
+		if token, ok := el.(Token); ok {
+			if isQuery && token.Type == lexer.WITH {
+				isOptions = true
+			}
+			formatter.writeCreateView(buf, token, previousToken, i, isOptions)
+		} else {
+
+			// Remember start of query, which follows AS
+			if previousToken.Type == lexer.AS {
+				isQuery = true
+			}
+
+			// Recursively format nested elements
+			_ = el.Format(buf, elements, i)
+		}
+
+		// Remember last Token element
+		if token, ok := el.(Token); ok {
+			previousToken = token
+		} else {
+			previousToken = Token{}
+		}
+	}
+
+	// Return nil and continue with parent Formatter
+	return nil
+}
+
+// AddIndent increments indentation level by the given amount
+func (formatter *CreateView) AddIndent(lev int) {
+	formatter.IndentLevel += lev
+
+	// Preprocess punctuation and enrich with surrounding information
+	elements, err := processPunctuation(formatter.Elements, formatter.Whitespace)
+	if err != nil {
+		elements = formatter.Elements
+	}
+
+	// Iterate and increase indent of child elements too
+	for _, el := range elements {
+		el.AddIndent(lev)
+	}
+}
+
+func (formatter *CreateView) writeCreateView(buf *bytes.Buffer, token, previousToken Token, position int, isOptions bool) {
+
+	// Prepare short variables for better visibility
+	var INDENT = formatter.Indent
+	var NEWLINE = formatter.Newline
+	var WHITESPACE = formatter.Whitespace
+
+	// Options following the query are keywords, although not all of them are known to the lexer, e.g. DATA
+	var value = token.Value
+	if isOptions && token.Type != lexer.COMMENT {
+		value = strings.ToUpper(value)
+	}
+
+	// Write element
+	switch {
+
+	// Start statement, options following the query and any token following a line comment on a new line
+	case position == 0,
+		isOptions && token.Type == lexer.WITH,
+		previousToken.IsLineComment():
+		buf.WriteString(fmt.Sprintf("%s%s%s", NEWLINE, strings.Repeat(INDENT, formatter.IndentLevel), value))
+
+	// Write comma token without whitespace
+	case token.Type == lexer.COMMA:
+		buf.WriteString(value)
+	case strings.HasPrefix(token.Value, "::"):
+		buf.WriteString(value)
+
+	// Write common token values
+	default:
+		buf.WriteString(fmt.Sprintf("%s%s", WHITESPACE, value))
+	}
+}
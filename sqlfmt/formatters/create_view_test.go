@@ -0,0 +1,75 @@
+package formatters
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/noneymous/go-sqlfmt/sqlfmt/lexer"
+)
+
+func TestFormatCreateView(t *testing.T) {
+	options := DefaultOptions()
+	tests := []struct {
+		name        string
+		tokenSource []Formatter
+		want        string
+	}{
+		{
+			name: "normalcase",
+			tokenSource: []Formatter{
+				Token{Options: options, Token: lexer.Token{Type: lexer.CREATE, Value: "CREATE"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.OR, Value: "OR"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.REPLACE, Value: "REPLACE"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.VIEW, Value: "VIEW"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.IDENT, Value: "v"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.AS, Value: "AS"}},
+				&Select{Options: options, Elements: []Formatter{
+					Token{Options: options, Token: lexer.Token{Type: lexer.SELECT, Value: "SELECT"}},
+					Token{Options: options, Token: lexer.Token{Type: lexer.IDENT, Value: "a"}},
+				}},
+				&From{Options: options, Elements: []Formatter{
+					Token{Options: options, Token: lexer.Token{Type: lexer.FROM, Value: "FROM"}},
+					Token{Options: options, Token: lexer.Token{Type: lexer.IDENT, Value: "t"}},
+				}},
+			},
+			want: "\nCREATE OR REPLACE VIEW v AS\nSELECT\n  a\nFROM t",
+		},
+		{
+			name: "with options",
+			tokenSource: []Formatter{
+				Token{Options: options, Token: lexer.Token{Type: lexer.CREATE, Value: "CREATE"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.MATERIALIZED, Value: "MATERIALIZED"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.VIEW, Value: "VIEW"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.IDENT, Value: "v"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.AS, Value: "AS"}},
+				&Select{Options: options, Elements: []Formatter{
+					Token{Options: options, Token: lexer.Token{Type: lexer.SELECT, Value: "SELECT"}},
+					Token{Options: options, Token: lexer.Token{Type: lexer.IDENT, Value: "a"}},
+				}},
+				&From{Options: options, Elements: []Formatter{
+					Token{Options: options, Token: lexer.Token{Type: lexer.FROM, Value: "FROM"}},
+					Token{Options: options, Token: lexer.Token{Type: lexer.IDENT, Value: "t"}},
+				}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.WITH, Value: "WITH"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.NO, Value: "NO"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.IDENT, Value: "data"}},
+			},
+			want: "\nCREATE MATERIALIZED VIEW v AS\nSELECT\n  a\nFROM t\nWITH NO DATA",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			el := &CreateView{Options: options, Elements: tt.tokenSource}
+
+			_ = el.Format(buf, nil, 0)
+			got := buf.String()
+			if tt.want != got {
+				t.Errorf("\n=======================\n=== WANT =============>\n%s\n=======================\n=== GOT ==============>\n%s\n=======================", tt.want, got)
+			} else {
+				fmt.Println(fmt.Sprintf("%s\n%s", got, "========================================================================"))
+			}
+		})
+	}
+}
@@ -11,10 +11,22 @@ import (
 
 // Options to define output format of Formatters
 type Options struct {
-	Padding    string // Character sequence added as left padding on all lines, e.g. "" (none)
-	Indent     string // Character sequence used left indentation on indented clauses, e.g. "    " (4 spaces)
-	Newline    string // Character sequence used as line feeds, e.g. "\n" (newline character)
-	Whitespace string // Character sequence used as whitespace in SQL string, e.g. " " (single space)
+	Padding    string        // Character sequence added as left padding on all lines, e.g. "" (none)
+	Indent     string        // Character sequence used left indentation on indented clauses, e.g. "    " (4 spaces)
+	Newline    string        // Character sequence used as line feeds, e.g. "\n" (newline character)
+	Whitespace string        // Character sequence used as whitespace in SQL string, e.g. " " (single space)
+	Dialect    lexer.Dialect // SQL dialect deciding which keywords, functions, types and operators are recognized
+
+	// DisableFunctionKeywords treats parenthesis-less functions, such as USER or CURRENT_DATE, as common names.
+	// See lexer.Config for details.
+	DisableFunctionKeywords bool
+
+	// Registry defines additional functions, keywords, types and function keywords, e.g. of database extensions
+	Registry *lexer.Registry
+
+	// Recover formats queries, even if parts of them can not be parsed, e.g. because of unsupported dialect
+	// features. Unrecognized regions are written verbatim, while the surrounding clauses are still formatted.
+	Recover bool
 }
 
 // DefaultOptions returns a default options set for Formatters. Also used in unit tests.
@@ -24,6 +36,7 @@ func DefaultOptions() *Options {
 		Indent:     "  ",
 		Newline:    "\n",
 		Whitespace: " ",
+		Dialect:    lexer.Generic,
 	}
 }
 
@@ -108,6 +121,37 @@ func (formatter Token) IsLineComment() bool {
 	return formatter.Type == lexer.COMMENT && !strings.HasPrefix(formatter.Value, "/*")
 }
 
+// IsHint returns true if token is an optimizer hint comment, e.g. /*+ INDEX(t idx) */
+func (formatter Token) IsHint() bool {
+	return formatter.Type == lexer.HINT
+}
+
+// IsIdent returns true if token is a field or table name, either plain or double-quoted
+func (formatter Token) IsIdent() bool {
+	return formatter.Type == lexer.IDENT || formatter.Type == lexer.QUOTED_IDENT
+}
+
+// IsOperator returns true if token is an arithmetic, concatenation, JSON or other operator
+func (formatter Token) IsOperator() bool {
+	return formatter.Type == lexer.OPERATOR
+}
+
+// IsNumber returns true if token is a numeric literal
+func (formatter Token) IsNumber() bool {
+	return formatter.Type == lexer.NUMBER
+}
+
+// IsPlaceholder returns true if token is a bind parameter placeholder, such as $1, ?, :name or @p1
+func (formatter Token) IsPlaceholder() bool {
+	return formatter.Type == lexer.PLACEHOLDER
+}
+
+// IsClauseValue returns true if token adds to the length of a clause, such as names, numbers, placeholders or
+// operators
+func (formatter Token) IsClauseValue() bool {
+	return formatter.IsIdent() || formatter.IsNumber() || formatter.IsPlaceholder() || formatter.IsOperator()
+}
+
 // IsComparator returns true if token is a comparator
 func (formatter Token) IsComparator() bool {
 	return formatter.Type == lexer.COMPARATOR
@@ -149,6 +193,31 @@ func processPunctuation(rs []Formatter, WHITESPACE string) ([]Formatter, error)
 	return result, nil
 }
 
+// separateHints removes optimizer hints directly following the leading keyword from the elements. They can
+// be written inline right after the keyword then, while the remaining elements are formatted as if there was
+// no hint. Some engines ignore hints, which are not placed right after the keyword.
+func separateHints(elements []Formatter) ([]Formatter, []Token) {
+
+	// Collect hints following the leading keyword
+	var hints []Token
+	end := 1
+	for ; end < len(elements); end++ {
+		token, ok := elements[end].(Token)
+		if !ok || !token.IsHint() {
+			break
+		}
+		hints = append(hints, token)
+	}
+
+	// Return elements unchanged if there are no hints
+	if len(hints) == 0 {
+		return elements, nil
+	}
+
+	// Return remaining elements and hints
+	return append([]Formatter{elements[0]}, elements[end:]...), hints
+}
+
 // returns surrounding area including punctuation such as {xxx, xxx}
 func extractSurroundingArea(rs []Formatter, WHITESPACE string) (string, int, error) {
 	var (
@@ -0,0 +1,183 @@
+package formatters
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/noneymous/go-sqlfmt/sqlfmt/lexer"
+)
+
+// grantKeywords lists words of privileges and options of GRANT and REVOKE statements, which are written
+// upper-cased, although they are not known to the lexer, e.g. "USAGE" or "OPTION" of WITH GRANT OPTION
+var grantKeywords = map[string]bool{
+	"PRIVILEGES": true, "USAGE": true, "REFERENCES": true, "TRUNCATE": true, "CONNECT": true, "TEMPORARY": true,
+	"TEMP": true, "MAINTAIN": true, "TRIGGER": true, "EXECUTE": true, "OPTION": true, "GRANTED": true,
+	"CASCADE": true, "RESTRICT": true, "DEFAULT": true,
+}
+
+// grantObjectKeywords lists the object types privileges are granted on, e.g. "SCHEMA" of ON SCHEMA public. They
+// are only upper-cased if they directly follow the words introducing them, so that equally named objects keep
+// their case.
+var grantObjectKeywords = map[string]bool{
+	"TABLES": true, "SEQUENCES": true, "FUNCTIONS": true, "PROCEDURES": true, "ROUTINES": true, "TYPES": true,
+	"SCHEMAS": true, "SEQUENCE": true, "SCHEMA": true, "DOMAIN": true, "LANGUAGE": true, "LARGE": true,
+	"OBJECT": true, "FOREIGN": true, "DATA": true, "WRAPPER": true, "SERVER": true, "PARAMETER": true,
+}
+
+// Grant group formatter
+// The grant group formatter writes GRANT and REVOKE statements, as well as ALTER DEFAULT PRIVILEGES statements.
+// The privileges and objects are written on the first line, the roles and options on lines of their own, e.g.:
+//
+//	GRANT SELECT, INSERT ON ALL TABLES IN SCHEMA public
+//	TO app_user, PUBLIC
+//	WITH GRANT OPTION
+type Grant struct {
+	Elements    []Formatter
+	IndentLevel int
+	*Options    // Options used later to format element
+}
+
+// Format component accordingly with necessary indents, newlines,...
+func (formatter *Grant) Format(buf *bytes.Buffer, parent []Formatter, parentIdx int) error {
+
+	// Preprocess punctuation and enrich with surrounding information
+	elements, err := processPunctuation(formatter.Elements, formatter.Whitespace)
+	if err != nil {
+		return err
+	}
+
+	// Iterate and write elements to the buffer. Recursively step into nested elements.
+	var previousToken Token
+	var isDefault = false // Whether default privileges are altered, which are granted or revoked on a new line
+	var isRoles = false   // Whether the roles privileges are granted to or revoked from have started
+	var depth = 0         // Depth of parentheses of column lists or argument types
+	for i, el := range elements {
+
+		// Write element or recursively call its Format function
+		if token, ok := el.(Token); ok {
+			switch {
+			case i == 0:
+				isDefault = token.Type == lexer.ALTER
+			case depth == 0 && (token.Type == lexer.TO || token.Type == lexer.FROM):
+				isRoles = true
+			case depth == 0 && (token.Type == lexer.GRANT || token.Type == lexer.REVOKE):
+				isRoles = false
+			}
+			formatter.writeGrant(buf, token, previousToken, i, depth, isDefault, isRoles)
+			switch token.Type {
+			case lexer.STARTPARENTHESIS:
+				depth++
+			case lexer.ENDPARENTHESIS:
+				depth--
+			}
+		} else {
+
+			// Recursively format nested elements
+			_ = el.Format(buf, elements, i)
+		}
+
+		// Remember last Token element
+		if token, ok := el.(Token); ok {
+			previousToken = token
+		} else {
+			previousToken = Token{}
+		}
+	}
+
+	// Return nil and continue with parent Formatter
+	return nil
+}
+
+// AddIndent increments indentation level by the given amount
+func (formatter *Grant) AddIndent(lev int) {
+	formatter.IndentLevel += lev
+
+	// Preprocess punctuation and enrich with surrounding information
+	elements, err := processPunctuation(formatter.Elements, formatter.Whitespace)
+	if err != nil {
+		elements = formatter.Elements
+	}
+
+	// Iterate and increase indent of child elements too
+	for _, el := range elements {
+		el.AddIndent(lev)
+	}
+}
+
+func (formatter *Grant) writeGrant(
+	buf *bytes.Buffer,
+	token,
+	previousToken Token,
+	position,
+	depth int,
+	isDefault,
+	isRoles bool,
+) {
+
+	// Prepare short variables for better visibility
+	var INDENT = formatter.Indent
+	var NEWLINE = formatter.Newline
+	var WHITESPACE = formatter.Whitespace
+
+	// Write words of privileges, object types and options upper-cased, although they are not known to the lexer
+	var value = token.Value
+	var word = strings.ToUpper(token.Value)
+	if token.Type == lexer.IDENT && depth == 0 {
+		switch {
+		case grantKeywords[word]:
+			value = word
+		case grantObjectKeywords[word] && isGrantObjectStart(previousToken):
+			value = word
+		case word == "PUBLIC" && isRoles: // Pseudo-role of all roles
+			value = word
+		case (word == "ADMIN" || word == "INHERIT") && previousToken.Type == lexer.WITH: // Options of role grants
+			value = word
+		case (word == "ROLE" || word == "USER") && previousToken.Type == lexer.FOR: // Target role of default privileges
+			value = word
+		}
+	}
+
+	// Write element
+	switch {
+
+	// Start statement, the roles, options and any token following a line comment on a new line. Default
+	// privileges are granted or revoked on a new line too.
+	case position == 0,
+		depth == 0 && (token.Type == lexer.TO || token.Type == lexer.FROM || token.Type == lexer.WITH),
+		depth == 0 && token.Type == lexer.IDENT && strings.EqualFold(token.Value, "GRANTED"),
+		depth == 0 && isDefault && (token.Type == lexer.GRANT || token.Type == lexer.REVOKE),
+		previousToken.IsLineComment():
+		buf.WriteString(fmt.Sprintf("%s%s%s", NEWLINE, strings.Repeat(INDENT, formatter.IndentLevel), value))
+
+	// Write argument types of routines directly after their name, like a function call
+	case token.Type == lexer.STARTPARENTHESIS && previousToken.Type == lexer.IDENT:
+		buf.WriteString(value)
+	case token.Type == lexer.STARTPARENTHESIS:
+		buf.WriteString(fmt.Sprintf("%s%s", WHITESPACE, value))
+	case token.Type == lexer.ENDPARENTHESIS, previousToken.Type == lexer.STARTPARENTHESIS:
+		buf.WriteString(value)
+
+	// Write comma token without whitespace
+	case token.Type == lexer.COMMA:
+		buf.WriteString(value)
+	case strings.HasPrefix(token.Value, "::"):
+		buf.WriteString(value)
+
+	// Write common token values
+	default:
+		buf.WriteString(fmt.Sprintf("%s%s", WHITESPACE, value))
+	}
+}
+
+// isGrantObjectStart returns true if the token introduces the object type privileges are granted on, e.g. ON of
+// ON SCHEMA, ALL of ON ALL TABLES, IN of IN SCHEMA or FOREIGN of ON FOREIGN DATA WRAPPER
+func isGrantObjectStart(token Token) bool {
+	switch token.Type {
+	case lexer.ON, lexer.ALL, lexer.IN:
+		return true
+	case lexer.IDENT:
+		return grantObjectKeywords[strings.ToUpper(token.Value)]
+	}
+	return false
+}
@@ -0,0 +1,92 @@
+package formatters
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/noneymous/go-sqlfmt/sqlfmt/lexer"
+)
+
+func TestFormatGrant(t *testing.T) {
+	options := DefaultOptions()
+	tests := []struct {
+		name        string
+		tokenSource []Formatter
+		want        string
+	}{
+		{
+			name: "normalcase",
+			tokenSource: []Formatter{
+				Token{Options: options, Token: lexer.Token{Type: lexer.GRANT, Value: "GRANT"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.SELECT, Value: "SELECT"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.COMMA, Value: ","}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.INSERT, Value: "INSERT"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.ON, Value: "ON"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.ALL, Value: "ALL"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.IDENT, Value: "tables"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.IN, Value: "IN"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.IDENT, Value: "schema"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.IDENT, Value: "public"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.TO, Value: "TO"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.IDENT, Value: "app"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.COMMA, Value: ","}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.IDENT, Value: "public"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.WITH, Value: "WITH"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.GRANT, Value: "GRANT"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.IDENT, Value: "option"}},
+			},
+			want: "\nGRANT SELECT, INSERT ON ALL TABLES IN SCHEMA public\nTO app, PUBLIC\nWITH GRANT OPTION",
+		},
+		{
+			name: "revoke on function",
+			tokenSource: []Formatter{
+				Token{Options: options, Token: lexer.Token{Type: lexer.REVOKE, Value: "REVOKE"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.ALL, Value: "ALL"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.IDENT, Value: "privileges"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.ON, Value: "ON"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.ROUTINE, Value: "FUNCTION"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.IDENT, Value: "f"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.STARTPARENTHESIS, Value: "("}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.TYPE, Value: "INT"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.ENDPARENTHESIS, Value: ")"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.FROM, Value: "FROM"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.IDENT, Value: "bob"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.IDENT, Value: "cascade"}},
+			},
+			want: "\nREVOKE ALL PRIVILEGES ON FUNCTION f(INT)\nFROM bob CASCADE",
+		},
+		{
+			name: "default privileges",
+			tokenSource: []Formatter{
+				Token{Options: options, Token: lexer.Token{Type: lexer.ALTER, Value: "ALTER"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.IDENT, Value: "default"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.IDENT, Value: "privileges"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.IN, Value: "IN"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.IDENT, Value: "schema"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.IDENT, Value: "s"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.GRANT, Value: "GRANT"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.SELECT, Value: "SELECT"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.ON, Value: "ON"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.IDENT, Value: "tables"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.TO, Value: "TO"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.IDENT, Value: "app"}},
+			},
+			want: "\nALTER DEFAULT PRIVILEGES IN SCHEMA s\nGRANT SELECT ON TABLES\nTO app",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			el := &Grant{Options: options, Elements: tt.tokenSource}
+
+			_ = el.Format(buf, nil, 0)
+			got := buf.String()
+			if tt.want != got {
+				t.Errorf("\n=======================\n=== WANT =============>\n%s\n=======================\n=== GOT ==============>\n%s\n=======================", tt.want, got)
+			} else {
+				fmt.Println(fmt.Sprintf("%s\n%s", got, "========================================================================"))
+			}
+		})
+	}
+}
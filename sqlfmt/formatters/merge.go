@@ -0,0 +1,140 @@
+package formatters
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/noneymous/go-sqlfmt/sqlfmt/lexer"
+)
+
+// Merge group formatter
+// The merge group formatter writes the target, source and join condition of a MERGE statement on lines of their
+// own, followed by each WHEN branch with its action indented, e.g.:
+//
+//	MERGE INTO t
+//	USING s
+//	ON t.id = s.id
+//	WHEN MATCHED THEN
+//	  UPDATE SET
+//	    a = s.a
+//	WHEN NOT MATCHED THEN
+//	  INSERT (id, a)
+//	  VALUES (s.id, s.a)
+type Merge struct {
+	Elements    []Formatter
+	IndentLevel int
+	*Options    // Options used later to format element
+}
+
+// Format component accordingly with necessary indents, newlines,...
+func (formatter *Merge) Format(buf *bytes.Buffer, parent []Formatter, parentIdx int) error {
+
+	// Preprocess punctuation and enrich with surrounding information
+	elements, err := processPunctuation(formatter.Elements, formatter.Whitespace)
+	if err != nil {
+		return err
+	}
+
+	// Iterate and write elements to the buffer. Recursively step into nested elements.
+	var previousToken Token
+	var level int // Indentation of the current line relative to the MERGE keyword
+	for i, el := range elements {
+
+		// Write element or recursively call its Format function
+		if token, ok := el.(Token); ok {
+			level = formatter.writeMerge(buf, token, previousToken, level, i)
+		} else {
+
+			// Indent nested elements like the line they are written on
+			el.AddIndent(level)
+
+			// Recursively format nested elements
+			_ = el.Format(buf, elements, i)
+		}
+
+		// Remember last Token element
+		if token, ok := el.(Token); ok {
+			previousToken = token
+		} else {
+			previousToken = Token{}
+		}
+	}
+
+	// Return nil and continue with parent Formatter
+	return nil
+}
+
+// AddIndent increments indentation level by the given amount
+func (formatter *Merge) AddIndent(lev int) {
+	formatter.IndentLevel += lev
+
+	// Preprocess punctuation and enrich with surrounding information
+	elements, err := processPunctuation(formatter.Elements, formatter.Whitespace)
+	if err != nil {
+		elements = formatter.Elements
+	}
+
+	// Iterate and increase indent of child elements too
+	for _, el := range elements {
+		el.AddIndent(lev)
+	}
+}
+
+// writeMerge writes a token of the MERGE statement and returns the relative indentation of the line continued
+func (formatter *Merge) writeMerge(buf *bytes.Buffer, token, previousToken Token, level int, position int) int {
+
+	// Prepare short variables for better visibility
+	var INDENT = formatter.Indent
+	var NEWLINE = formatter.Newline
+	var WHITESPACE = formatter.Whitespace
+
+	// Prepare function writing the token onto a new line with the given relative indentation
+	newline := func(level int) int {
+		buf.WriteString(fmt.Sprintf("%s%s%s", NEWLINE, strings.Repeat(INDENT, formatter.IndentLevel+level), token.Value))
+		return level
+	}
+
+	// Write element
+	switch {
+
+	// Start statement on a new line
+	case position == 0:
+		return newline(0)
+
+	// Any token following a line comment must start on a new line
+	case previousToken.IsLineComment():
+		return newline(level)
+
+	// Write target, source, join condition and branches on lines of their own
+	case token.Type == lexer.USING, token.Type == lexer.ON, token.Type == lexer.WHEN:
+		return newline(0)
+
+	// Write action of a branch indented on a new line. Oracle allows conditions and a DELETE clause after it.
+	case previousToken.Type == lexer.THEN,
+		token.Type == lexer.WHERE && previousToken.Type != lexer.DELETE,
+		token.Type == lexer.DELETE && level > 0:
+		return newline(1)
+
+	// Write VALUES of an INSERT action on a new line, unless there is no column list
+	case token.Type == lexer.VALUES && previousToken.Type != lexer.INSERT && !strings.EqualFold(previousToken.Value, "DEFAULT"):
+		return newline(1)
+
+	// Write assignments of an UPDATE action on lines of their own
+	case previousToken.Type == lexer.SET, previousToken.Type == lexer.COMMA && level == 2:
+		return newline(2)
+
+	// Write comma token without whitespace
+	case token.Type == lexer.COMMA:
+		buf.WriteString(token.Value)
+	case strings.HasPrefix(token.Value, "::"):
+		buf.WriteString(token.Value)
+
+	// Write common token values
+	default:
+		buf.WriteString(fmt.Sprintf("%s%s", WHITESPACE, token.Value))
+	}
+
+	// Continue current line
+	return level
+}
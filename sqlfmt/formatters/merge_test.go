@@ -0,0 +1,76 @@
+package formatters
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/noneymous/go-sqlfmt/sqlfmt/lexer"
+)
+
+func TestFormatMerge(t *testing.T) {
+	options := DefaultOptions()
+	tests := []struct {
+		name        string
+		tokenSource []Formatter
+		want        string
+	}{
+		{
+			name: "normalcase",
+			tokenSource: []Formatter{
+				Token{Options: options, Token: lexer.Token{Type: lexer.MERGE, Value: "MERGE"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.INTO, Value: "INTO"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.IDENT, Value: "t"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.USING, Value: "USING"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.IDENT, Value: "s"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.ON, Value: "ON"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.IDENT, Value: "t.id"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.COMPARATOR, Value: "="}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.IDENT, Value: "s.id"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.WHEN, Value: "WHEN"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.MATCHED, Value: "MATCHED"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.THEN, Value: "THEN"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.UPDATE, Value: "UPDATE"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.SET, Value: "SET"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.IDENT, Value: "a"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.COMPARATOR, Value: "="}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.IDENT, Value: "s.a"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.COMMA, Value: ","}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.IDENT, Value: "b"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.COMPARATOR, Value: "="}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.IDENT, Value: "s.b"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.WHEN, Value: "WHEN"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.NOT, Value: "NOT"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.MATCHED, Value: "MATCHED"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.THEN, Value: "THEN"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.INSERT, Value: "INSERT"}},
+				&Parenthesis{Options: options, Elements: []Formatter{
+					Token{Options: options, Token: lexer.Token{Type: lexer.STARTPARENTHESIS, Value: "("}},
+					Token{Options: options, Token: lexer.Token{Type: lexer.IDENT, Value: "id"}},
+					Token{Options: options, Token: lexer.Token{Type: lexer.ENDPARENTHESIS, Value: ")"}},
+				}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.VALUES, Value: "VALUES"}},
+				&Parenthesis{Options: options, Elements: []Formatter{
+					Token{Options: options, Token: lexer.Token{Type: lexer.STARTPARENTHESIS, Value: "("}},
+					Token{Options: options, Token: lexer.Token{Type: lexer.IDENT, Value: "s.id"}},
+					Token{Options: options, Token: lexer.Token{Type: lexer.ENDPARENTHESIS, Value: ")"}},
+				}},
+			},
+			want: "\nMERGE INTO t\nUSING s\nON t.id = s.id\nWHEN MATCHED THEN\n  UPDATE SET\n    a = s.a,\n    b = s.b\nWHEN NOT MATCHED THEN\n  INSERT (id)\n  VALUES (s.id)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			el := &Merge{Options: options, Elements: tt.tokenSource}
+
+			_ = el.Format(buf, nil, 0)
+			got := buf.String()
+			if tt.want != got {
+				t.Errorf("\n=======================\n=== WANT =============>\n%s\n=======================\n=== GOT ==============>\n%s\n=======================", tt.want, got)
+			} else {
+				fmt.Println(fmt.Sprintf("%s\n%s", got, "========================================================================"))
+			}
+		})
+	}
+}
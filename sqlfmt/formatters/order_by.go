@@ -33,7 +33,7 @@ func (formatter *OrderBy) Format(buf *bytes.Buffer, parent []Formatter, parentId
 	for _, el := range elements {
 		switch t := el.(type) {
 		case Token:
-			if t.Type == lexer.IDENT {
+			if t.IsClauseValue() {
 				clauses++
 			} else if t.Type == lexer.COMMENT {
 				clauses = 999 // Format like if there were many clauses to make space for comments
@@ -0,0 +1,121 @@
+package formatters
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/noneymous/go-sqlfmt/sqlfmt/lexer"
+)
+
+// policyKeywords lists words of row-level security policies, which are written upper-cased, although they are
+// not known to the lexer, e.g. "CHECK" of WITH CHECK
+var policyKeywords = map[string]bool{
+	"PERMISSIVE": true, "RESTRICTIVE": true, "CHECK": true, "PUBLIC": true, "RENAME": true,
+}
+
+// Policy group formatter
+// The policy group formatter writes CREATE POLICY and ALTER POLICY statements of row-level security. The kind of
+// the policy, the command, the roles and the expressions are written on lines of their own. The expressions are
+// nested elements, which are laid out like the conditions of WHERE clauses, e.g.:
+//
+//	CREATE POLICY tenant_isolation ON orders
+//	AS RESTRICTIVE
+//	FOR SELECT
+//	TO app_user
+//	USING (
+//	  tenant_id = CURRENT_SETTING('app.tenant')
+//	  AND NOT deleted
+//	)
+//	WITH CHECK (owner = CURRENT_USER)
+type Policy struct {
+	Elements    []Formatter
+	IndentLevel int
+	*Options    // Options used later to format element
+}
+
+// Format component accordingly with necessary indents, newlines,...
+func (formatter *Policy) Format(buf *bytes.Buffer, parent []Formatter, parentIdx int) error {
+
+	// Preprocess punctuation and enrich with surrounding information
+	elements, err := processPunctuation(formatter.Elements, formatter.Whitespace)
+	if err != nil {
+		return err
+	}
+
+	// Iterate and write elements to the buffer. Recursively step into nested elements.
+	var previousToken Token
+	for i, el := range elements {
+
+		// Write element or recursively call its Format function
+		if token, ok := el.(Token); ok {
+			formatter.writePolicy(buf, token, previousToken, i)
+		} else {
+
+			// Recursively format nested elements
+			_ = el.Format(buf, elements, i)
+		}
+
+		// Remember last Token element
+		if token, ok := el.(Token); ok {
+			previousToken = token
+		} else {
+			previousToken = Token{}
+		}
+	}
+
+	// Return nil and continue with parent Formatter
+	return nil
+}
+
+// AddIndent increments indentation level by the given amount
+func (formatter *Policy) AddIndent(lev int) {
+	formatter.IndentLevel += lev
+
+	// Preprocess punctuation and enrich with surrounding information
+	elements, err := processPunctuation(formatter.Elements, formatter.Whitespace)
+	if err != nil {
+		elements = formatter.Elements
+	}
+
+	// Iterate and increase indent of child elements too
+	for _, el := range elements {
+		el.AddIndent(lev)
+	}
+}
+
+func (formatter *Policy) writePolicy(buf *bytes.Buffer, token, previousToken Token, position int) {
+
+	// Prepare short variables for better visibility
+	var INDENT = formatter.Indent
+	var NEWLINE = formatter.Newline
+	var WHITESPACE = formatter.Whitespace
+
+	// Write words of the policy upper-cased, although they are not known to the lexer
+	var value = token.Value
+	if token.Type == lexer.IDENT && policyKeywords[strings.ToUpper(token.Value)] {
+		value = strings.ToUpper(token.Value)
+	}
+
+	// Write element
+	switch {
+
+	// Start statement, the parts of the policy and any token following a line comment on a new line. The new name
+	// of RENAME TO continues the line.
+	case position == 0,
+		token.Type == lexer.AS, token.Type == lexer.FOR, token.Type == lexer.USING, token.Type == lexer.WITH,
+		token.Type == lexer.TO && !strings.EqualFold(previousToken.Value, "RENAME"),
+		previousToken.IsLineComment():
+		buf.WriteString(fmt.Sprintf("%s%s%s", NEWLINE, strings.Repeat(INDENT, formatter.IndentLevel), value))
+
+	// Write comma token without whitespace
+	case token.Type == lexer.COMMA:
+		buf.WriteString(value)
+	case strings.HasPrefix(token.Value, "::"):
+		buf.WriteString(value)
+
+	// Write common token values
+	default:
+		buf.WriteString(fmt.Sprintf("%s%s", WHITESPACE, value))
+	}
+}
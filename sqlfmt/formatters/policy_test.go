@@ -0,0 +1,81 @@
+package formatters
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/noneymous/go-sqlfmt/sqlfmt/lexer"
+)
+
+func TestFormatPolicy(t *testing.T) {
+	options := DefaultOptions()
+	tests := []struct {
+		name        string
+		tokenSource []Formatter
+		want        string
+	}{
+		{
+			name: "normalcase",
+			tokenSource: []Formatter{
+				Token{Options: options, Token: lexer.Token{Type: lexer.CREATE, Value: "CREATE"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.POLICY, Value: "POLICY"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.IDENT, Value: "p"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.ON, Value: "ON"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.IDENT, Value: "t"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.AS, Value: "AS"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.IDENT, Value: "restrictive"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.FOR, Value: "FOR"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.SELECT, Value: "SELECT"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.TO, Value: "TO"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.IDENT, Value: "app"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.USING, Value: "USING"}},
+				&Parenthesis{Options: options, Elements: []Formatter{
+					Token{Options: options, Token: lexer.Token{Type: lexer.STARTPARENTHESIS, Value: "("}},
+					Token{Options: options, Token: lexer.Token{Type: lexer.IDENT, Value: "a"}},
+					Token{Options: options, Token: lexer.Token{Type: lexer.COMPARATOR, Value: "="}},
+					Token{Options: options, Token: lexer.Token{Type: lexer.IDENT, Value: "1"}},
+					Token{Options: options, Token: lexer.Token{Type: lexer.ENDPARENTHESIS, Value: ")"}},
+				}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.WITH, Value: "WITH"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.IDENT, Value: "check"}},
+				&Parenthesis{Options: options, Elements: []Formatter{
+					Token{Options: options, Token: lexer.Token{Type: lexer.STARTPARENTHESIS, Value: "("}},
+					Token{Options: options, Token: lexer.Token{Type: lexer.IDENT, Value: "b"}},
+					Token{Options: options, Token: lexer.Token{Type: lexer.COMPARATOR, Value: "="}},
+					Token{Options: options, Token: lexer.Token{Type: lexer.IDENT, Value: "2"}},
+					Token{Options: options, Token: lexer.Token{Type: lexer.ENDPARENTHESIS, Value: ")"}},
+				}},
+			},
+			want: "\nCREATE POLICY p ON t\nAS RESTRICTIVE\nFOR SELECT\nTO app\nUSING (a = 1)\nWITH CHECK (b = 2)",
+		},
+		{
+			name: "rename",
+			tokenSource: []Formatter{
+				Token{Options: options, Token: lexer.Token{Type: lexer.ALTER, Value: "ALTER"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.POLICY, Value: "POLICY"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.IDENT, Value: "p"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.ON, Value: "ON"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.IDENT, Value: "t"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.IDENT, Value: "rename"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.TO, Value: "TO"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.IDENT, Value: "q"}},
+			},
+			want: "\nALTER POLICY p ON t RENAME TO q",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			el := &Policy{Options: options, Elements: tt.tokenSource}
+
+			_ = el.Format(buf, nil, 0)
+			got := buf.String()
+			if tt.want != got {
+				t.Errorf("\n=======================\n=== WANT =============>\n%s\n=======================\n=== GOT ==============>\n%s\n=======================", tt.want, got)
+			} else {
+				fmt.Println(fmt.Sprintf("%s\n%s", got, "========================================================================"))
+			}
+		})
+	}
+}
@@ -0,0 +1,146 @@
+package formatters
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/noneymous/go-sqlfmt/sqlfmt/lexer"
+)
+
+// roleOptions lists the words introducing options of CREATE ROLE and CREATE USER statements, which are not
+// necessarily known to the lexer. Each option is written on a line of its own.
+var roleOptions = map[string]bool{
+	"SUPERUSER": true, "NOSUPERUSER": true, "CREATEDB": true, "NOCREATEDB": true, "CREATEROLE": true,
+	"NOCREATEROLE": true, "INHERIT": true, "NOINHERIT": true, "LOGIN": true, "NOLOGIN": true, "REPLICATION": true,
+	"NOREPLICATION": true, "BYPASSRLS": true, "NOBYPASSRLS": true, "CONNECTION": true, "ENCRYPTED": true,
+	"PASSWORD": true, "VALID": true, "IN": true, "ROLE": true, "ADMIN": true, "USER": true, "SYSID": true,
+	"IDENTIFIED": true, "REQUIRE": true, "ACCOUNT": true,
+}
+
+// roleKeywords lists further words of role options, which are written upper-cased, e.g. "UNTIL" of VALID UNTIL
+var roleKeywords = map[string]bool{
+	"LIMIT": true, "UNTIL": true, "GROUP": true, "BY": true, "LOCK": true, "UNLOCK": true,
+}
+
+// Role group formatter
+// The role group formatter writes CREATE ROLE, CREATE USER and CREATE GROUP statements. The name of the role is
+// written on the first line, each of its options indented on a line of its own, e.g.:
+//
+//	CREATE ROLE app WITH
+//	  LOGIN
+//	  PASSWORD 'secret'
+//	  VALID UNTIL 'infinity'
+//	  IN ROLE readers, writers
+type Role struct {
+	Elements    []Formatter
+	IndentLevel int
+	*Options    // Options used later to format element
+}
+
+// Format component accordingly with necessary indents, newlines,...
+func (formatter *Role) Format(buf *bytes.Buffer, parent []Formatter, parentIdx int) error {
+
+	// Preprocess punctuation and enrich with surrounding information
+	elements, err := processPunctuation(formatter.Elements, formatter.Whitespace)
+	if err != nil {
+		return err
+	}
+
+	// Determine where the options of the role start, which is after its name and the optional WITH
+	var optionsStart = len(elements)
+	for i, el := range elements {
+		if token, ok := el.(Token); ok && (token.Type == lexer.ROLE || token.Type == lexer.GROUP ||
+			strings.EqualFold(token.Value, "USER")) {
+			optionsStart = i + 2 // The name follows the kind of role
+			break
+		}
+	}
+	if optionsStart < len(elements) {
+		if token, ok := elements[optionsStart].(Token); ok && token.Type == lexer.WITH {
+			optionsStart++
+		}
+	}
+
+	// Iterate and write elements to the buffer. Recursively step into nested elements.
+	var previousToken Token
+	for i, el := range elements {
+
+		// Write element or recursively call its Format function
+		if token, ok := el.(Token); ok {
+			formatter.writeRole(buf, token, previousToken, i, optionsStart)
+		} else {
+
+			// Recursively format nested elements
+			_ = el.Format(buf, elements, i)
+		}
+
+		// Remember last Token element
+		if token, ok := el.(Token); ok {
+			previousToken = token
+		} else {
+			previousToken = Token{}
+		}
+	}
+
+	// Return nil and continue with parent Formatter
+	return nil
+}
+
+// AddIndent increments indentation level by the given amount
+func (formatter *Role) AddIndent(lev int) {
+	formatter.IndentLevel += lev
+
+	// Preprocess punctuation and enrich with surrounding information
+	elements, err := processPunctuation(formatter.Elements, formatter.Whitespace)
+	if err != nil {
+		elements = formatter.Elements
+	}
+
+	// Iterate and increase indent of child elements too
+	for _, el := range elements {
+		el.AddIndent(lev)
+	}
+}
+
+func (formatter *Role) writeRole(buf *bytes.Buffer, token, previousToken Token, position int, optionsStart int) {
+
+	// Prepare short variables for better visibility
+	var INDENT = formatter.Indent
+	var NEWLINE = formatter.Newline
+	var WHITESPACE = formatter.Whitespace
+
+	// Options start new lines, unless they continue the previous one, e.g. ROLE of IN ROLE or PASSWORD of
+	// ENCRYPTED PASSWORD
+	var word = strings.ToUpper(token.Value)
+	var isOption = position >= optionsStart && token.Type != lexer.STRING && roleOptions[word] &&
+		previousToken.Type != lexer.IN && !strings.EqualFold(previousToken.Value, "ENCRYPTED")
+
+	// Write words of options upper-cased, although they are not known to the lexer
+	var value = token.Value
+	if position >= optionsStart && token.Type != lexer.STRING && (roleOptions[word] || roleKeywords[word]) {
+		value = word
+	}
+
+	// Write element
+	switch {
+
+	// Start statement and any token following a line comment on a new line
+	case position == 0, previousToken.IsLineComment():
+		buf.WriteString(fmt.Sprintf("%s%s%s", NEWLINE, strings.Repeat(INDENT, formatter.IndentLevel), value))
+
+	// Write options indented on lines of their own
+	case isOption:
+		buf.WriteString(fmt.Sprintf("%s%s%s%s", NEWLINE, strings.Repeat(INDENT, formatter.IndentLevel), INDENT, value))
+
+	// Write comma token without whitespace
+	case token.Type == lexer.COMMA:
+		buf.WriteString(value)
+	case strings.HasPrefix(token.Value, "::"):
+		buf.WriteString(value)
+
+	// Write common token values
+	default:
+		buf.WriteString(fmt.Sprintf("%s%s", WHITESPACE, value))
+	}
+}
@@ -0,0 +1,66 @@
+package formatters
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/noneymous/go-sqlfmt/sqlfmt/lexer"
+)
+
+func TestFormatRole(t *testing.T) {
+	options := DefaultOptions()
+	tests := []struct {
+		name        string
+		tokenSource []Formatter
+		want        string
+	}{
+		{
+			name: "normalcase",
+			tokenSource: []Formatter{
+				Token{Options: options, Token: lexer.Token{Type: lexer.CREATE, Value: "CREATE"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.ROLE, Value: "ROLE"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.IDENT, Value: "app"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.WITH, Value: "WITH"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.IDENT, Value: "login"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.IDENT, Value: "password"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.STRING, Value: "'secret'"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.IDENT, Value: "valid"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.IDENT, Value: "until"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.STRING, Value: "'infinity'"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.IN, Value: "IN"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.ROLE, Value: "ROLE"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.IDENT, Value: "readers"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.COMMA, Value: ","}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.IDENT, Value: "writers"}},
+			},
+			want: "\nCREATE ROLE app WITH\n  LOGIN\n  PASSWORD 'secret'\n  VALID UNTIL 'infinity'\n  IN ROLE readers, writers",
+		},
+		{
+			name: "user",
+			tokenSource: []Formatter{
+				Token{Options: options, Token: lexer.Token{Type: lexer.CREATE, Value: "CREATE"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.FUNCTIONKEYWORD, Value: "USER"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.IDENT, Value: "bob"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.IDENT, Value: "identified"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.BY, Value: "BY"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.STRING, Value: "'pw'"}},
+			},
+			want: "\nCREATE USER bob\n  IDENTIFIED BY 'pw'",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			el := &Role{Options: options, Elements: tt.tokenSource}
+
+			_ = el.Format(buf, nil, 0)
+			got := buf.String()
+			if tt.want != got {
+				t.Errorf("\n=======================\n=== WANT =============>\n%s\n=======================\n=== GOT ==============>\n%s\n=======================", tt.want, got)
+			} else {
+				fmt.Println(fmt.Sprintf("%s\n%s", got, "========================================================================"))
+			}
+		})
+	}
+}
@@ -0,0 +1,174 @@
+package formatters
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/noneymous/go-sqlfmt/sqlfmt/lexer"
+)
+
+// routineOptions lists the words introducing options of CREATE FUNCTION and CREATE PROCEDURE statements, which
+// are not necessarily known to the lexer. Each option is written on a line of its own.
+var routineOptions = map[string]bool{
+	"RETURNS": true, "LANGUAGE": true, "IMMUTABLE": true, "STABLE": true, "VOLATILE": true, "LEAKPROOF": true,
+	"STRICT": true, "CALLED": true, "SECURITY": true, "EXTERNAL": true, "PARALLEL": true, "COST": true,
+	"SUPPORT": true, "TRANSFORM": true, "RETURN": true, "WINDOW": true,
+}
+
+// routineKeywords lists further words of routine options and parameters, which are written upper-cased, e.g.
+// "DEFINER" of SECURITY DEFINER or "OUT" of an output parameter
+var routineKeywords = map[string]bool{
+	"SETOF": true, "INPUT": true, "DEFINER": true, "INVOKER": true, "SAFE": true, "UNSAFE": true,
+	"RESTRICTED": true, "OUT": true, "INOUT": true, "VARIADIC": true, "DEFAULT": true,
+}
+
+// Routine group formatter
+// The routine group formatter writes CREATE FUNCTION and CREATE PROCEDURE statements, as well as DO statements.
+// The signature is written on the first line, each option of the routine on a line of its own. Dollar-quoted
+// bodies are formatted by the Body formatter, e.g.:
+//
+//	CREATE OR REPLACE FUNCTION f(a INT)
+//	RETURNS INT
+//	LANGUAGE sql
+//	IMMUTABLE
+//	AS $$
+//	SELECT
+//	  a + 1;
+//	$$
+type Routine struct {
+	Elements    []Formatter
+	IndentLevel int
+	*Options    // Options used later to format element
+}
+
+// Format component accordingly with necessary indents, newlines,...
+func (formatter *Routine) Format(buf *bytes.Buffer, parent []Formatter, parentIdx int) error {
+
+	// Preprocess punctuation and enrich with surrounding information
+	elements, err := processPunctuation(formatter.Elements, formatter.Whitespace)
+	if err != nil {
+		return err
+	}
+
+	// Iterate and write elements to the buffer. Recursively step into nested elements.
+	var previousToken Token
+	var isCreate = false     // Whether the routine is created, rather than executed by a DO statement
+	var hasSignature = false // Whether the parameter list of the routine was written already
+	var depth = 0            // Depth of parentheses of the parameter list or result table
+	for i, el := range elements {
+
+		// Write element or recursively call its Format function
+		if token, ok := el.(Token); ok {
+			if i == 0 {
+				isCreate = token.Type == lexer.CREATE
+			}
+			formatter.writeRoutine(buf, token, previousToken, i, depth, isCreate, hasSignature)
+			switch token.Type {
+			case lexer.STARTPARENTHESIS:
+				depth++
+			case lexer.ENDPARENTHESIS:
+				depth--
+				hasSignature = hasSignature || depth == 0
+			}
+		} else {
+
+			// Recursively format nested elements
+			_ = el.Format(buf, elements, i)
+		}
+
+		// Remember last Token element
+		if token, ok := el.(Token); ok {
+			previousToken = token
+		} else {
+			previousToken = Token{}
+		}
+	}
+
+	// Return nil and continue with parent Formatter
+	return nil
+}
+
+// AddIndent increments indentation level by the given amount
+func (formatter *Routine) AddIndent(lev int) {
+	formatter.IndentLevel += lev
+
+	// Preprocess punctuation and enrich with surrounding information
+	elements, err := processPunctuation(formatter.Elements, formatter.Whitespace)
+	if err != nil {
+		elements = formatter.Elements
+	}
+
+	// Iterate and increase indent of child elements too
+	for _, el := range elements {
+		el.AddIndent(lev)
+	}
+}
+
+func (formatter *Routine) writeRoutine(
+	buf *bytes.Buffer,
+	token,
+	previousToken Token,
+	position,
+	depth int,
+	isCreate,
+	hasSignature bool,
+) {
+
+	// Prepare short variables for better visibility
+	var INDENT = formatter.Indent
+	var NEWLINE = formatter.Newline
+	var WHITESPACE = formatter.Whitespace
+
+	// Write words of options and parameters upper-cased, although they are not known to the lexer
+	var value = token.Value
+	var word = strings.ToUpper(token.Value)
+	if token.Type == lexer.IDENT && (routineOptions[word] || routineKeywords[word]) {
+		value = word
+	}
+
+	// Write the name of created routines as it is, even if it is named like a keyword or function, e.g. "add"
+	if isCreate && !hasSignature && depth == 0 && previousToken.Type == lexer.ROUTINE && token.Raw != "" {
+		value = token.Raw
+	}
+
+	// Options of created routines start new lines, unless they are negated, e.g. NOT LEAKPROOF. Some of them are
+	// keywords known to the lexer.
+	var isOption = false
+	if isCreate && depth == 0 && hasSignature && previousToken.Type != lexer.NOT {
+		switch token.Type {
+		case lexer.IDENT:
+			isOption = routineOptions[word]
+		case lexer.AS, lexer.SET, lexer.ROWS, lexer.NOT:
+			isOption = true
+		}
+	}
+
+	// Write element
+	switch {
+
+	// Start statement, options and any token following a line comment on a new line
+	case position == 0,
+		isOption,
+		previousToken.IsLineComment():
+		buf.WriteString(fmt.Sprintf("%s%s%s", NEWLINE, strings.Repeat(INDENT, formatter.IndentLevel), value))
+
+	// Write parameter list directly after the routine name, like a function call
+	case token.Type == lexer.STARTPARENTHESIS && depth == 0 && !hasSignature:
+		buf.WriteString(value)
+	case token.Type == lexer.STARTPARENTHESIS:
+		buf.WriteString(fmt.Sprintf("%s%s", WHITESPACE, value))
+	case token.Type == lexer.ENDPARENTHESIS, previousToken.Type == lexer.STARTPARENTHESIS:
+		buf.WriteString(value)
+
+	// Write comma token without whitespace
+	case token.Type == lexer.COMMA:
+		buf.WriteString(value)
+	case strings.HasPrefix(token.Value, "::"):
+		buf.WriteString(value)
+
+	// Write common token values
+	default:
+		buf.WriteString(fmt.Sprintf("%s%s", WHITESPACE, value))
+	}
+}
@@ -0,0 +1,86 @@
+package formatters
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/noneymous/go-sqlfmt/sqlfmt/lexer"
+)
+
+func TestFormatRoutine(t *testing.T) {
+	options := DefaultOptions()
+	tests := []struct {
+		name        string
+		tokenSource []Formatter
+		want        string
+	}{
+		{
+			name: "normalcase",
+			tokenSource: []Formatter{
+				Token{Options: options, Token: lexer.Token{Type: lexer.CREATE, Value: "CREATE"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.ROUTINE, Value: "FUNCTION"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.IDENT, Value: "f"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.STARTPARENTHESIS, Value: "("}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.IDENT, Value: "a"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.TYPE, Value: "INT"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.COMMA, Value: ","}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.IDENT, Value: "out"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.IDENT, Value: "b"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.TYPE, Value: "TEXT"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.ENDPARENTHESIS, Value: ")"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.IDENT, Value: "returns"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.TYPE, Value: "INT"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.IDENT, Value: "language"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.IDENT, Value: "sql"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.NOT, Value: "NOT"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.IDENT, Value: "leakproof"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.IDENT, Value: "security"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.IDENT, Value: "definer"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.SET, Value: "SET"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.IDENT, Value: "search_path"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.COMPARATOR, Value: "="}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.IDENT, Value: "public"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.AS, Value: "AS"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.STRING, Value: "'SELECT a'"}},
+			},
+			want: "\nCREATE FUNCTION f(a INT, OUT b TEXT)\nRETURNS INT\nLANGUAGE sql\nNOT LEAKPROOF\nSECURITY DEFINER\nSET search_path = public\nAS 'SELECT a'",
+		},
+		{
+			name: "do statement",
+			tokenSource: []Formatter{
+				Token{Options: options, Token: lexer.Token{Type: lexer.DO, Value: "DO"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.IDENT, Value: "language"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.IDENT, Value: "plpgsql"}},
+				&Body{Options: options, Tag: "$$", Elements: []Formatter{
+					&BodyLine{Options: options, Elements: []Formatter{
+						Token{Options: options, Token: lexer.Token{Type: lexer.BEGIN, Value: "BEGIN"}},
+					}},
+					&BodyLine{Options: options, IndentLevel: 1, Elements: []Formatter{
+						Token{Options: options, Token: lexer.Token{Type: lexer.IDENT, Value: "PERFORM"}},
+						Token{Options: options, Token: lexer.Token{Type: lexer.IDENT, Value: "1"}},
+						Token{Options: options, Token: lexer.Token{Type: lexer.SEMICOLON, Value: ";"}},
+					}},
+					&BodyLine{Options: options, Elements: []Formatter{
+						Token{Options: options, Token: lexer.Token{Type: lexer.END, Value: "END"}},
+					}},
+				}},
+			},
+			want: "\nDO LANGUAGE plpgsql $$\nBEGIN\n  PERFORM 1;\nEND\n$$",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			el := &Routine{Options: options, Elements: tt.tokenSource}
+
+			_ = el.Format(buf, nil, 0)
+			got := buf.String()
+			if tt.want != got {
+				t.Errorf("\n=======================\n=== WANT =============>\n%s\n=======================\n=== GOT ==============>\n%s\n=======================", tt.want, got)
+			} else {
+				fmt.Println(fmt.Sprintf("%s\n%s", got, "========================================================================"))
+			}
+		})
+	}
+}
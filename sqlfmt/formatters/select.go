@@ -27,6 +27,9 @@ func (formatter *Select) Format(buf *bytes.Buffer, parent []Formatter, parentIdx
 		return err
 	}
 
+	// Separate optimizer hints, which must stay inline right after the SELECT keyword
+	elements, hints := separateHints(elements)
+
 	// Iterate and write elements to the buffer. Recursively step into nested elements.
 	var previousToken Token
 	for i, el := range elements {
@@ -34,6 +37,13 @@ func (formatter *Select) Format(buf *bytes.Buffer, parent []Formatter, parentIdx
 		// Write element or recursively call its Format function
 		if token, ok := el.(Token); ok {
 			formatter.writeSelect(buf, token, previousToken, formatter.IndentLevel, i, true)
+
+			// Write optimizer hints right after the SELECT keyword
+			if i == 0 {
+				for _, hint := range hints {
+					buf.WriteString(fmt.Sprintf("%s%s", WHITESPACE, hint.Value))
+				}
+			}
 		} else {
 
 			// Set peripheral parameters
@@ -0,0 +1,154 @@
+package formatters
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/noneymous/go-sqlfmt/sqlfmt/lexer"
+)
+
+// triggerKeywords lists words of trigger definitions, which are written upper-cased, although they are not known
+// to the lexer, e.g. "STATEMENT" of FOR EACH STATEMENT
+var triggerKeywords = map[string]bool{
+	"CONSTRAINT": true, "OF": true, "REFERENCING": true, "STATEMENT": true, "NEW": true, "OLD": true, "TAG": true,
+	"TRUNCATE": true, "DEFERRABLE": true, "INITIALLY": true, "DEFERRED": true, "IMMEDIATE": true, "FOLLOWS": true,
+	"PRECEDES": true,
+}
+
+// Trigger group formatter
+// The trigger group formatter writes CREATE TRIGGER and CREATE EVENT TRIGGER statements. The timing and events,
+// the target table, the transition relations, the FOR EACH clause, the condition and the executed function are
+// written on lines of their own, e.g.:
+//
+//	CREATE TRIGGER trg
+//	BEFORE INSERT OR UPDATE OF a, b
+//	ON t
+//	FOR EACH ROW
+//	WHEN (OLD.a IS DISTINCT FROM NEW.a)
+//	EXECUTE FUNCTION f()
+type Trigger struct {
+	Elements    []Formatter
+	IndentLevel int
+	*Options    // Options used later to format element
+}
+
+// Format component accordingly with necessary indents, newlines,...
+func (formatter *Trigger) Format(buf *bytes.Buffer, parent []Formatter, parentIdx int) error {
+
+	// Preprocess punctuation and enrich with surrounding information
+	elements, err := processPunctuation(formatter.Elements, formatter.Whitespace)
+	if err != nil {
+		return err
+	}
+
+	// Iterate and write elements to the buffer. Recursively step into nested elements.
+	var previousToken Token
+	var depth = 0 // Depth of parentheses of the condition or arguments, nested parentheses are separate elements
+	for i, el := range elements {
+
+		// Write element or recursively call its Format function
+		if token, ok := el.(Token); ok {
+			formatter.writeTrigger(buf, token, previousToken, i, depth)
+			switch token.Type {
+			case lexer.STARTPARENTHESIS:
+				depth++
+			case lexer.ENDPARENTHESIS:
+				depth--
+			}
+		} else if previousToken.Type == lexer.STARTPARENTHESIS {
+
+			// Write first element within parenthesis without whitespace, e.g. a function call or expression
+			elBuf := &bytes.Buffer{}
+			_ = el.Format(elBuf, elements, i)
+			buf.WriteString(strings.TrimPrefix(elBuf.String(), formatter.Whitespace))
+		} else {
+
+			// Recursively format nested elements
+			_ = el.Format(buf, elements, i)
+		}
+
+		// Remember last Token element
+		if token, ok := el.(Token); ok {
+			previousToken = token
+		} else {
+			previousToken = Token{}
+		}
+	}
+
+	// Return nil and continue with parent Formatter
+	return nil
+}
+
+// AddIndent increments indentation level by the given amount
+func (formatter *Trigger) AddIndent(lev int) {
+	formatter.IndentLevel += lev
+
+	// Preprocess punctuation and enrich with surrounding information
+	elements, err := processPunctuation(formatter.Elements, formatter.Whitespace)
+	if err != nil {
+		elements = formatter.Elements
+	}
+
+	// Iterate and increase indent of child elements too
+	for _, el := range elements {
+		el.AddIndent(lev)
+	}
+}
+
+func (formatter *Trigger) writeTrigger(buf *bytes.Buffer, token, previousToken Token, position int, depth int) {
+
+	// Prepare short variables for better visibility
+	var INDENT = formatter.Indent
+	var NEWLINE = formatter.Newline
+	var WHITESPACE = formatter.Whitespace
+
+	// Write words of the trigger definition upper-cased, although they are not known to the lexer
+	var value = token.Value
+	var word = strings.ToUpper(token.Value)
+	if token.Type == lexer.IDENT && depth == 0 && triggerKeywords[word] {
+		value = word
+	}
+
+	// Write element
+	switch {
+
+	// Start statement, the parts of the trigger definition and any token following a line comment on a new line.
+	// The statement executed by a trigger of MySQL follows FOR EACH ROW and starts a new line too.
+	case position == 0,
+		depth == 0 && isTriggerPart(token),
+		depth == 0 && previousToken.Type == lexer.ROW && token.Type != lexer.WHEN && token.Type != lexer.EXECUTE,
+		previousToken.IsLineComment():
+		buf.WriteString(fmt.Sprintf("%s%s%s", NEWLINE, strings.Repeat(INDENT, formatter.IndentLevel), value))
+
+	// Write arguments of the executed function directly after its name, like a function call
+	case token.Type == lexer.STARTPARENTHESIS && depth == 0 && previousToken.Type == lexer.IDENT:
+		buf.WriteString(value)
+	case token.Type == lexer.STARTPARENTHESIS:
+		buf.WriteString(fmt.Sprintf("%s%s", WHITESPACE, value))
+	case token.Type == lexer.ENDPARENTHESIS, previousToken.Type == lexer.STARTPARENTHESIS:
+		buf.WriteString(value)
+
+	// Write comma token without whitespace
+	case token.Type == lexer.COMMA:
+		buf.WriteString(value)
+	case strings.HasPrefix(token.Value, "::"):
+		buf.WriteString(value)
+
+	// Write common token values
+	default:
+		buf.WriteString(fmt.Sprintf("%s%s", WHITESPACE, value))
+	}
+}
+
+// isTriggerPart returns true if the token starts a part of a trigger definition, e.g. its timing, the target
+// table or the executed function
+func isTriggerPart(token Token) bool {
+	switch token.Type {
+	case lexer.BEFORE, lexer.AFTER, lexer.INSTEAD, lexer.ON, lexer.FROM, lexer.FOR, lexer.WHEN, lexer.EXECUTE:
+		return true
+	case lexer.IDENT:
+		return strings.EqualFold(token.Value, "REFERENCING")
+	}
+	return false
+}
@@ -0,0 +1,87 @@
+package formatters
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/noneymous/go-sqlfmt/sqlfmt/lexer"
+)
+
+func TestFormatTrigger(t *testing.T) {
+	options := DefaultOptions()
+	tests := []struct {
+		name        string
+		tokenSource []Formatter
+		want        string
+	}{
+		{
+			name: "normalcase",
+			tokenSource: []Formatter{
+				Token{Options: options, Token: lexer.Token{Type: lexer.CREATE, Value: "CREATE"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.TRIGGER, Value: "TRIGGER"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.IDENT, Value: "trg"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.BEFORE, Value: "BEFORE"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.INSERT, Value: "INSERT"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.OR, Value: "OR"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.UPDATE, Value: "UPDATE"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.IDENT, Value: "of"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.IDENT, Value: "a"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.ON, Value: "ON"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.IDENT, Value: "t"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.FOR, Value: "FOR"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.EACH, Value: "EACH"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.ROW, Value: "ROW"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.WHEN, Value: "WHEN"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.STARTPARENTHESIS, Value: "("}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.IDENT, Value: "new.a"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.COMPARATOR, Value: ">"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.IDENT, Value: "1"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.ENDPARENTHESIS, Value: ")"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.EXECUTE, Value: "EXECUTE"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.ROUTINE, Value: "FUNCTION"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.IDENT, Value: "f"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.STARTPARENTHESIS, Value: "("}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.ENDPARENTHESIS, Value: ")"}},
+			},
+			want: "\nCREATE TRIGGER trg\nBEFORE INSERT OR UPDATE OF a\nON t\nFOR EACH ROW\nWHEN (new.a > 1)\nEXECUTE FUNCTION f()",
+		},
+		{
+			name: "event trigger",
+			tokenSource: []Formatter{
+				Token{Options: options, Token: lexer.Token{Type: lexer.CREATE, Value: "CREATE"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.EVENT, Value: "EVENT"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.TRIGGER, Value: "TRIGGER"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.IDENT, Value: "et"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.ON, Value: "ON"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.IDENT, Value: "ddl_command_end"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.WHEN, Value: "WHEN"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.IDENT, Value: "tag"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.IN, Value: "IN"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.STARTPARENTHESIS, Value: "("}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.STRING, Value: "'DROP TABLE'"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.ENDPARENTHESIS, Value: ")"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.EXECUTE, Value: "EXECUTE"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.ROUTINE, Value: "FUNCTION"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.IDENT, Value: "g"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.STARTPARENTHESIS, Value: "("}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.ENDPARENTHESIS, Value: ")"}},
+			},
+			want: "\nCREATE EVENT TRIGGER et\nON ddl_command_end\nWHEN TAG IN ('DROP TABLE')\nEXECUTE FUNCTION g()",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			el := &Trigger{Options: options, Elements: tt.tokenSource}
+
+			_ = el.Format(buf, nil, 0)
+			got := buf.String()
+			if tt.want != got {
+				t.Errorf("\n=======================\n=== WANT =============>\n%s\n=======================\n=== GOT ==============>\n%s\n=======================", tt.want, got)
+			} else {
+				fmt.Println(fmt.Sprintf("%s\n%s", got, "========================================================================"))
+			}
+		})
+	}
+}
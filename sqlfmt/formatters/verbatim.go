@@ -0,0 +1,77 @@
+package formatters
+
+import (
+	"bytes"
+	"strings"
+)
+
+// Verbatim group formatter
+// The verbatim group formatter is applied to regions of the SQL query, which could not be parsed. Its tokens are
+// written as they appear in the input, preserving their case, spacing and line breaks. Only the indentation of
+// the region is adapted.
+type Verbatim struct {
+	Elements    []Formatter
+	IndentLevel int
+	Err         error // Reason why the region could not be parsed
+	*Options          // Options used later to format element
+}
+
+// Format component accordingly with necessary indents, newlines,...
+func (formatter *Verbatim) Format(buf *bytes.Buffer, parent []Formatter, parentIdx int) error {
+
+	// Prepare short variables for better visibility
+	var INDENT = formatter.Indent
+	var NEWLINE = formatter.Newline
+
+	// Collect tokens of the region
+	var tokens []Token
+	for _, el := range formatter.Elements {
+		if token, ok := el.(Token); ok {
+			tokens = append(tokens, token)
+		}
+	}
+	if len(tokens) == 0 {
+		return nil
+	}
+
+	// Determine the smallest column any line of the region starts at. Lines are indented relative to it.
+	column := tokens[0].Start.Column
+	for i := 1; i < len(tokens); i++ {
+		if tokens[i].Start.Line > tokens[i-1].End.Line && tokens[i].Start.Column < column {
+			column = tokens[i].Start.Column
+		}
+	}
+
+	// Start region on a new line
+	indent := strings.Repeat(INDENT, formatter.IndentLevel)
+	buf.WriteString(NEWLINE + indent)
+
+	// Iterate and write tokens with their original spacing
+	for i, token := range tokens {
+		if i > 0 {
+			previousToken := tokens[i-1]
+			switch {
+			case token.Start.Line > previousToken.End.Line:
+				buf.WriteString(strings.Repeat(NEWLINE, token.Start.Line-previousToken.End.Line))
+				buf.WriteString(indent + strings.Repeat(" ", max(token.Start.Column-column, 0)))
+			case token.Start.Column > previousToken.End.Column:
+				buf.WriteString(strings.Repeat(" ", token.Start.Column-previousToken.End.Column))
+			}
+		}
+
+		// Write original text of token, if available
+		if token.Raw != "" {
+			buf.WriteString(token.Raw)
+		} else {
+			buf.WriteString(token.Value)
+		}
+	}
+
+	// Return nil and continue with parent Formatter
+	return nil
+}
+
+// AddIndent increments indentation level by the given amount
+func (formatter *Verbatim) AddIndent(lev int) {
+	formatter.IndentLevel += lev
+}
@@ -0,0 +1,55 @@
+package formatters
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/noneymous/go-sqlfmt/sqlfmt/lexer"
+)
+
+func TestFormatVerbatim(t *testing.T) {
+	options := DefaultOptions()
+	tests := []struct {
+		name        string
+		tokenSource []Formatter
+		indent      int
+		want        string
+	}{
+		{
+			name: "normalcase",
+			tokenSource: []Formatter{
+				Token{Options: options, Token: lexer.Token{Type: lexer.IDENT, Value: "merge", Raw: "merge", Start: lexer.Position{Line: 1, Column: 1}, End: lexer.Position{Line: 1, Column: 6}}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.IDENT, Value: "into", Raw: "into", Start: lexer.Position{Line: 1, Column: 8}, End: lexer.Position{Line: 1, Column: 12}}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.IDENT, Value: "t", Raw: "t", Start: lexer.Position{Line: 1, Column: 13}, End: lexer.Position{Line: 1, Column: 14}}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.USING, Value: "USING", Raw: "using", Start: lexer.Position{Line: 2, Column: 3}, End: lexer.Position{Line: 2, Column: 8}}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.IDENT, Value: "s", Raw: "s", Start: lexer.Position{Line: 2, Column: 9}, End: lexer.Position{Line: 2, Column: 10}}},
+			},
+			want: "\nmerge  into t\n  using s",
+		},
+		{
+			name: "indented",
+			tokenSource: []Formatter{
+				Token{Options: options, Token: lexer.Token{Type: lexer.ENDPARENTHESIS, Value: ")", Raw: ")", Start: lexer.Position{Line: 1, Column: 9}, End: lexer.Position{Line: 1, Column: 10}}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.IDENT, Value: "b", Raw: "b", Start: lexer.Position{Line: 2, Column: 1}, End: lexer.Position{Line: 2, Column: 2}}},
+			},
+			indent: 1,
+			want:   "\n  )\n  b",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			el := &Verbatim{Options: options, Elements: tt.tokenSource}
+			el.AddIndent(tt.indent)
+
+			_ = el.Format(buf, nil, 0)
+			got := buf.String()
+			if tt.want != got {
+				t.Errorf("\n=======================\n=== WANT =============>\n%s\n=======================\n=== GOT ==============>\n%s\n=======================", tt.want, got)
+			} else {
+				fmt.Println(fmt.Sprintf("%s\n%s", got, "========================================================================"))
+			}
+		})
+	}
+}
@@ -0,0 +1,209 @@
+package formatters
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/noneymous/go-sqlfmt/sqlfmt/lexer"
+)
+
+// Window group formatter
+// The window group formatter writes the window specification following OVER, as well as the named window
+// definitions of the WINDOW clause. Specifications with a single part stay on the same line, others write their
+// partitioning, ordering and frame on lines of their own, e.g.:
+//
+//	ROW_NUMBER() OVER (
+//	  PARTITION BY a
+//	  ORDER BY b
+//	  ROWS BETWEEN UNBOUNDED PRECEDING AND CURRENT ROW
+//	)
+type Window struct {
+	Elements    []Formatter
+	IndentLevel int
+	*Options    // Options used later to format element
+}
+
+// Format component accordingly with necessary indents, newlines,...
+func (formatter *Window) Format(buf *bytes.Buffer, parent []Formatter, parentIdx int) error {
+
+	// Preprocess punctuation and enrich with surrounding information
+	elements, err := processPunctuation(formatter.Elements, formatter.Whitespace)
+	if err != nil {
+		return err
+	}
+
+	// Determine which window specifications span multiple lines. They do if they consist of several parts, such
+	// as an existing window name, PARTITION BY, ORDER BY and the frame, or if they contain comments.
+	var spanning = make(map[int]bool) // Indices of parentheses starting window specifications spanning lines
+	var start, parts = -1, 0
+	for i, el := range elements {
+		if token, ok := el.(Token); ok {
+			switch {
+			case token.Type == lexer.STARTPARENTHESIS:
+				start, parts = i, 0
+			case token.Type == lexer.ENDPARENTHESIS && start >= 0:
+				spanning[start] = parts > 1
+				start = -1
+			case start >= 0 && isWindowPart(token):
+				parts++
+			case start >= 0 && token.Type == lexer.IDENT && i == start+1:
+				parts++ // Name of an existing window the specification is based on
+			case start >= 0 && token.Type == lexer.COMMENT:
+				parts = 999 // Format like if there were many parts to make space for comments
+			}
+		}
+	}
+
+	// Check whether the WINDOW clause defines several windows. Write each of them on a line of its own, if so.
+	var hasMany = false
+	var depth = 0
+	for _, el := range elements {
+		if token, ok := el.(Token); ok {
+			switch {
+			case token.Type == lexer.STARTPARENTHESIS:
+				depth++
+			case token.Type == lexer.ENDPARENTHESIS:
+				depth--
+			case token.Type == lexer.COMMA && depth == 0:
+				hasMany = true
+			}
+		}
+	}
+
+	// Indent window definitions, if they are written on lines of their own
+	var level = 0
+	if hasMany {
+		level = 1
+	}
+
+	// Iterate and write elements to the buffer. Recursively step into nested elements.
+	var previousToken Token
+	var isSpanning = false
+	depth = 0
+	for i, el := range elements {
+
+		// Write element or recursively call its Format function
+		if token, ok := el.(Token); ok {
+			if token.Type == lexer.STARTPARENTHESIS {
+				isSpanning = spanning[i]
+			}
+			formatter.writeWindow(buf, token, previousToken, i, depth, level, isSpanning, hasMany)
+			switch token.Type {
+			case lexer.STARTPARENTHESIS:
+				depth++
+			case lexer.ENDPARENTHESIS:
+				depth--
+			}
+		} else {
+
+			// Indent nested elements like the line they are written on
+			if depth > 0 && isSpanning {
+				el.AddIndent(level + 1)
+			} else {
+				el.AddIndent(level)
+			}
+
+			// Recursively format nested elements
+			_ = el.Format(buf, elements, i)
+		}
+
+		// Remember last Token element
+		if token, ok := el.(Token); ok {
+			previousToken = token
+		} else {
+			previousToken = Token{}
+		}
+	}
+
+	// Return nil and continue with parent Formatter
+	return nil
+}
+
+// AddIndent increments indentation level by the given amount
+func (formatter *Window) AddIndent(lev int) {
+	formatter.IndentLevel += lev
+
+	// Preprocess punctuation and enrich with surrounding information
+	elements, err := processPunctuation(formatter.Elements, formatter.Whitespace)
+	if err != nil {
+		elements = formatter.Elements
+	}
+
+	// Iterate and increase indent of child elements too
+	for _, el := range elements {
+		el.AddIndent(lev)
+	}
+}
+
+func (formatter *Window) writeWindow(
+	buf *bytes.Buffer,
+	token,
+	previousToken Token,
+	position,
+	depth,
+	level int,
+	isSpanning,
+	hasMany bool,
+) {
+
+	// Prepare short variables for better visibility
+	var INDENT = formatter.Indent
+	var NEWLINE = formatter.Newline
+	var WHITESPACE = formatter.Whitespace
+
+	// Prepare function writing the token onto a new line with the given relative indentation
+	newline := func(level int) {
+		buf.WriteString(fmt.Sprintf("%s%s%s", NEWLINE, strings.Repeat(INDENT, formatter.IndentLevel+level), token.Value))
+	}
+
+	// Write element
+	switch {
+
+	// Start WINDOW clause on a new line, but continue the line of the window function with OVER
+	case position == 0 && token.Type == lexer.WINDOW:
+		newline(0)
+	case position == 0:
+		buf.WriteString(fmt.Sprintf("%s%s", WHITESPACE, token.Value))
+
+	// Any token following a line comment must start on a new line
+	case previousToken.IsLineComment() && depth > 0 && isSpanning:
+		newline(level + 1)
+	case previousToken.IsLineComment():
+		newline(level)
+
+	// Write window definitions on lines of their own, if there are many
+	case hasMany && depth == 0 && (position == 1 || previousToken.Type == lexer.COMMA):
+		newline(level)
+
+	// Write parts of window specifications spanning multiple lines on lines of their own
+	case token.Type == lexer.STARTPARENTHESIS:
+		buf.WriteString(fmt.Sprintf("%s%s", WHITESPACE, token.Value))
+	case token.Type == lexer.ENDPARENTHESIS && isSpanning:
+		newline(level)
+	case depth > 0 && isSpanning && (previousToken.Type == lexer.STARTPARENTHESIS || isWindowPart(token)):
+		newline(level + 1)
+
+	// Write first token of window specification, commas and casts without whitespace
+	case previousToken.Type == lexer.STARTPARENTHESIS, token.Type == lexer.ENDPARENTHESIS:
+		buf.WriteString(token.Value)
+	case token.Type == lexer.COMMA:
+		buf.WriteString(token.Value)
+	case strings.HasPrefix(token.Value, "::"):
+		buf.WriteString(token.Value)
+
+	// Write common token values
+	default:
+		buf.WriteString(fmt.Sprintf("%s%s", WHITESPACE, token.Value))
+	}
+}
+
+// isWindowPart returns true if the token starts a part of a window specification, i.e. its partitioning,
+// ordering or frame
+func isWindowPart(token Token) bool {
+	switch token.Type {
+	case lexer.PARTITION, lexer.ORDER, lexer.ROWS, lexer.RANGE, lexer.GROUPS:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,90 @@
+package formatters
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/noneymous/go-sqlfmt/sqlfmt/lexer"
+)
+
+func TestFormatWindow(t *testing.T) {
+	options := DefaultOptions()
+	tests := []struct {
+		name        string
+		tokenSource []Formatter
+		want        string
+	}{
+		{
+			name: "normalcase",
+			tokenSource: []Formatter{
+				Token{Options: options, Token: lexer.Token{Type: lexer.OVER, Value: "OVER"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.STARTPARENTHESIS, Value: "("}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.PARTITION, Value: "PARTITION"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.BY, Value: "BY"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.IDENT, Value: "a"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.ORDER, Value: "ORDER"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.BY, Value: "BY"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.IDENT, Value: "b"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.ROWS, Value: "ROWS"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.BETWEEN, Value: "BETWEEN"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.UNBOUNDED, Value: "UNBOUNDED"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.PRECEDING, Value: "PRECEDING"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.AND, Value: "AND"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.CURRENT, Value: "CURRENT"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.ROW, Value: "ROW"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.ENDPARENTHESIS, Value: ")"}},
+			},
+			want: " OVER (\n  PARTITION BY a\n  ORDER BY b\n  ROWS BETWEEN UNBOUNDED PRECEDING AND CURRENT ROW\n)",
+		},
+		{
+			name: "single part",
+			tokenSource: []Formatter{
+				Token{Options: options, Token: lexer.Token{Type: lexer.OVER, Value: "OVER"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.STARTPARENTHESIS, Value: "("}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.PARTITION, Value: "PARTITION"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.BY, Value: "BY"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.IDENT, Value: "a"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.ENDPARENTHESIS, Value: ")"}},
+			},
+			want: " OVER (PARTITION BY a)",
+		},
+		{
+			name: "window clause",
+			tokenSource: []Formatter{
+				Token{Options: options, Token: lexer.Token{Type: lexer.WINDOW, Value: "WINDOW"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.IDENT, Value: "w1"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.AS, Value: "AS"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.STARTPARENTHESIS, Value: "("}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.ORDER, Value: "ORDER"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.BY, Value: "BY"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.IDENT, Value: "a"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.ENDPARENTHESIS, Value: ")"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.COMMA, Value: ","}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.IDENT, Value: "w2"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.AS, Value: "AS"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.STARTPARENTHESIS, Value: "("}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.IDENT, Value: "w1"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.PARTITION, Value: "PARTITION"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.BY, Value: "BY"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.IDENT, Value: "b"}},
+				Token{Options: options, Token: lexer.Token{Type: lexer.ENDPARENTHESIS, Value: ")"}},
+			},
+			want: "\nWINDOW\n  w1 AS (ORDER BY a),\n  w2 AS (\n    w1\n    PARTITION BY b\n  )",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			el := &Window{Options: options, Elements: tt.tokenSource}
+
+			_ = el.Format(buf, nil, 0)
+			got := buf.String()
+			if tt.want != got {
+				t.Errorf("\n=======================\n=== WANT =============>\n%s\n=======================\n=== GOT ==============>\n%s\n=======================", tt.want, got)
+			} else {
+				fmt.Println(fmt.Sprintf("%s\n%s", got, "========================================================================"))
+			}
+		})
+	}
+}
@@ -0,0 +1,213 @@
+package lexer
+
+import (
+	"strings"
+)
+
+// statementContext tracks the position within the statement currently being tokenized. It decides whether words
+// of contextKeywordMap are keywords at their position, e.g. WINDOW following the FROM clause, or common names,
+// e.g. a column named "window".
+type statementContext struct {
+	count       int           // Number of significant tokens of the statement read so far
+	with        bool          // Whether common table expressions are read, e.g. WITH x AS (...)
+	merge       bool          // Whether the statement is a MERGE statement
+	insert      bool          // Whether the statement is an INSERT statement
+	copy        bool          // Whether the statement is a COPY statement
+	data        bool          // Whether data follows the statement, e.g. COPY t FROM STDIN
+	join        bool          // Whether the last join awaits its condition, e.g. JOIN u ON ...
+	header      bool          // Whether the header of a CREATE, ALTER, DROP or REFRESH statement is read
+	object      TokenType     // Type of object created, altered or dropped, once the header is complete, e.g. VIEW
+	privileges  bool          // Whether privileges are granted or revoked, e.g. GRANT or ALTER DEFAULT PRIVILEGES
+	clauses     bool          // Whether FROM, WHERE, GROUP or HAVING occurred, which the WINDOW clause follows
+	windows     bool          // Whether the WINDOW clause started, whose window specifications follow AS
+	parentheses []parenthesis // Open parentheses, the innermost one last
+}
+
+// parenthesis describes an open parenthesis of the statement currently being tokenized
+type parenthesis struct {
+	window bool // Whether the parenthesis encloses a window specification, e.g. OVER (PARTITION BY a)
+	frame  bool // Whether the frame clause of the window specification started, e.g. ROWS BETWEEN ...
+}
+
+// keyword returns the token type of a word of the given context keywords, if it is a keyword at the current
+// position of the statement, given the type of the previous significant token
+func (c *statementContext) keyword(word string, previous TokenType, keywords map[string]TokenType) (TokenType, bool) {
+	ttype, ok := keywords[strings.ToUpper(word)]
+	if !ok {
+		return 0, false
+	}
+	switch ttype {
+
+	// MERGE starts the statement, possibly following common table expressions. Its branches depend on whether
+	// rows matched, e.g. WHEN NOT MATCHED THEN.
+	case MERGE:
+		return ttype, c.count == 0 || (c.with && len(c.parentheses) == 0 && previous == ENDPARENTHESIS)
+	case MATCHED:
+		return ttype, c.merge && (previous == WHEN || previous == NOT)
+
+	// Conflict clauses follow the rows inserted, but not a join, e.g. ON CONFLICT (id) DO NOTHING or ON DUPLICATE
+	// KEY UPDATE. Actions of conflict clauses and of MERGE branches follow DO, e.g. WHEN MATCHED THEN DO NOTHING.
+	case CONFLICT, DUPLICATE:
+		return ttype, c.insert && !c.join && len(c.parentheses) == 0 && previous == ON
+	case NOTHING:
+		return ttype, previous == DO
+
+	// Modifiers and types of objects are part of the header of CREATE, ALTER and DROP statements, e.g. CREATE OR
+	// REPLACE VIEW, CREATE UNIQUE INDEX or CREATE EVENT TRIGGER
+	case REPLACE:
+		return ttype, c.header && previous == OR
+	case UNIQUE:
+		return ttype, c.header && previous == CREATE
+	case VIEW, INDEX, TRIGGER, EVENT, ROLE, POLICY:
+		return ttype, c.header
+
+	// Views might be materialized, e.g. CREATE MATERIALIZED VIEW, as might common table expressions, e.g. WITH x AS
+	// NOT MATERIALIZED (...)
+	case MATERIALIZED:
+		return ttype, c.header || (c.with && len(c.parentheses) == 0 && (previous == AS || previous == NOT))
+
+	// Indexes and materialized views might be built without locking, e.g. CREATE INDEX CONCURRENTLY or REFRESH
+	// MATERIALIZED VIEW CONCURRENTLY. Tables and indexes might be stored in a tablespace, which is an object itself,
+	// e.g. CREATE INDEX ON t (a) TABLESPACE fast or ALTER TABLE t SET TABLESPACE fast.
+	case CONCURRENTLY:
+		return ttype, (c.object == INDEX || c.object == VIEW) && previous == c.object
+	case TABLESPACE:
+		return ttype, c.header || ((c.object == INDEX || c.object == TABLE) && len(c.parentheses) == 0 &&
+			(previous == ENDPARENTHESIS || previous == SET))
+
+	// Functions and procedures are objects of CREATE, ALTER and DROP statements, of privileges, e.g. GRANT EXECUTE
+	// ON FUNCTION f TO r, or executed by triggers, e.g. EXECUTE FUNCTION f()
+	case ROUTINE:
+		return ttype, c.header || (c.privileges && previous == ON) || (c.object == TRIGGER && previous == EXECUTE)
+
+	// Privileges are granted or revoked by statements of their own, by ALTER DEFAULT PRIVILEGES or as an option,
+	// e.g. WITH GRANT OPTION
+	case GRANT, REVOKE:
+		return ttype, c.count == 0 || (c.privileges && len(c.parentheses) == 0)
+
+	// Included columns follow the indexed ones, e.g. CREATE INDEX ON t (a) INCLUDE (b)
+	case INCLUDE:
+		return ttype, c.object == INDEX && len(c.parentheses) == 0 && previous == ENDPARENTHESIS
+
+	// Timing of triggers follows their name, the executed function follows the events, e.g. CREATE TRIGGER trg
+	// BEFORE INSERT ON t FOR EACH ROW EXECUTE FUNCTION f(). Statements executed by triggers of MySQL follow FOR
+	// EACH ROW, where these words are names again, e.g. SET NEW.after = 1.
+	case BEFORE, AFTER, INSTEAD:
+		return ttype, c.object == TRIGGER && len(c.parentheses) == 0 && (previous == IDENT || previous == QUOTED_IDENT)
+	case EACH:
+		return ttype, c.object == TRIGGER && len(c.parentheses) == 0 && previous == FOR
+	case EXECUTE:
+		return ttype, c.object == TRIGGER && len(c.parentheses) == 0
+
+	// WINDOW clause follows the table expression, e.g. FROM t WINDOW w AS (...)
+	case WINDOW:
+		return ttype, c.clauses && isOperand(previous)
+
+	// Clauses of window specifications start the specification or follow a preceding clause, e.g. ORDER BY a
+	// ROWS ..., but not a list of expressions, e.g. PARTITION BY a, range
+	case PARTITION, RANGE, GROUPS:
+		return ttype, c.isWindow() && (previous == STARTPARENTHESIS || isOperand(previous) ||
+			previous == ASC || previous == DESC || previous == FIRST || previous == LAST)
+
+	// Bounds and exclusions of frames follow the frame clause, e.g. ROWS BETWEEN UNBOUNDED PRECEDING. ROW also
+	// describes the granularity of triggers, e.g. FOR EACH ROW.
+	case ROW:
+		return ttype, previous == EACH || c.isFrame()
+	case UNBOUNDED, PRECEDING, FOLLOWING, CURRENT, EXCLUDE, TIES, OTHERS, NO:
+		return ttype, c.isFrame()
+	}
+	return ttype, false
+}
+
+// update moves the context on with the next significant token, given the type of the token preceding it
+func (c *statementContext) update(token Token, previous TokenType) {
+
+	// Remember the type of object of CREATE, ALTER and DROP statements, which completes their header
+	switch {
+	case c.count == 0:
+		c.with = token.Type == WITH
+		c.header = token.Type == CREATE || token.Type == ALTER || token.Type == DROP ||
+			strings.EqualFold(token.Value, "REFRESH") // REFRESH MATERIALIZED VIEW
+		c.privileges = token.Type == GRANT || token.Type == REVOKE
+		c.copy = token.Type == COPY
+	case c.count == 2 && c.header && strings.EqualFold(token.Value, "PRIVILEGES"): // ALTER DEFAULT PRIVILEGES
+		c.privileges = true
+	case c.header && !isHeaderModifier(token):
+		c.header = false
+		c.object = token.Type
+	}
+	c.count++
+
+	// Forget joins once their condition started, e.g. JOIN u ON ... or JOIN u USING (...)
+	if previous == ON || previous == USING {
+		c.join = false
+	}
+
+	switch token.Type {
+
+	// Start over with the next statement, but remember whether data precedes it
+	case SEMICOLON:
+		*c = statementContext{data: c.data}
+
+	// Remember opened parenthesis, whether it encloses a window specification, e.g. OVER (...) or w AS (...)
+	case STARTPARENTHESIS:
+		c.parentheses = append(c.parentheses, parenthesis{
+			window: previous == OVER || (c.windows && previous == AS),
+		})
+	case ENDPARENTHESIS:
+		if len(c.parentheses) > 0 {
+			c.parentheses = c.parentheses[:len(c.parentheses)-1]
+		}
+
+	// Remember clauses the WINDOW clause might follow
+	case FROM, WHERE, GROUP, HAVING:
+		c.clauses = true
+	case WINDOW:
+		c.windows = true
+	case MERGE:
+		c.merge = true
+	case INSERT:
+		c.insert = c.count == 1 || (c.with && len(c.parentheses) == 0 && previous == ENDPARENTHESIS)
+	case JOIN:
+		c.join = previous != CROSS && previous != NATURAL
+
+	// Remember data following COPY, e.g. COPY t (a, b) FROM STDIN
+	case IDENT:
+		c.data = c.data || (c.copy && previous == FROM && strings.EqualFold(token.Value, "STDIN"))
+
+	// Remember start of frame clause within window specification
+	case ROWS, RANGE, GROUPS:
+		if c.isWindow() {
+			c.parentheses[len(c.parentheses)-1].frame = true
+		}
+	}
+
+	// Common table expressions end with the statement they precede, e.g. WITH x AS (...) SELECT
+	if c.with && len(c.parentheses) == 0 {
+		switch token.Type {
+		case SELECT, INSERT, UPDATE, DELETE, MERGE:
+			c.with = false
+		}
+	}
+}
+
+// isWindow returns whether the innermost open parenthesis encloses a window specification
+func (c *statementContext) isWindow() bool {
+	return len(c.parentheses) > 0 && c.parentheses[len(c.parentheses)-1].window
+}
+
+// isFrame returns whether the innermost open parenthesis encloses a window specification, whose frame clause
+// started
+func (c *statementContext) isFrame() bool {
+	return c.isWindow() && c.parentheses[len(c.parentheses)-1].frame
+}
+
+// isHeaderModifier returns whether the token modifies the object of a CREATE, ALTER or DROP statement, rather than
+// being its type, e.g. OR REPLACE, UNIQUE or TEMPORARY, or MySQL's DEFINER = CURRENT_USER
+func isHeaderModifier(token Token) bool {
+	switch token.Type {
+	case OR, REPLACE, ALTER, UNIQUE, MATERIALIZED, EVENT, IDENT, COMPARATOR, STRING, FUNCTIONKEYWORD:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,97 @@
+package lexer
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestTokenize_ContextKeywords(t *testing.T) {
+	tests := []struct {
+		sql  string
+		want []TokenType
+	}{
+		{sql: "select window from t", want: []TokenType{SELECT, IDENT, FROM, IDENT, EOF}},
+		{sql: "from t where range > 1", want: []TokenType{FROM, IDENT, WHERE, IDENT, COMPARATOR, NUMBER, EOF}},
+		{sql: "from t window w as (partition by range order by row rows current row)", want: []TokenType{
+			FROM, IDENT, WINDOW, IDENT, AS, STARTPARENTHESIS, PARTITION, BY, IDENT, ORDER, BY, IDENT, ROWS, CURRENT, ROW, ENDPARENTHESIS, EOF,
+		}},
+		{sql: "over (order by a range between unbounded preceding and 1 following exclude no others)", want: []TokenType{
+			OVER, STARTPARENTHESIS, ORDER, BY, IDENT, RANGE, BETWEEN, UNBOUNDED, PRECEDING, AND, NUMBER, FOLLOWING, EXCLUDE, NO, OTHERS, ENDPARENTHESIS, EOF,
+		}},
+		{sql: "over (partition by a, groups) current", want: []TokenType{
+			OVER, STARTPARENTHESIS, PARTITION, BY, IDENT, COMMA, IDENT, ENDPARENTHESIS, IDENT, EOF,
+		}},
+		{sql: "select no, ties, exclude; from t window w as ()", want: []TokenType{
+			SELECT, IDENT, COMMA, IDENT, COMMA, IDENT, SEMICOLON, FROM, IDENT, WINDOW, IDENT, AS, STARTPARENTHESIS, ENDPARENTHESIS, EOF,
+		}},
+		{sql: "select index, view, unique from t", want: []TokenType{SELECT, IDENT, COMMA, IDENT, COMMA, IDENT, FROM, IDENT, EOF}},
+		{sql: "update t set replace = 1", want: []TokenType{UPDATE, IDENT, SET, IDENT, COMPARATOR, NUMBER, EOF}},
+		{sql: "create or replace view index", want: []TokenType{CREATE, OR, REPLACE, VIEW, IDENT, EOF}},
+		{sql: "create unique index on t (a) include (include)", want: []TokenType{
+			CREATE, UNIQUE, INDEX, ON, IDENT, STARTPARENTHESIS, IDENT, ENDPARENTHESIS, INCLUDE, STARTPARENTHESIS, IDENT, ENDPARENTHESIS, EOF,
+		}},
+		{sql: "create temporary view v; drop index i", want: []TokenType{CREATE, IDENT, VIEW, IDENT, SEMICOLON, DROP, INDEX, IDENT, EOF}},
+		{sql: "update t set event = 1, before = 2, after = 3", want: []TokenType{
+			UPDATE, IDENT, SET, IDENT, COMPARATOR, NUMBER, COMMA, IDENT, COMPARATOR, NUMBER, COMMA, IDENT, COMPARATOR, NUMBER, EOF,
+		}},
+		{sql: "create event trigger et on e execute function f", want: []TokenType{CREATE, EVENT, TRIGGER, IDENT, ON, IDENT, EXECUTE, ROUTINE, IDENT, EOF}},
+		{sql: "create trigger trg before insert on t for each row set after = before", want: []TokenType{
+			CREATE, TRIGGER, IDENT, BEFORE, INSERT, ON, IDENT, FOR, EACH, ROW, SET, IDENT, COMPARATOR, IDENT, EOF,
+		}},
+		{sql: "select role, policy, grant from users", want: []TokenType{SELECT, IDENT, COMMA, IDENT, COMMA, IDENT, FROM, IDENT, EOF}},
+		{sql: "grant select on t to r with grant option", want: []TokenType{GRANT, SELECT, ON, IDENT, TO, IDENT, WITH, GRANT, IDENT, EOF}},
+		{sql: "alter default privileges revoke all on tables from role", want: []TokenType{
+			ALTER, IDENT, IDENT, REVOKE, ALL, ON, IDENT, FROM, IDENT, EOF,
+		}},
+		{sql: "drop role r; create policy p on t using (role = 1)", want: []TokenType{
+			DROP, ROLE, IDENT, SEMICOLON, CREATE, POLICY, IDENT, ON, IDENT, USING, STARTPARENTHESIS, IDENT, COMPARATOR, NUMBER, ENDPARENTHESIS, EOF,
+		}},
+		{sql: "select merge, matched from t where (merge)", want: []TokenType{
+			SELECT, IDENT, COMMA, IDENT, FROM, IDENT, WHERE, STARTPARENTHESIS, IDENT, ENDPARENTHESIS, EOF,
+		}},
+		{sql: "merge into t using u on matched when not matched then delete", want: []TokenType{
+			MERGE, INTO, IDENT, USING, IDENT, ON, IDENT, WHEN, NOT, MATCHED, THEN, DELETE, EOF,
+		}},
+		{sql: "with x as (select merge) merge into t", want: []TokenType{
+			WITH, IDENT, AS, STARTPARENTHESIS, SELECT, IDENT, ENDPARENTHESIS, MERGE, INTO, IDENT, EOF,
+		}},
+		{sql: "select conflict, nothing from t join u on conflict = u.x and duplicate = u.y where (conflict = 1)", want: []TokenType{
+			SELECT, IDENT, COMMA, IDENT, FROM, IDENT, JOIN, IDENT, ON, IDENT, COMPARATOR, IDENT, AND, IDENT, COMPARATOR, IDENT,
+			WHERE, STARTPARENTHESIS, IDENT, COMPARATOR, NUMBER, ENDPARENTHESIS, EOF,
+		}},
+		{sql: "insert into t select * from a join u on conflict = u.x on conflict do nothing", want: []TokenType{
+			INSERT, INTO, IDENT, SELECT, IDENT, FROM, IDENT, JOIN, IDENT, ON, IDENT, COMPARATOR, IDENT, ON, CONFLICT, DO, NOTHING, EOF,
+		}},
+		{sql: "with x as (select 1) insert into t select * from x on duplicate key update a = 1", want: []TokenType{
+			WITH, IDENT, AS, STARTPARENTHESIS, SELECT, NUMBER, ENDPARENTHESIS, INSERT, INTO, IDENT, SELECT, IDENT, FROM, IDENT,
+			ON, DUPLICATE, KEY, UPDATE, IDENT, COMPARATOR, NUMBER, EOF,
+		}},
+		{sql: "select a from t where tablespace = 1 and (materialized or concurrently)", want: []TokenType{
+			SELECT, IDENT, FROM, IDENT, WHERE, IDENT, COMPARATOR, NUMBER, AND, STARTPARENTHESIS, IDENT, OR, IDENT, ENDPARENTHESIS, EOF,
+		}},
+		{sql: "create index concurrently on t (a) tablespace fast; refresh materialized view concurrently v", want: []TokenType{
+			CREATE, INDEX, CONCURRENTLY, ON, IDENT, STARTPARENTHESIS, IDENT, ENDPARENTHESIS, TABLESPACE, IDENT, SEMICOLON,
+			IDENT, MATERIALIZED, VIEW, CONCURRENTLY, IDENT, EOF,
+		}},
+		{sql: "with x as not materialized (select 1) select a as materialized from x", want: []TokenType{
+			WITH, IDENT, AS, NOT, MATERIALIZED, STARTPARENTHESIS, SELECT, NUMBER, ENDPARENTHESIS, SELECT, IDENT, AS, IDENT, FROM, IDENT, EOF,
+		}},
+		{sql: "insert into t (function, role) select p.procedure from procedure p", want: []TokenType{
+			INSERT, INTO, IDENT, STARTPARENTHESIS, IDENT, COMMA, IDENT, ENDPARENTHESIS, SELECT, IDENT, FROM, IDENT, IDENT, EOF,
+		}},
+		{sql: "create or replace function f; drop procedure p; grant execute on routine r to u", want: []TokenType{
+			CREATE, OR, REPLACE, ROUTINE, IDENT, SEMICOLON, DROP, ROUTINE, IDENT, SEMICOLON, GRANT, IDENT, ON, ROUTINE, IDENT, TO, IDENT, EOF,
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.sql, func(t *testing.T) {
+			got, err := Tokenize(tt.sql)
+			assert.Nil(t, err)
+			var gotTypes []TokenType
+			for _, token := range got {
+				gotTypes = append(gotTypes, token.Type)
+			}
+			assert.Equal(t, tt.want, gotTypes)
+		})
+	}
+}
@@ -0,0 +1,151 @@
+package lexer
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Dialect selects the SQL dialect whose keywords, functions, types and operators are recognized
+type Dialect int
+
+// Supported dialects
+const (
+	Generic    Dialect = iota // Recognizes keywords, functions, types and operators of all dialects alike
+	ANSI                      // Standard SQL only
+	PostgreSQL                // PostgreSQL
+	MySQL                     // MySQL and MariaDB
+	SQLite                    // SQLite
+	SQLServer                 // Microsoft SQL Server
+	Oracle                    // Oracle Database
+)
+
+// dialectNames maps dialects to their names, which are also accepted by ParseDialect
+var dialectNames = map[Dialect]string{
+	Generic:    "generic",
+	ANSI:       "ansi",
+	PostgreSQL: "postgresql",
+	MySQL:      "mysql",
+	SQLite:     "sqlite",
+	SQLServer:  "sqlserver",
+	Oracle:     "oracle",
+}
+
+// dialectAliases maps alternative names to dialects
+var dialectAliases = map[string]Dialect{
+	"":         Generic,
+	"postgres": PostgreSQL,
+	"pg":       PostgreSQL,
+	"mariadb":  MySQL,
+	"mssql":    SQLServer,
+	"tsql":     SQLServer,
+}
+
+// String returns the name of the dialect
+func (d Dialect) String() string {
+	if name, ok := dialectNames[d]; ok {
+		return name
+	}
+	return fmt.Sprintf("Dialect(%d)", int(d))
+}
+
+// ParseDialect returns the dialect of the given name, e.g. "postgresql", "postgres" or "mysql". An empty name
+// returns the generic dialect.
+func ParseDialect(name string) (Dialect, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	for dialect, dialectName := range dialectNames {
+		if name == dialectName {
+			return dialect, nil
+		}
+	}
+	if dialect, ok := dialectAliases[name]; ok {
+		return dialect, nil
+	}
+	return Generic, fmt.Errorf("unknown dialect '%s'", name)
+}
+
+// nestsComments returns whether the dialect allows nested block comments, e.g. /* outer /* inner */ outer */
+func (d Dialect) nestsComments() bool {
+	switch d {
+	case MySQL, SQLite, Oracle:
+		return false
+	}
+	return true
+}
+
+// lookupTables holds the keywords, functions and operators recognized by the tokenizer for a dialect
+type lookupTables struct {
+	keywords  map[string]TokenType // Keywords, function keywords and data types
+	context   map[string]TokenType // Keywords only recognized in certain positions of a statement
+	functions map[string]TokenType // Functions, only recognized if followed by a parenthesis
+	operators map[string]TokenType // Arithmetic, concatenation, JSON and other operators
+	symbols   string               // Characters operators and comparators are comprised out of
+}
+
+// dialectTables holds the prepared lookup tables of each dialect
+var dialectTables = prepareDialectTables()
+
+// prepareDialectTables merges the common lookup tables with the ones specific to each dialect. The generic
+// dialect merges the ones of all dialects.
+func prepareDialectTables() map[Dialect]*lookupTables {
+	tables := make(map[Dialect]*lookupTables, len(dialectNames))
+	for dialect := range dialectNames {
+		tables[dialect] = &lookupTables{
+			keywords:  mergeMaps(keywordMap, typeMap),
+			context:   mergeMaps(contextKeywordMap),
+			functions: mergeMaps(functionMap),
+			operators: mergeMaps(operatorMap),
+		}
+	}
+	for dialect := range dialectNames {
+		if dialect == Generic {
+			continue
+		}
+		for _, target := range []*lookupTables{tables[dialect], tables[Generic]} {
+			addToMap(target.keywords, dialectKeywordMap[dialect], dialectTypeMap[dialect])
+			addToMap(target.context, dialectContextKeywordMap[dialect])
+			addToMap(target.functions, dialectFunctionMap[dialect])
+			addToMap(target.operators, dialectOperatorMap[dialect])
+		}
+	}
+	for _, target := range tables {
+		target.symbols = symbolsOf(target.operators)
+	}
+	return tables
+}
+
+// symbolsOf returns the characters the given operators and all comparators are comprised out of
+func symbolsOf(operators map[string]TokenType) string {
+	symbols := comparatorCharacters
+	for operator := range operators {
+		for _, ch := range operator {
+			if !strings.ContainsRune(symbols, ch) {
+				symbols += string(ch)
+			}
+		}
+	}
+	return symbols
+}
+
+// tablesOf returns the lookup tables of a dialect, or the generic ones if the dialect is unknown
+func tablesOf(dialect Dialect) *lookupTables {
+	if tables, ok := dialectTables[dialect]; ok {
+		return tables
+	}
+	return dialectTables[Generic]
+}
+
+// mergeMaps returns a new map containing the entries of all given maps
+func mergeMaps(maps ...map[string]TokenType) map[string]TokenType {
+	merged := make(map[string]TokenType)
+	addToMap(merged, maps...)
+	return merged
+}
+
+// addToMap adds the entries of all given maps to the target map
+func addToMap(target map[string]TokenType, maps ...map[string]TokenType) {
+	for _, m := range maps {
+		for key, value := range m {
+			target[key] = value
+		}
+	}
+}
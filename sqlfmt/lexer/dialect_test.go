@@ -0,0 +1,134 @@
+package lexer
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestParseDialect(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    Dialect
+		wantErr bool
+	}{
+		{name: "", want: Generic},
+		{name: "generic", want: Generic},
+		{name: "ANSI", want: ANSI},
+		{name: "postgresql", want: PostgreSQL},
+		{name: "Postgres", want: PostgreSQL},
+		{name: "mysql", want: MySQL},
+		{name: "mariadb", want: MySQL},
+		{name: "sqlite", want: SQLite},
+		{name: "mssql", want: SQLServer},
+		{name: " oracle ", want: Oracle},
+		{name: "db2", want: Generic, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseDialect(tt.name)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.Nil(t, err)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	// Dialect names must be parsable again
+	for dialect, name := range dialectNames {
+		got, err := ParseDialect(dialect.String())
+		assert.Nil(t, err)
+		assert.Equalf(t, dialect, got, "dialect %s", name)
+	}
+}
+
+func TestTokenizeWithConfig_Dialect(t *testing.T) {
+	tests := []struct {
+		sql     string
+		dialect Dialect
+		want    []TokenType
+	}{
+		{sql: "dump(a)", dialect: Generic, want: []TokenType{FUNCTION, STARTPARENTHESIS, IDENT, ENDPARENTHESIS, EOF}},
+		{sql: "dump(a)", dialect: Oracle, want: []TokenType{FUNCTION, STARTPARENTHESIS, IDENT, ENDPARENTHESIS, EOF}},
+		{sql: "dump(a)", dialect: MySQL, want: []TokenType{IDENT, STARTPARENTHESIS, IDENT, ENDPARENTHESIS, EOF}},
+		{sql: "count(a)", dialect: ANSI, want: []TokenType{FUNCTION, STARTPARENTHESIS, IDENT, ENDPARENTHESIS, EOF}},
+		{sql: "from user", dialect: PostgreSQL, want: []TokenType{FROM, FUNCTIONKEYWORD, EOF}},
+		{sql: "from user", dialect: MySQL, want: []TokenType{FROM, IDENT, EOF}},
+		{sql: "a ilike b", dialect: PostgreSQL, want: []TokenType{IDENT, ILIKE, IDENT, EOF}},
+		{sql: "a ilike b", dialect: SQLServer, want: []TokenType{IDENT, IDENT, IDENT, EOF}},
+		{sql: "merge into t", dialect: SQLServer, want: []TokenType{MERGE, INTO, IDENT, EOF}},
+		{sql: "merge into t", dialect: MySQL, want: []TokenType{IDENT, INTO, IDENT, EOF}},
+		{sql: "insert into t values (1) on conflict do nothing", dialect: PostgreSQL, want: []TokenType{
+			INSERT, INTO, IDENT, VALUES, STARTPARENTHESIS, NUMBER, ENDPARENTHESIS, ON, CONFLICT, DO, NOTHING, EOF,
+		}},
+		{sql: "insert into t values (1) on conflict do nothing", dialect: MySQL, want: []TokenType{
+			INSERT, INTO, IDENT, VALUES, STARTPARENTHESIS, NUMBER, ENDPARENTHESIS, ON, IDENT, DO, IDENT, EOF,
+		}},
+		{sql: "insert into t values (1) on duplicate key", dialect: MySQL, want: []TokenType{
+			INSERT, INTO, IDENT, VALUES, STARTPARENTHESIS, NUMBER, ENDPARENTHESIS, ON, DUPLICATE, KEY, EOF,
+		}},
+		{sql: "create materialized view", dialect: PostgreSQL, want: []TokenType{CREATE, MATERIALIZED, VIEW, EOF}},
+		{sql: "create materialized view", dialect: MySQL, want: []TokenType{CREATE, IDENT, VIEW, EOF}},
+		{sql: "create trigger trg before insert", dialect: MySQL, want: []TokenType{CREATE, TRIGGER, IDENT, BEFORE, INSERT, EOF}},
+		{sql: "create trigger trg after insert on t for each row execute function f", dialect: PostgreSQL, want: []TokenType{
+			CREATE, TRIGGER, IDENT, AFTER, INSERT, ON, IDENT, FOR, EACH, ROW, EXECUTE, ROUTINE, IDENT, EOF,
+		}},
+		{sql: "grant select on t to r", dialect: MySQL, want: []TokenType{GRANT, SELECT, ON, IDENT, TO, IDENT, EOF}},
+		{sql: "create policy p on t", dialect: PostgreSQL, want: []TokenType{CREATE, POLICY, IDENT, ON, IDENT, EOF}},
+		{sql: "a varbit", dialect: PostgreSQL, want: []TokenType{IDENT, TYPE, EOF}},
+		{sql: "a varbit", dialect: MySQL, want: []TokenType{IDENT, IDENT, EOF}},
+		{sql: "a varchar2", dialect: Oracle, want: []TokenType{IDENT, TYPE, EOF}},
+		{sql: "a->>b", dialect: MySQL, want: []TokenType{IDENT, OPERATOR, IDENT, EOF}},
+		{sql: "a @> b", dialect: PostgreSQL, want: []TokenType{IDENT, OPERATOR, IDENT, EOF}},
+		{sql: "a ~* b", dialect: PostgreSQL, want: []TokenType{IDENT, OPERATOR, IDENT, EOF}},
+		{sql: "a !~* b", dialect: PostgreSQL, want: []TokenType{IDENT, OPERATOR, IDENT, EOF}},
+		{sql: "a <=> b", dialect: MySQL, want: []TokenType{IDENT, OPERATOR, IDENT, EOF}},
+		{sql: "a || b", dialect: ANSI, want: []TokenType{IDENT, OPERATOR, IDENT, EOF}},
+		{sql: "a->>b", dialect: ANSI, want: []TokenType{IDENT, OPERATOR, IDENT, EOF}},
+		{sql: "a->>b", dialect: SQLServer, want: []TokenType{IDENT, OPERATOR, IDENT, EOF}},
+		{sql: "a->>b", dialect: Oracle, want: []TokenType{IDENT, OPERATOR, IDENT, EOF}},
+		{sql: "a <=> b", dialect: PostgreSQL, want: []TokenType{IDENT, OPERATOR, IDENT, EOF}},
+		{sql: "a <-> b", dialect: MySQL, want: []TokenType{IDENT, OPERATOR, IDENT, EOF}},
+		{sql: "set a += 1, b -= 1, c *= 2, d /= 2", dialect: SQLServer, want: []TokenType{
+			SET, IDENT, OPERATOR, NUMBER, COMMA, IDENT, OPERATOR, NUMBER, COMMA, IDENT, OPERATOR, NUMBER, COMMA,
+			IDENT, OPERATOR, NUMBER, EOF,
+		}},
+		{sql: "a=-1, a*-b", dialect: ANSI, want: []TokenType{IDENT, COMPARATOR, NUMBER, COMMA, IDENT, OPERATOR, IDENT, EOF}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.dialect.String()+": "+tt.sql, func(t *testing.T) {
+			got, err := TokenizeWithConfig(tt.sql, Config{Dialect: tt.dialect})
+			assert.Nil(t, err)
+			var gotTypes []TokenType
+			for _, token := range got {
+				gotTypes = append(gotTypes, token.Type)
+			}
+			assert.Equal(t, tt.want, gotTypes)
+		})
+	}
+}
+
+func TestTokenizeWithConfig_DialectFunctions(t *testing.T) {
+	tests := map[Dialect][]string{
+		Generic:    {"count", "round", "greatest", "to_char", "decode", "len", "ifnull"},
+		ANSI:       {"count", "coalesce", "round", "replace", "ltrim", "rtrim", "sign", "concat"},
+		PostgreSQL: {"round", "greatest", "least", "sign", "replace", "concat", "to_char", "random"},
+		MySQL:      {"round", "ascii", "sign", "replace", "concat", "ifnull", "greatest", "year"},
+		SQLite:     {"round", "length", "replace", "substr", "ltrim", "rtrim", "ifnull", "random"},
+		SQLServer:  {"round", "ltrim", "rtrim", "replace", "concat", "len", "isnull", "left"},
+		Oracle: {
+			"round", "to_char", "to_date", "decode", "substr", "length", "replace", "instr", "trunc", "ltrim",
+			"rtrim", "nvl",
+		},
+	}
+	for dialect, functions := range tests {
+		for _, function := range functions {
+			t.Run(dialect.String()+": "+function, func(t *testing.T) {
+				got, err := TokenizeWithConfig(function+"(a)", Config{Dialect: dialect})
+				assert.Nil(t, err)
+				assert.Equal(t, FUNCTION, got[0].Type)
+			})
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package lexer
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Error describes a failure to tokenize the SQL input at a certain location. It can be retrieved from wrapped
+// errors via errors.As.
+type Error struct {
+	Position        // Location of the first character of the offending text
+	Text     string // Offending text, e.g. an unterminated string literal
+	Snippet  string // Line of the input containing the offending text, followed by a caret pointing at it
+	Err      error  // Underlying cause of the failure
+}
+
+// Error returns a human-readable description of the failure
+func (e *Error) Error() string {
+	return fmt.Sprintf("tokenizer error at %s: %v", e.Position, e.Err)
+}
+
+// Unwrap returns the underlying cause of the failure
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// newError creates an error describing a failure to scan the token currently being scanned
+func (t *tokenizer) newError(err error) *Error {
+
+	// Complete the line up to the offending text with its first line
+	text := t.raw.String()
+	line, _, cut := strings.Cut(text, "\n")
+	if !cut {
+
+		// Peek into buffered characters to complete the line, without reading them
+		buffered, _ := t.r.Peek(t.r.Buffered())
+		rest, _, _ := strings.Cut(string(buffered), "\n")
+		line += rest
+	}
+	line = t.line.String() + strings.TrimRight(line, "\r")
+
+	// Align caret with the offending character. Tabs are kept, so that it is aligned in any editor.
+	var caret strings.Builder
+	for _, ch := range t.line.String() {
+		if isTab(ch) {
+			caret.WriteRune(ch)
+		} else {
+			caret.WriteRune(' ')
+		}
+	}
+	caret.WriteRune('^')
+
+	// Return error
+	return &Error{
+		Position: t.pos,
+		Text:     text,
+		Snippet:  line + "\n" + caret.String(),
+		Err:      err,
+	}
+}
@@ -0,0 +1,62 @@
+package lexer
+
+import (
+	"errors"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestError(t *testing.T) {
+	tests := []struct {
+		sql     string
+		want    Position
+		text    string
+		snippet string
+		message string
+	}{
+		{
+			sql:     "select 'open",
+			want:    Position{Offset: 7, Line: 1, Column: 8},
+			text:    "'open",
+			snippet: "select 'open\n       ^",
+			message: "tokenizer error at line 1, column 8: unexpected EOF expected closing quote",
+		},
+		{
+			sql:     "select a,\n\tb || \"x\ny",
+			want:    Position{Offset: 16, Line: 2, Column: 7},
+			text:    "\"x\ny",
+			snippet: "\tb || \"x\n\t     ^",
+			message: "tokenizer error at line 2, column 7: unexpected EOF expected closing quote",
+		},
+		{
+			sql:     "select a from t\nwhere b = $$abc",
+			want:    Position{Offset: 26, Line: 2, Column: 11},
+			text:    "$$abc",
+			snippet: "where b = $$abc\n          ^",
+			message: "tokenizer error at line 2, column 11: unexpected EOF expected closing $$",
+		},
+		{
+			sql:     "select a /* unterminated\ncomment",
+			want:    Position{Offset: 9, Line: 1, Column: 10},
+			text:    "/* unterminated\ncomment",
+			snippet: "select a /* unterminated\n         ^",
+			message: "tokenizer error at line 1, column 10: unexpected EOF expected closing */",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.sql, func(t *testing.T) {
+			_, err := Tokenize(tt.sql)
+
+			// Verify that the error can be retrieved from a wrapped error
+			var errLexer *Error
+			if !assert.True(t, errors.As(fmt.Errorf("wrapped: %w", err), &errLexer)) {
+				return
+			}
+			assert.Equal(t, tt.want, errLexer.Position)
+			assert.Equal(t, tt.text, errLexer.Text)
+			assert.Equal(t, tt.snippet, errLexer.Snippet)
+			assert.Equal(t, tt.message, errLexer.Error())
+		})
+	}
+}
@@ -0,0 +1,125 @@
+package lexer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Registry holds additional functions, keywords, data types and function keywords extending the lookup tables
+// of the selected dialect, e.g. functions of database extensions like PostGIS or custom types like CITEXT.
+// Names are case-insensitive. Register all names before tokenizing, a registry must not be modified while
+// it is in use.
+type Registry struct {
+	functions map[string]struct{}  // Function names, optionally qualified by a schema, e.g. "myschema.fn"
+	keywords  map[string]TokenType // Keywords, function keywords and data types
+}
+
+// NewRegistry returns an empty registry
+func NewRegistry() *Registry {
+	return &Registry{
+		functions: make(map[string]struct{}),
+		keywords:  make(map[string]TokenType),
+	}
+}
+
+// RegisterFunctions registers additional function names. Functions are only recognized if they are followed by
+// a parenthesis, possibly separated by whitespace. Names may be qualified by a schema, e.g. "myschema.fn", to
+// only match the qualified usage, which also matches the quoted schema, e.g. "myschema".fn.
+func (r *Registry) RegisterFunctions(names ...string) {
+	for _, name := range names {
+		r.functions[strings.ToUpper(name)] = struct{}{}
+	}
+}
+
+// RegisterTypes registers additional data types, e.g. CITEXT, LTREE or custom enums
+func (r *Registry) RegisterTypes(names ...string) {
+	for _, name := range names {
+		r.keywords[strings.ToUpper(name)] = TYPE
+	}
+}
+
+// RegisterFunctionKeywords registers additional functions without parenthesis, e.g. CURRENT_ROLE. They are
+// treated as common names if function keywords are disabled.
+func (r *Registry) RegisterFunctionKeywords(names ...string) {
+	for _, name := range names {
+		r.keywords[strings.ToUpper(name)] = FUNCTIONKEYWORD
+	}
+}
+
+// RegisterKeyword registers an additional keyword, which is treated like keywords of the given token type,
+// e.g. "RLIKE" as LIKE. Only token types of keywords, data types and function keywords are accepted.
+func (r *Registry) RegisterKeyword(name string, ttype TokenType) error {
+	if ttype < UNION && ttype != TYPE && ttype != FUNCTIONKEYWORD {
+		return fmt.Errorf("invalid keyword token type %d for '%s'", ttype, name)
+	}
+	r.keywords[strings.ToUpper(name)] = ttype
+	return nil
+}
+
+// isFunction checks whether a name is a registered function, either by its plain or its qualified name
+func (r *Registry) isFunction(name string, qualified string) bool {
+	if r == nil {
+		return false
+	}
+	if _, ok := r.functions[name]; ok {
+		return true
+	}
+	_, ok := r.functions[qualified]
+	return ok
+}
+
+// keyword looks up the token type of a registered keyword
+func (r *Registry) keyword(name string) (TokenType, bool) {
+	if r == nil {
+		return 0, false
+	}
+	ttype, ok := r.keywords[name]
+	return ttype, ok
+}
+
+// registryFile describes the JSON structure of a registry file
+type registryFile struct {
+	Functions        []string          `json:"functions"`
+	Types            []string          `json:"types"`
+	FunctionKeywords []string          `json:"function_keywords"`
+	Keywords         map[string]string `json:"keywords"` // Additional keywords mapped to the keyword they behave like
+}
+
+// LoadRegistry reads a registry from JSON, e.g.:
+//
+//	{
+//	  "functions": ["ST_Distance", "myschema.fn"],
+//	  "types": ["CITEXT", "LTREE"],
+//	  "function_keywords": ["CURRENT_ROLE"],
+//	  "keywords": {"RLIKE": "LIKE"}
+//	}
+func LoadRegistry(reader io.Reader) (*Registry, error) {
+
+	// Decode registry file, unknown fields are most likely typos
+	var file registryFile
+	decoder := json.NewDecoder(reader)
+	decoder.DisallowUnknownFields()
+	if errDecode := decoder.Decode(&file); errDecode != nil {
+		return nil, fmt.Errorf("invalid registry: %w", errDecode)
+	}
+
+	// Register names
+	r := NewRegistry()
+	r.RegisterFunctions(file.Functions...)
+	r.RegisterTypes(file.Types...)
+	r.RegisterFunctionKeywords(file.FunctionKeywords...)
+	for name, like := range file.Keywords {
+		ttype, ok := tablesOf(Generic).keywords[strings.ToUpper(like)]
+		if !ok {
+			return nil, fmt.Errorf("invalid registry: unknown keyword '%s' for '%s'", like, name)
+		}
+		if errRegister := r.RegisterKeyword(name, ttype); errRegister != nil {
+			return nil, fmt.Errorf("invalid registry: %w", errRegister)
+		}
+	}
+
+	// Return registry
+	return r, nil
+}
@@ -0,0 +1,99 @@
+package lexer
+
+import (
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestRegistry(t *testing.T) {
+	registry := NewRegistry()
+	registry.RegisterFunctions("st_distance", "myschema.fn")
+	registry.RegisterTypes("CITEXT")
+	registry.RegisterFunctionKeywords("current_role")
+	assert.Nil(t, registry.RegisterKeyword("rlike", LIKE))
+	assert.Error(t, registry.RegisterKeyword("x", IDENT))
+
+	sql := "st_distance(a, b) myschema.fn(c) otherschema.fn(d) e::citext current_role f rlike g"
+	want := []Token{
+		{Type: FUNCTION, Value: "ST_DISTANCE"},
+		{Type: STARTPARENTHESIS, Value: "("},
+		{Type: IDENT, Value: "a"},
+		{Type: COMMA, Value: ","},
+		{Type: IDENT, Value: "b"},
+		{Type: ENDPARENTHESIS, Value: ")"},
+		{Type: FUNCTION, Value: "myschema.FN"},
+		{Type: STARTPARENTHESIS, Value: "("},
+		{Type: IDENT, Value: "c"},
+		{Type: ENDPARENTHESIS, Value: ")"},
+		{Type: IDENT, Value: "otherschema.fn"},
+		{Type: STARTPARENTHESIS, Value: "("},
+		{Type: IDENT, Value: "d"},
+		{Type: ENDPARENTHESIS, Value: ")"},
+		{Type: IDENT, Value: "e"},
+		{Type: DOUBLECOLON, Value: "::"},
+		{Type: TYPE, Value: "CITEXT"},
+		{Type: FUNCTIONKEYWORD, Value: "CURRENT_ROLE"},
+		{Type: IDENT, Value: "f"},
+		{Type: LIKE, Value: "RLIKE"},
+		{Type: IDENT, Value: "g"},
+		{Type: EOF, Value: "EOF"},
+	}
+
+	got, err := TokenizeWithConfig(sql, Config{Registry: registry})
+	assert.Nil(t, err)
+	if assert.Len(t, got, len(want)) {
+		for i := range want {
+			assert.Equal(t, want[i].Type, got[i].Type)
+			assert.Equal(t, want[i].Value, got[i].Value)
+		}
+	}
+
+	// Registered functions might be qualified by a quoted schema and separated from their parenthesis by whitespace
+	got, err = TokenizeWithConfig(`"MySchema".fn (a), st_distance
+(b), "myschema"."fn"(c), fn (d)`, Config{Registry: registry})
+	assert.Nil(t, err)
+	var gotTypes []TokenType
+	var gotValues []string
+	for _, token := range got {
+		if token.Type == FUNCTION || token.Type == QUOTED_IDENT || token.Type == IDENT {
+			gotTypes = append(gotTypes, token.Type)
+			gotValues = append(gotValues, token.Value)
+		}
+	}
+	assert.Equal(t, []TokenType{FUNCTION, IDENT, FUNCTION, IDENT, QUOTED_IDENT, IDENT, IDENT, IDENT}, gotTypes)
+	assert.Equal(t, []string{`"MySchema".FN`, "a", "ST_DISTANCE", "b", `"myschema"."fn"`, "c", "fn", "d"}, gotValues)
+
+	// Registered function keywords are disabled like built-in ones
+	got, err = TokenizeWithConfig("current_role", Config{Registry: registry, DisableFunctionKeywords: true})
+	assert.Nil(t, err)
+	assert.Equal(t, IDENT, got[0].Type)
+}
+
+func TestLoadRegistry(t *testing.T) {
+	registry, err := LoadRegistry(strings.NewReader(`{
+		"functions": ["ST_Distance"],
+		"types": ["ltree"],
+		"function_keywords": ["CURRENT_ROLE"],
+		"keywords": {"RLIKE": "like"}
+	}`))
+	if assert.Nil(t, err) {
+		assert.True(t, registry.isFunction("ST_DISTANCE", "ST_DISTANCE"))
+		for name, want := range map[string]TokenType{"LTREE": TYPE, "CURRENT_ROLE": FUNCTIONKEYWORD, "RLIKE": LIKE} {
+			got, ok := registry.keyword(name)
+			assert.True(t, ok)
+			assert.Equal(t, want, got)
+		}
+	}
+
+	// Invalid registries
+	for _, data := range []string{
+		`{"functions": "ST_Distance"}`,
+		`{"function": ["ST_Distance"]}`,
+		`{"keywords": {"RLIKE": "UNKNOWN"}}`,
+		`{"keywords": {"X": "("}}`,
+	} {
+		_, errLoad := LoadRegistry(strings.NewReader(data))
+		assert.Errorf(t, errLoad, "registry %s", data)
+	}
+}
@@ -9,7 +9,50 @@ import (
 // Token is a token struct
 type Token struct {
 	Type  TokenType
-	Value string
+	Value string   // Normalized value, e.g. upper-cased keywords
+	Raw   string   // Original text as it appeared in the input
+	Start Position // Location of the first character of the token
+	End   Position // Location directly after the last character of the token
+
+	Leading  string // White-spaces, new-lines and tabs preceding the token, if trivia is kept
+	Trailing string // White-spaces and tabs following the token up to and including the line break, if trivia is kept
+}
+
+// Reconstruct returns the original input from a sequence of tokens, which was tokenized with trivia kept
+func Reconstruct(tokens []Token) string {
+	var sb strings.Builder
+	for _, token := range tokens {
+		sb.WriteString(token.Leading)
+		sb.WriteString(token.Raw)
+		sb.WriteString(token.Trailing)
+	}
+	return sb.String()
+}
+
+// Position describes a location within the SQL input
+type Position struct {
+	Offset int // Byte offset, starting at 0
+	Line   int // Line number, starting at 1
+	Column int // Column number counted in characters, starting at 1
+}
+
+// String returns a human-readable representation of the position
+func (p Position) String() string {
+	return fmt.Sprintf("line %d, column %d", p.Line, p.Column)
+}
+
+// advance returns the position reached after consuming the given text
+func (p Position) advance(text string) Position {
+	p.Offset += len(text)
+	for _, ch := range text {
+		if ch == '\n' {
+			p.Line++
+			p.Column = 1
+		} else {
+			p.Column++
+		}
+	}
+	return p
 }
 
 // TokenType is an alias representing a kind of token
@@ -22,9 +65,11 @@ const (
 	NEWLINE
 	TAB
 	COMMA
+	SEMICOLON
 	COLON
 	DOUBLECOLON
 	COMMENT
+	HINT // optimizer hint comments, such as /*+ INDEX(t idx) */
 	FUNCTION
 	FUNCTIONKEYWORD
 	STARTPARENTHESIS
@@ -34,10 +79,15 @@ const (
 	STARTBRACE
 	ENDBRACE
 	COMPARATOR
+	OPERATOR
 	SURROUNDING
 	TYPE
-	IDENT  // field or table name
-	STRING // values surrounded with single quotes
+	IDENT        // field or table name
+	QUOTED_IDENT // field or table name surrounded with double quotes
+	STRING       // values surrounded with single quotes
+	NUMBER       // numeric literals, such as 42, -1.5e-3 or 0x1F
+	PLACEHOLDER  // bind parameters, such as $1, ?, :name or @p1
+	DATA         // data of COPY ... FROM STDIN up to its terminating line "\."
 	UNION
 	SELECT
 	DISTINCT
@@ -119,6 +169,44 @@ const (
 	WITH
 	PRIMARY
 	KEY
+	MERGE
+	MATCHED
+	CONFLICT
+	DUPLICATE
+	NOTHING
+	PARTITION
+	WINDOW
+	RANGE
+	GROUPS
+	UNBOUNDED
+	PRECEDING
+	FOLLOWING
+	CURRENT
+	ROW
+	EXCLUDE
+	TIES
+	OTHERS
+	NO
+	REPLACE
+	VIEW
+	MATERIALIZED
+	INDEX
+	UNIQUE
+	CONCURRENTLY
+	INCLUDE
+	TABLESPACE
+	ROUTINE
+	TRIGGER
+	EVENT
+	BEFORE
+	AFTER
+	INSTEAD
+	EACH
+	EXECUTE
+	GRANT
+	REVOKE
+	ROLE
+	POLICY
 
 	SHOW
 	DISCARD
@@ -136,136 +224,146 @@ const (
 
 // Define end keywords for each clause segment
 var (
-	EndOfSelect      = []TokenType{FROM, UNION, WHERE, ENDPARENTHESIS, EOF}
-	EndOfCase        = []TokenType{END, EOF}
-	EndOfFrom        = []TokenType{WHERE, INNER, OUTER, LEFT, RIGHT, JOIN, NATURAL, CROSS, ORDER, GROUP, UNION, OFFSET, LIMIT, FETCH, EXCEPT, INTERSECT, ENDPARENTHESIS, EOF}
-	EndOfJoin        = []TokenType{WHERE, ORDER, GROUP, LIMIT, OFFSET, FETCH, LEFT, RIGHT, INNER, OUTER, NATURAL, CROSS, UNION, EXCEPT, INTERSECT, ENDPARENTHESIS, EOF}
-	EndOfWhere       = []TokenType{GROUP, ORDER, LIMIT, OFFSET, FETCH, UNION, EXCEPT, INTERSECT, RETURNING, ENDPARENTHESIS, EOF}
-	EndOfAnd         = []TokenType{GROUP, ORDER, LIMIT, OFFSET, FETCH, UNION, EXCEPT, INTERSECT, AND, OR, ENDPARENTHESIS, EOF}
-	EndOfOr          = []TokenType{GROUP, ORDER, LIMIT, OFFSET, FETCH, UNION, EXCEPT, INTERSECT, AND, OR, ENDPARENTHESIS, EOF}
-	EndOfGroupBy     = []TokenType{ORDER, LIMIT, FETCH, OFFSET, UNION, EXCEPT, INTERSECT, HAVING, ENDPARENTHESIS, EOF}
-	EndOfHaving      = []TokenType{LIMIT, OFFSET, FETCH, ORDER, UNION, EXCEPT, INTERSECT, ENDPARENTHESIS, EOF}
-	EndOfOrderBy     = []TokenType{LIMIT, FETCH, OFFSET, UNION, EXCEPT, INTERSECT, ENDPARENTHESIS, EOF}
-	EndOfLimitClause = []TokenType{UNION, EXCEPT, INTERSECT, ENDPARENTHESIS, EOF}
-	EndOfParenthesis = []TokenType{ENDPARENTHESIS, EOF}
-	EndOfTieClause   = []TokenType{SELECT, STARTPARENTHESIS, EOF}
-	EndOfUpdate      = []TokenType{WHERE, SET, RETURNING, EOF}
-	EndOfSet         = []TokenType{FROM, WHERE, RETURNING, EOF}
-	EndOfReturning   = []TokenType{EOF}
-	EndOfCreate      = []TokenType{ENDPARENTHESIS, EOF}
-	EndOfAlter       = []TokenType{ENDPARENTHESIS, EOF}
-	EndOfAdd         = []TokenType{ENDPARENTHESIS, EOF}
-	EndOfDelete      = []TokenType{ENDPARENTHESIS, EOF}
-	EndOfDrop        = []TokenType{ENDPARENTHESIS, EOF}
-	EndOfInsert      = []TokenType{SET, VALUES, EOF}
-	EndOfValues      = []TokenType{UPDATE, RETURNING, EOF}
-	EndOfType        = []TokenType{ENDPARENTHESIS, EOF}
-	EndOfLock        = []TokenType{EOF}
-	EndOfWith        = []TokenType{ENDPARENTHESIS, EOF}
-	EndOfFunction    = []TokenType{ENDPARENTHESIS, EOF}
-	EndOfShow        = []TokenType{ENDPARENTHESIS, EOF}
-	EndOfDiscard     = []TokenType{ENDPARENTHESIS, EOF}
-	EndOfBegin       = []TokenType{ENDPARENTHESIS, EOF}
-	EndOfSavepoint   = []TokenType{ENDPARENTHESIS, EOF}
-	EndOfRollback    = []TokenType{ENDPARENTHESIS, EOF}
-	EndOfCommit      = []TokenType{ENDPARENTHESIS, EOF}
-	EndOfAnalyze     = []TokenType{ENDPARENTHESIS, EOF}
-	EndOfVacuum      = []TokenType{ENDPARENTHESIS, EOF}
-	EndOfReset       = []TokenType{ENDPARENTHESIS, EOF}
-	EndOfCopy        = []TokenType{EOF}
-	EndOfExplain     = []TokenType{SELECT, INSERT, UPDATE, DELETE, VALUES, WITH, EOF}
-	EndOfComment     []TokenType // Empty slice means anything is end token
+	EndOfSelect        = []TokenType{FROM, UNION, WHERE, ENDPARENTHESIS, EOF}
+	EndOfCase          = []TokenType{END, EOF}
+	EndOfFrom          = []TokenType{WHERE, INNER, OUTER, LEFT, RIGHT, JOIN, NATURAL, CROSS, ORDER, GROUP, UNION, OFFSET, LIMIT, FETCH, EXCEPT, INTERSECT, WINDOW, ENDPARENTHESIS, EOF}
+	EndOfJoin          = []TokenType{WHERE, ORDER, GROUP, LIMIT, OFFSET, FETCH, LEFT, RIGHT, INNER, OUTER, NATURAL, CROSS, UNION, EXCEPT, INTERSECT, WINDOW, ENDPARENTHESIS, EOF}
+	EndOfWhere         = []TokenType{GROUP, ORDER, LIMIT, OFFSET, FETCH, UNION, EXCEPT, INTERSECT, RETURNING, WINDOW, ENDPARENTHESIS, EOF}
+	EndOfAnd           = []TokenType{GROUP, ORDER, LIMIT, OFFSET, FETCH, UNION, EXCEPT, INTERSECT, AND, OR, WINDOW, ENDPARENTHESIS, EOF}
+	EndOfOr            = []TokenType{GROUP, ORDER, LIMIT, OFFSET, FETCH, UNION, EXCEPT, INTERSECT, AND, OR, WINDOW, ENDPARENTHESIS, EOF}
+	EndOfGroupBy       = []TokenType{ORDER, LIMIT, FETCH, OFFSET, UNION, EXCEPT, INTERSECT, HAVING, WINDOW, ENDPARENTHESIS, EOF}
+	EndOfHaving        = []TokenType{LIMIT, OFFSET, FETCH, ORDER, UNION, EXCEPT, INTERSECT, WINDOW, ENDPARENTHESIS, EOF}
+	EndOfOrderBy       = []TokenType{LIMIT, FETCH, OFFSET, UNION, EXCEPT, INTERSECT, ENDPARENTHESIS, EOF}
+	EndOfLimitClause   = []TokenType{UNION, EXCEPT, INTERSECT, ENDPARENTHESIS, EOF}
+	EndOfParenthesis   = []TokenType{ENDPARENTHESIS, EOF}
+	EndOfTieClause     = []TokenType{SELECT, STARTPARENTHESIS, EOF}
+	EndOfUpdate        = []TokenType{WHERE, SET, RETURNING, EOF}
+	EndOfSet           = []TokenType{FROM, WHERE, RETURNING, EOF}
+	EndOfReturning     = []TokenType{EOF}
+	EndOfCreate        = []TokenType{ENDPARENTHESIS, EOF}
+	EndOfAlter         = []TokenType{ENDPARENTHESIS, EOF}
+	EndOfAdd           = []TokenType{ENDPARENTHESIS, EOF}
+	EndOfDelete        = []TokenType{ENDPARENTHESIS, EOF}
+	EndOfDrop          = []TokenType{ENDPARENTHESIS, EOF}
+	EndOfInsert        = []TokenType{SET, VALUES, EOF}
+	EndOfValues        = []TokenType{UPDATE, RETURNING, EOF}
+	EndOfType          = []TokenType{ENDPARENTHESIS, EOF}
+	EndOfLock          = []TokenType{EOF}
+	EndOfWith          = []TokenType{ENDPARENTHESIS, EOF}
+	EndOfFunction      = []TokenType{ENDPARENTHESIS, EOF}
+	EndOfShow          = []TokenType{ENDPARENTHESIS, EOF}
+	EndOfDiscard       = []TokenType{ENDPARENTHESIS, EOF}
+	EndOfBegin         = []TokenType{ENDPARENTHESIS, EOF}
+	EndOfSavepoint     = []TokenType{ENDPARENTHESIS, EOF}
+	EndOfRollback      = []TokenType{ENDPARENTHESIS, EOF}
+	EndOfCommit        = []TokenType{ENDPARENTHESIS, EOF}
+	EndOfAnalyze       = []TokenType{ENDPARENTHESIS, EOF}
+	EndOfVacuum        = []TokenType{ENDPARENTHESIS, EOF}
+	EndOfReset         = []TokenType{ENDPARENTHESIS, EOF}
+	EndOfCopy          = []TokenType{EOF}
+	EndOfDo            = []TokenType{EOF}
+	EndOfExplain       = []TokenType{SELECT, INSERT, UPDATE, DELETE, VALUES, WITH, MERGE, EOF}
+	EndOfMerge         = []TokenType{EOF}
+	EndOfConflict      = []TokenType{RETURNING, EOF}
+	EndOfOver          = []TokenType{ENDPARENTHESIS, EOF}
+	EndOfWindow        = []TokenType{ORDER, LIMIT, OFFSET, FETCH, UNION, EXCEPT, INTERSECT, ENDPARENTHESIS, EOF}
+	EndOfCreateIndex   = []TokenType{EOF}
+	EndOfCreateRoutine = []TokenType{EOF}
+	EndOfCreateTrigger = []TokenType{EOF}
+	EndOfCreateRole    = []TokenType{EOF}
+	EndOfCreatePolicy  = []TokenType{EOF}
+	EndOfGrant         = []TokenType{EOF}
+	EndOfComment       []TokenType // Empty slice means anything is end token
 )
 
 // Define keywords indicating certain segment groups
 var (
-	TokenTypesOfGroupMaker  = []TokenType{SELECT, CASE, FROM, WHERE, ORDER, GROUP, LIMIT, AND, OR, HAVING, UNION, EXCEPT, INTERSECT, FUNCTION, STARTPARENTHESIS, TYPE, WITH}
+	TokenTypesOfGroupMaker  = []TokenType{SELECT, CASE, FROM, WHERE, ORDER, GROUP, LIMIT, AND, OR, HAVING, UNION, EXCEPT, INTERSECT, FUNCTION, STARTPARENTHESIS, TYPE, WITH, MERGE, OVER, WINDOW}
 	TokenTypesOfJoinMaker   = []TokenType{JOIN, INNER, OUTER, LEFT, RIGHT, NATURAL, CROSS}
 	TokenTypesOfTieClause   = []TokenType{UNION, INTERSECT, EXCEPT}
 	TokenTypesOfLimitClause = []TokenType{LIMIT, FETCH, OFFSET}
 )
 
+// keywordMap defines keywords known to all dialects
 var keywordMap = map[string]TokenType{
-	"SELECT":      SELECT,
-	"FROM":        FROM,
-	"WHERE":       WHERE,
-	"CASE":        CASE,
-	"ORDER":       ORDER,
-	"BY":          BY,
-	"AS":          AS,
-	"JOIN":        JOIN,
-	"LEFT":        LEFT,
-	"RIGHT":       RIGHT,
-	"INNER":       INNER,
-	"OUTER":       OUTER,
-	"ON":          ON,
-	"WHEN":        WHEN,
-	"END":         END,
-	"GROUP":       GROUP,
-	"DESC":        DESC,
-	"ASC":         ASC,
-	"LIMIT":       LIMIT,
-	"OVER":        OVER,
-	"AND":         AND,
-	"OR":          OR,
-	"IN":          IN,
-	"ANY":         ANY,
-	"ARRAY":       ARRAY,
-	"IS":          IS,
-	"IF":          IF,
-	"NOT":         NOT,
-	"NULL":        NULL,
-	"DISTINCT":    DISTINCT,
-	"LIKE":        LIKE,
-	"ILIKE":       ILIKE,
-	"BETWEEN":     BETWEEN,
-	"UNION":       UNION,
-	"ALL":         ALL,
-	"HAVING":      HAVING,
-	"EXISTS":      EXISTS,
-	"UPDATE":      UPDATE,
-	"SET":         SET,
-	"RETURNING":   RETURNING,
-	"CREATE":      CREATE,
-	"ALTER":       ALTER,
-	"ADD":         ADD,
-	"RENAME":      RENAME,
-	"MODIFY":      MODIFY,
-	"COLUMN":      COLUMN,
-	"TABLE":       TABLE,
-	"DATABASE":    DATABASE,
-	"TO":          TO,
-	"DROP":        DROP,
-	"DELETE":      DELETE,
-	"INSERT":      INSERT,
-	"INTO":        INTO,
-	"DO":          DO,
-	"VALUES":      VALUES,
-	"FOR":         FOR,
-	"THEN":        THEN,
-	"ELSE":        ELSE,
-	"DISTINCTROW": DISTINCTROW,
-	"FILTER":      FILTER,
-	"WITHIN":      WITHIN,
-	"COLLATE":     COLLATE,
-	"INTERSECT":   INTERSECT,
-	"EXCEPT":      EXCEPT,
-	"OFFSET":      OFFSET,
-	"FETCH":       FETCH,
-	"FIRST":       FIRST,
-	"ROWS":        ROWS,
-	"USING":       USING,
-	"OVERLAPS":    OVERLAPS,
-	"NATURAL":     NATURAL,
-	"CROSS":       CROSS,
-	"ZONE":        ZONE,
-	"NULLS":       NULLS,
-	"LAST":        LAST,
-	"AT":          AT,
-	"LOCK":        LOCK,
-	"WITH":        WITH,
-	"PRIMARY":     PRIMARY,
-	"KEY":         KEY,
+	"SELECT":    SELECT,
+	"FROM":      FROM,
+	"WHERE":     WHERE,
+	"CASE":      CASE,
+	"ORDER":     ORDER,
+	"BY":        BY,
+	"AS":        AS,
+	"JOIN":      JOIN,
+	"LEFT":      LEFT,
+	"RIGHT":     RIGHT,
+	"INNER":     INNER,
+	"OUTER":     OUTER,
+	"ON":        ON,
+	"WHEN":      WHEN,
+	"END":       END,
+	"GROUP":     GROUP,
+	"DESC":      DESC,
+	"ASC":       ASC,
+	"LIMIT":     LIMIT,
+	"OVER":      OVER,
+	"AND":       AND,
+	"OR":        OR,
+	"IN":        IN,
+	"ANY":       ANY,
+	"ARRAY":     ARRAY,
+	"IS":        IS,
+	"IF":        IF,
+	"NOT":       NOT,
+	"NULL":      NULL,
+	"DISTINCT":  DISTINCT,
+	"LIKE":      LIKE,
+	"BETWEEN":   BETWEEN,
+	"UNION":     UNION,
+	"ALL":       ALL,
+	"HAVING":    HAVING,
+	"EXISTS":    EXISTS,
+	"UPDATE":    UPDATE,
+	"SET":       SET,
+	"RETURNING": RETURNING,
+	"CREATE":    CREATE,
+	"ALTER":     ALTER,
+	"ADD":       ADD,
+	"RENAME":    RENAME,
+	"MODIFY":    MODIFY,
+	"COLUMN":    COLUMN,
+	"TABLE":     TABLE,
+	"DATABASE":  DATABASE,
+	"TO":        TO,
+	"DROP":      DROP,
+	"DELETE":    DELETE,
+	"INSERT":    INSERT,
+	"INTO":      INTO,
+	"DO":        DO,
+	"VALUES":    VALUES,
+	"FOR":       FOR,
+	"THEN":      THEN,
+	"ELSE":      ELSE,
+	"FILTER":    FILTER,
+	"WITHIN":    WITHIN,
+	"COLLATE":   COLLATE,
+	"INTERSECT": INTERSECT,
+	"EXCEPT":    EXCEPT,
+	"OFFSET":    OFFSET,
+	"FETCH":     FETCH,
+	"FIRST":     FIRST,
+	"ROWS":      ROWS,
+	"USING":     USING,
+	"OVERLAPS":  OVERLAPS,
+	"NATURAL":   NATURAL,
+	"CROSS":     CROSS,
+	"ZONE":      ZONE,
+	"NULLS":     NULLS,
+	"LAST":      LAST,
+	"AT":        AT,
+	"LOCK":      LOCK,
+	"WITH":      WITH,
+	"PRIMARY":   PRIMARY,
+	"KEY":       KEY,
 
 	/*
 	 * Special queries
@@ -282,12 +380,135 @@ var keywordMap = map[string]TokenType{
 	"RESET":     RESET,
 	"COPY":      COPY,
 	"EXPLAIN":   EXPLAIN,
+}
 
-	/*
-	 * Data types
-	 */
+// contextKeywordMap defines keywords known to all dialects, which are only recognized in certain positions of a
+// statement, see statementContext. Elsewhere, they are common names, e.g. a column named "window" or "range".
+var contextKeywordMap = map[string]TokenType{
+	"WINDOW":    WINDOW,
+	"PARTITION": PARTITION,
+	"RANGE":     RANGE,
+	"GROUPS":    GROUPS,
+	"UNBOUNDED": UNBOUNDED,
+	"PRECEDING": PRECEDING,
+	"FOLLOWING": FOLLOWING,
+	"CURRENT":   CURRENT,
+	"ROW":       ROW,
+	"EXCLUDE":   EXCLUDE,
+	"TIES":      TIES,
+	"OTHERS":    OTHERS,
+	"NO":        NO,
+	"REPLACE":   REPLACE,
+	"UNIQUE":    UNIQUE,
+	"VIEW":      VIEW,
+	"INDEX":     INDEX,
+	"INCLUDE":   INCLUDE,
+	"TRIGGER":   TRIGGER,
+	"EVENT":     EVENT,
+	"BEFORE":    BEFORE,
+	"AFTER":     AFTER,
+	"INSTEAD":   INSTEAD,
+	"EACH":      EACH,
+	"EXECUTE":   EXECUTE,
+	"GRANT":     GRANT,
+	"REVOKE":    REVOKE,
+	"ROLE":      ROLE,
+	"POLICY":    POLICY,
+	"FUNCTION":  ROUTINE,
+	"PROCEDURE": ROUTINE,
+	"ROUTINE":   ROUTINE,
+}
+
+// dialectContextKeywordMap defines keywords only known to certain dialects, in addition to the ones of
+// contextKeywordMap, which are only recognized in certain positions of a statement, see statementContext
+var dialectContextKeywordMap = map[Dialect]map[string]TokenType{
+	ANSI: {
+		"MERGE":   MERGE,
+		"MATCHED": MATCHED,
+	},
+	PostgreSQL: {
+		"MERGE":        MERGE,
+		"MATCHED":      MATCHED,
+		"CONFLICT":     CONFLICT,
+		"NOTHING":      NOTHING,
+		"MATERIALIZED": MATERIALIZED,
+		"CONCURRENTLY": CONCURRENTLY,
+		"TABLESPACE":   TABLESPACE,
+	},
+	MySQL: {
+		"DUPLICATE": DUPLICATE,
+	},
+	SQLite: {
+		"CONFLICT": CONFLICT,
+		"NOTHING":  NOTHING,
+	},
+	SQLServer: {
+		"MERGE":   MERGE,
+		"MATCHED": MATCHED,
+	},
+	Oracle: {
+		"MERGE":        MERGE,
+		"MATCHED":      MATCHED,
+		"MATERIALIZED": MATERIALIZED,
+		"TABLESPACE":   TABLESPACE,
+	},
+}
+
+// dialectKeywordMap defines keywords only known to certain dialects, in addition to the ones of keywordMap.
+// Some dialects know functions without parenthesis. They look like normal keywords, but they might conflict
+// with table/column names, e.g. "user", which is why they are only recognized for dialects defining them.
+var dialectKeywordMap = map[Dialect]map[string]TokenType{
+	ANSI: {
+		"LOCALTIME":         FUNCTIONKEYWORD,
+		"LOCALTIMESTAMP":    FUNCTIONKEYWORD,
+		"CURRENT_DATE":      FUNCTIONKEYWORD,
+		"CURRENT_TIME":      FUNCTIONKEYWORD,
+		"CURRENT_TIMESTAMP": FUNCTIONKEYWORD,
+		"CURRENT_USER":      FUNCTIONKEYWORD,
+		"SESSION_USER":      FUNCTIONKEYWORD,
+	},
+	PostgreSQL: {
+		"ILIKE":             ILIKE,
+		"LOCALTIME":         FUNCTIONKEYWORD,
+		"LOCALTIMESTAMP":    FUNCTIONKEYWORD,
+		"CURRENT_DATE":      FUNCTIONKEYWORD,
+		"CURRENT_TIME":      FUNCTIONKEYWORD,
+		"CURRENT_TIMESTAMP": FUNCTIONKEYWORD,
+		"CURRENT_USER":      FUNCTIONKEYWORD,
+		"CURRENT_CATALOG":   FUNCTIONKEYWORD,
+		"SESSION_USER":      FUNCTIONKEYWORD,
+		"USER":              FUNCTIONKEYWORD,
+	},
+	MySQL: {
+		"DISTINCTROW":       DISTINCTROW,
+		"LOCALTIME":         FUNCTIONKEYWORD,
+		"LOCALTIMESTAMP":    FUNCTIONKEYWORD,
+		"CURRENT_DATE":      FUNCTIONKEYWORD,
+		"CURRENT_TIME":      FUNCTIONKEYWORD,
+		"CURRENT_TIMESTAMP": FUNCTIONKEYWORD,
+		"CURRENT_USER":      FUNCTIONKEYWORD,
+	},
+	SQLite: {
+		"CURRENT_DATE":      FUNCTIONKEYWORD,
+		"CURRENT_TIME":      FUNCTIONKEYWORD,
+		"CURRENT_TIMESTAMP": FUNCTIONKEYWORD,
+	},
+	SQLServer: {
+		"CURRENT_TIMESTAMP": FUNCTIONKEYWORD,
+		"CURRENT_USER":      FUNCTIONKEYWORD,
+		"SESSION_USER":      FUNCTIONKEYWORD,
+	},
+	Oracle: {
+		"LOCALTIMESTAMP":    FUNCTIONKEYWORD,
+		"CURRENT_DATE":      FUNCTIONKEYWORD,
+		"CURRENT_TIMESTAMP": FUNCTIONKEYWORD,
+		"USER":              FUNCTIONKEYWORD,
+	},
+}
+
+// typeMap defines data types known to all dialects
+var typeMap = map[string]TokenType{
 	"BIG":        TYPE,
-	"BIGSERIAL":  TYPE,
 	"BOOLEAN":    TYPE,
 	"CHAR":       TYPE,
 	"BIT":        TYPE,
@@ -300,32 +521,96 @@ var keywordMap = map[string]TokenType{
 	"FLOAT":      TYPE,
 	"CUSTOMTYPE": TYPE,
 	"VARCHAR":    TYPE,
-	"VARBIT":     TYPE,
 	"TIMESTAMP":  TYPE,
 	"TIME":       TYPE,
 	"SECOND":     TYPE,
 	"INTERVAL":   TYPE,
+}
 
-	/*
-	 * Additional Postgres functions without parenthesis.
-	 * These
-	 */
-	"LOCALTIME":         FUNCTIONKEYWORD,
-	"LOCALTIMESTAMP":    FUNCTIONKEYWORD,
-	"CURRENT_DATE":      FUNCTIONKEYWORD,
-	"CURRENT_TIME":      FUNCTIONKEYWORD,
-	"CURRENT_TIMESTAMP": FUNCTIONKEYWORD,
-	"CURRENT_USER":      FUNCTIONKEYWORD,
-	"CURRENT_CATALOG":   FUNCTIONKEYWORD,
-	"SESSION_USER":      FUNCTIONKEYWORD,
-	"USER":              FUNCTIONKEYWORD,
+// dialectTypeMap defines data types only known to certain dialects, in addition to the ones of typeMap
+var dialectTypeMap = map[Dialect]map[string]TokenType{
+	PostgreSQL: {
+		"BIGSERIAL": TYPE,
+		"VARBIT":    TYPE,
+	},
+	MySQL: {
+		"TINYINT":   TYPE,
+		"MEDIUMINT": TYPE,
+	},
+	SQLServer: {
+		"NVARCHAR": TYPE,
+	},
+	Oracle: {
+		"VARCHAR2":  TYPE,
+		"NVARCHAR2": TYPE,
+	},
 }
 
+// functionMap defines standard SQL functions known to all dialects
 var functionMap = map[string]TokenType{
+	"ABS":              FUNCTION,
+	"ACOS":             FUNCTION,
+	"ASIN":             FUNCTION,
+	"ATAN":             FUNCTION,
+	"AVG":              FUNCTION,
+	"CAST":             FUNCTION,
+	"CEIL":             FUNCTION,
+	"CEILING":          FUNCTION,
+	"CHARACTER_LENGTH": FUNCTION,
+	"CHAR_LENGTH":      FUNCTION,
+	"COALESCE":         FUNCTION,
+	"CONCAT":           FUNCTION,
+	"CORR":             FUNCTION,
+	"COS":              FUNCTION,
+	"COUNT":            FUNCTION,
+	"COVAR_POP":        FUNCTION,
+	"COVAR_SAMP":       FUNCTION,
+	"CUME_DIST":        FUNCTION,
+	"DENSE_RANK":       FUNCTION,
+	"EXP":              FUNCTION,
+	"EXTRACT":          FUNCTION,
+	"FIRST_VALUE":      FUNCTION,
+	"FLOOR":            FUNCTION,
+	"LAG":              FUNCTION,
+	"LAST_VALUE":       FUNCTION,
+	"LEAD":             FUNCTION,
+	"LN":               FUNCTION,
+	"LOG":              FUNCTION,
+	"LOWER":            FUNCTION,
+	"LTRIM":            FUNCTION,
+	"MAX":              FUNCTION,
+	"MIN":              FUNCTION,
+	"MOD":              FUNCTION,
+	"NTH_VALUE":        FUNCTION,
+	"NTILE":            FUNCTION,
+	"NULLIF":           FUNCTION,
+	"OCTET_LENGTH":     FUNCTION,
+	"PERCENTILE_CONT":  FUNCTION,
+	"PERCENTILE_DISC":  FUNCTION,
+	"PERCENT_RANK":     FUNCTION,
+	"POSITION":         FUNCTION,
+	"POWER":            FUNCTION,
+	"RANK":             FUNCTION,
+	"REPLACE":          FUNCTION,
+	"ROUND":            FUNCTION,
+	"ROW_NUMBER":       FUNCTION,
+	"RTRIM":            FUNCTION,
+	"SIGN":             FUNCTION,
+	"SIN":              FUNCTION,
+	"SQRT":             FUNCTION,
+	"STDDEV_POP":       FUNCTION,
+	"STDDEV_SAMP":      FUNCTION,
+	"SUBSTRING":        FUNCTION,
+	"SUM":              FUNCTION,
+	"TAN":              FUNCTION,
+	"TRIM":             FUNCTION,
+	"UPPER":            FUNCTION,
+	"VAR_POP":          FUNCTION,
+	"VAR_SAMP":         FUNCTION,
+}
 
-	/*
-	 * PostgreSQL functions
-	 */
+// postgresFunctionMap defines additional PostgreSQL functions
+var postgresFunctionMap = map[string]TokenType{
 	"BIT_LENGTH":                         FUNCTION,
 	"CHAR_LENGTH":                        FUNCTION,
 	"LOWER":                              FUNCTION,
@@ -338,7 +623,6 @@ var functionMap = map[string]TokenType{
 	"ASCII":                              FUNCTION,
 	"BTRIM":                              FUNCTION,
 	"CHR":                                FUNCTION,
-	"CONCAT":                             FUNCTION,
 	"CONCAT_WS":                          FUNCTION,
 	"CONVERT":                            FUNCTION,
 	"CONVERT_FROM":                       FUNCTION,
@@ -350,7 +634,6 @@ var functionMap = map[string]TokenType{
 	"LEFT":                               FUNCTION,
 	"LENGTH":                             FUNCTION,
 	"LPAD":                               FUNCTION,
-	"LTRIM":                              FUNCTION,
 	"MD5":                                FUNCTION,
 	"PG_CLIENT_ENCODING":                 FUNCTION,
 	"QUOTE_IDENT":                        FUNCTION,
@@ -361,11 +644,9 @@ var functionMap = map[string]TokenType{
 	"REGEXP_SPLIT_TO_ARRAY":              FUNCTION,
 	"REGEXP_SPLIT_TO_TABLE":              FUNCTION,
 	"REPEAT":                             FUNCTION,
-	"REPLACE":                            FUNCTION,
 	"REVERSE":                            FUNCTION,
 	"RIGHT":                              FUNCTION,
 	"RPAD":                               FUNCTION,
-	"RTRIM":                              FUNCTION,
 	"SPLIT_PART":                         FUNCTION,
 	"STRPOS":                             FUNCTION,
 	"SUBSTR":                             FUNCTION,
@@ -531,7 +812,7 @@ var functionMap = map[string]TokenType{
 	"GENERATE_SUBSCRIPTS":                FUNCTION,
 	"CURRENT_DATABASE":                   FUNCTION,
 	"CURRENT_QUERY":                      FUNCTION,
-	"CURRENT_SCHEMA[":                    FUNCTION,
+	"CURRENT_SCHEMA":                     FUNCTION,
 	"CURRENT_SCHEMAS":                    FUNCTION,
 	"INET_CLIENT_ADDR":                   FUNCTION,
 	"INET_CLIENT_PORT":                   FUNCTION,
@@ -640,10 +921,174 @@ var functionMap = map[string]TokenType{
 	"PG_TRY_ADVISORY_LOCK_SHARED":        FUNCTION,
 	"PG_TRY_ADVISORY_XACT_LOCK":          FUNCTION,
 	"PG_TRY_ADVISORY_XACT_LOCK_SHARED":   FUNCTION,
+	"GREATEST":                           FUNCTION,
+	"LEAST":                              FUNCTION,
+	"PI":                                 FUNCTION,
+	"DEGREES":                            FUNCTION,
+	"RADIANS":                            FUNCTION,
+	"COT":                                FUNCTION,
+	"ATAN2":                              FUNCTION,
+	"LOG10":                              FUNCTION,
+	"RANDOM":                             FUNCTION,
+}
 
-	/*
-	 * Additional SQL Functions
-	 */
+// mysqlFunctionMap defines additional MySQL functions
+var mysqlFunctionMap = map[string]TokenType{
+	"ADDDATE":         FUNCTION,
+	"ADDTIME":         FUNCTION,
+	"AES_DECRYPT":     FUNCTION,
+	"AES_ENCRYPT":     FUNCTION,
+	"ANY_VALUE":       FUNCTION,
+	"BIN":             FUNCTION,
+	"BIT_COUNT":       FUNCTION,
+	"CHAR":            FUNCTION,
+	"CONCAT_WS":       FUNCTION,
+	"CONV":            FUNCTION,
+	"CONVERT_TZ":      FUNCTION,
+	"CRC32":           FUNCTION,
+	"CURDATE":         FUNCTION,
+	"CURTIME":         FUNCTION,
+	"DATE_ADD":        FUNCTION,
+	"DATE_FORMAT":     FUNCTION,
+	"DATE_SUB":        FUNCTION,
+	"DATEDIFF":        FUNCTION,
+	"DAYNAME":         FUNCTION,
+	"DAYOFMONTH":      FUNCTION,
+	"DAYOFWEEK":       FUNCTION,
+	"DAYOFYEAR":       FUNCTION,
+	"ELT":             FUNCTION,
+	"FIELD":           FUNCTION,
+	"FIND_IN_SET":     FUNCTION,
+	"FORMAT":          FUNCTION,
+	"FOUND_ROWS":      FUNCTION,
+	"FROM_BASE64":     FUNCTION,
+	"FROM_DAYS":       FUNCTION,
+	"FROM_UNIXTIME":   FUNCTION,
+	"GREATEST":        FUNCTION,
+	"GROUP_CONCAT":    FUNCTION,
+	"HEX":             FUNCTION,
+	"IFNULL":          FUNCTION,
+	"INET_ATON":       FUNCTION,
+	"INET_NTOA":       FUNCTION,
+	"INSTR":           FUNCTION,
+	"JSON_ARRAY":      FUNCTION,
+	"JSON_ARRAYAGG":   FUNCTION,
+	"JSON_CONTAINS":   FUNCTION,
+	"JSON_EXTRACT":    FUNCTION,
+	"JSON_OBJECT":     FUNCTION,
+	"JSON_OBJECTAGG":  FUNCTION,
+	"JSON_SET":        FUNCTION,
+	"JSON_UNQUOTE":    FUNCTION,
+	"LAST_INSERT_ID":  FUNCTION,
+	"LCASE":           FUNCTION,
+	"LEAST":           FUNCTION,
+	"LEFT":            FUNCTION,
+	"LENGTH":          FUNCTION,
+	"LOCATE":          FUNCTION,
+	"LPAD":            FUNCTION,
+	"MAKEDATE":        FUNCTION,
+	"MD5":             FUNCTION,
+	"MICROSECOND":     FUNCTION,
+	"MID":             FUNCTION,
+	"NOW":             FUNCTION,
+	"PERIOD_ADD":      FUNCTION,
+	"PERIOD_DIFF":     FUNCTION,
+	"RAND":            FUNCTION,
+	"REGEXP_INSTR":    FUNCTION,
+	"REGEXP_LIKE":     FUNCTION,
+	"REGEXP_REPLACE":  FUNCTION,
+	"REGEXP_SUBSTR":   FUNCTION,
+	"REPEAT":          FUNCTION,
+	"REVERSE":         FUNCTION,
+	"RIGHT":           FUNCTION,
+	"ROW_COUNT":       FUNCTION,
+	"RPAD":            FUNCTION,
+	"SEC_TO_TIME":     FUNCTION,
+	"SHA1":            FUNCTION,
+	"SHA2":            FUNCTION,
+	"SLEEP":           FUNCTION,
+	"SOUNDEX":         FUNCTION,
+	"SPACE":           FUNCTION,
+	"STR_TO_DATE":     FUNCTION,
+	"STRCMP":          FUNCTION,
+	"SUBDATE":         FUNCTION,
+	"SUBSTR":          FUNCTION,
+	"SUBSTRING_INDEX": FUNCTION,
+	"SUBTIME":         FUNCTION,
+	"SYSDATE":         FUNCTION,
+	"TIME_FORMAT":     FUNCTION,
+	"TIME_TO_SEC":     FUNCTION,
+	"TIMEDIFF":        FUNCTION,
+	"TIMESTAMPADD":    FUNCTION,
+	"TIMESTAMPDIFF":   FUNCTION,
+	"TO_BASE64":       FUNCTION,
+	"TO_DAYS":         FUNCTION,
+	"TRUNCATE":        FUNCTION,
+	"UCASE":           FUNCTION,
+	"UNHEX":           FUNCTION,
+	"UNIX_TIMESTAMP":  FUNCTION,
+	"UTC_DATE":        FUNCTION,
+	"UTC_TIME":        FUNCTION,
+	"UTC_TIMESTAMP":   FUNCTION,
+	"UUID":            FUNCTION,
+	"WEEK":            FUNCTION,
+	"WEEKDAY":         FUNCTION,
+	"WEEKOFYEAR":      FUNCTION,
+	"YEARWEEK":        FUNCTION,
+	"ASCII":           FUNCTION,
+	"PI":              FUNCTION,
+	"DEGREES":         FUNCTION,
+	"RADIANS":         FUNCTION,
+	"COT":             FUNCTION,
+	"ATAN2":           FUNCTION,
+	"YEAR":            FUNCTION,
+	"MONTH":           FUNCTION,
+	"DAY":             FUNCTION,
+}
+
+// sqliteFunctionMap defines additional SQLite functions
+var sqliteFunctionMap = map[string]TokenType{
+	"CHANGES":                   FUNCTION,
+	"GLOB":                      FUNCTION,
+	"HEX":                       FUNCTION,
+	"IFNULL":                    FUNCTION,
+	"INSTR":                     FUNCTION,
+	"LAST_INSERT_ROWID":         FUNCTION,
+	"LIKELIHOOD":                FUNCTION,
+	"LIKELY":                    FUNCTION,
+	"LOAD_EXTENSION":            FUNCTION,
+	"PRINTF":                    FUNCTION,
+	"QUOTE":                     FUNCTION,
+	"RANDOMBLOB":                FUNCTION,
+	"SQLITE_COMPILEOPTION_GET":  FUNCTION,
+	"SQLITE_COMPILEOPTION_USED": FUNCTION,
+	"SQLITE_OFFSET":             FUNCTION,
+	"SQLITE_SOURCE_ID":          FUNCTION,
+	"SQLITE_VERSION":            FUNCTION,
+	"TOTAL_CHANGES":             FUNCTION,
+	"TYPEOF":                    FUNCTION,
+	"UNHEX":                     FUNCTION,
+	"UNLIKELY":                  FUNCTION,
+	"ZEROBLOB":                  FUNCTION,
+	"GROUP_CONCAT":              FUNCTION,
+	"DATE":                      FUNCTION,
+	"TIME":                      FUNCTION,
+	"DATETIME":                  FUNCTION,
+	"JULIANDAY":                 FUNCTION,
+	"STRFTIME":                  FUNCTION,
+	"RANDOM":                    FUNCTION,
+	"LENGTH":                    FUNCTION,
+	"SUBSTR":                    FUNCTION,
+	"UNICODE":                   FUNCTION,
+	"IIF":                       FUNCTION,
+	"PI":                        FUNCTION,
+	"DEGREES":                   FUNCTION,
+	"RADIANS":                   FUNCTION,
+	"JSON_EXTRACT":              FUNCTION,
+}
+
+// sqlServerFunctionMap defines additional SQL Server functions
+var sqlServerFunctionMap = map[string]TokenType{
 	"CHAR":              FUNCTION,
 	"CHARINDEX":         FUNCTION,
 	"DATALENGTH":        FUNCTION,
@@ -659,28 +1104,19 @@ var functionMap = map[string]TokenType{
 	"STUFF":             FUNCTION,
 	"UNICODE":           FUNCTION,
 	"ABS":               FUNCTION,
-	"ACOS":              FUNCTION,
-	"ASIN":              FUNCTION,
-	"ATAN":              FUNCTION,
 	"ATN2":              FUNCTION,
 	"CEILING":           FUNCTION,
-	"COS":               FUNCTION,
 	"COT":               FUNCTION,
 	"DEGREES":           FUNCTION,
 	"EXP":               FUNCTION,
 	"FLOOR":             FUNCTION,
-	"LOG":               FUNCTION,
 	"LOG10":             FUNCTION,
 	"PI":                FUNCTION,
 	"POWER":             FUNCTION,
 	"RADIANS":           FUNCTION,
 	"RAND":              FUNCTION,
-	"ROUND":             FUNCTION,
-	"SIGN":              FUNCTION,
-	"SIN":               FUNCTION,
 	"SQRT":              FUNCTION,
 	"SQUARE":            FUNCTION,
-	"TAN":               FUNCTION,
 	"CURRENT_TIMESTAMP": FUNCTION,
 	"DATEADD":           FUNCTION,
 	"DATEDIFF":          FUNCTION,
@@ -705,43 +1141,29 @@ var functionMap = map[string]TokenType{
 	"SESSIONPROPERTY":   FUNCTION,
 	"SYSTEM_USER":       FUNCTION,
 	"USER_NAME":         FUNCTION,
+	"ASCII":             FUNCTION,
+	"LEFT":              FUNCTION,
+	"RIGHT":             FUNCTION,
+	"REVERSE":           FUNCTION,
+	"STRING_AGG":        FUNCTION,
+	"CONCAT_WS":         FUNCTION,
+	"GREATEST":          FUNCTION,
+	"LEAST":             FUNCTION,
+	"FORMAT":            FUNCTION,
+	"TRANSLATE":         FUNCTION,
+}
 
-	/*
-	 * Additional SQLite functions
-	 */
-	"CHANGES":                   FUNCTION,
-	"GLOB":                      FUNCTION,
-	"HEX":                       FUNCTION,
-	"IFNULL":                    FUNCTION,
-	"INSTR":                     FUNCTION,
-	"LAST_INSERT_ROWID":         FUNCTION,
-	"LIKELIHOOD":                FUNCTION,
-	"LIKELY":                    FUNCTION,
-	"LOAD_EXTENSION":            FUNCTION,
-	"PRINTF":                    FUNCTION,
-	"QUOTE":                     FUNCTION,
-	"RANDOMBLOB":                FUNCTION,
-	"SQLITE_COMPILEOPTION_GET":  FUNCTION,
-	"SQLITE_COMPILEOPTION_USED": FUNCTION,
-	"SQLITE_OFFSET":             FUNCTION,
-	"SQLITE_SOURCE_ID":          FUNCTION,
-	"SQLITE_VERSION":            FUNCTION,
-	"TOTAL_CHANGES":             FUNCTION,
-	"TYPEOF":                    FUNCTION,
-	"UNHEX":                     FUNCTION,
-	"UNLIKELY":                  FUNCTION,
-	"ZEROBLOB":                  FUNCTION,
-	"GROUP_CONCAT":              FUNCTION,
-	"DATE":                      FUNCTION,
-	"TIME":                      FUNCTION,
-	"DATETIME":                  FUNCTION,
-	"JULIANDAY":                 FUNCTION,
-	"STRFTIME":                  FUNCTION,
-	"RANDOM":                    FUNCTION,
+// dialectFunctionMap assigns the additional functions to their dialects
+var dialectFunctionMap = map[Dialect]map[string]TokenType{
+	PostgreSQL: postgresFunctionMap,
+	MySQL:      mysqlFunctionMap,
+	SQLite:     sqliteFunctionMap,
+	SQLServer:  sqlServerFunctionMap,
+	Oracle:     oracleFunctionMap,
+}
 
-	/*
-	 * Additional Oracle functions
-	 */
+// oracleFunctionMap defines additional Oracle functions
+var oracleFunctionMap = map[string]TokenType{
 	"ATAN2":                        FUNCTION,
 	"BITAND":                       FUNCTION,
 	"COSH":                         FUNCTION,
@@ -852,6 +1274,26 @@ var functionMap = map[string]TokenType{
 	"STATS_T_TEST_INDEPU":          FUNCTION,
 	"STATS_WSR_TEST":               FUNCTION,
 	"PERCENTILE_DISC":              FUNCTION,
+	"TO_CHAR":                      FUNCTION,
+	"TO_DATE":                      FUNCTION,
+	"TO_NUMBER":                    FUNCTION,
+	"TO_TIMESTAMP":                 FUNCTION,
+	"DECODE":                       FUNCTION,
+	"SUBSTR":                       FUNCTION,
+	"LENGTH":                       FUNCTION,
+	"INSTR":                        FUNCTION,
+	"TRUNC":                        FUNCTION,
+	"GREATEST":                     FUNCTION,
+	"LEAST":                        FUNCTION,
+	"ASCII":                        FUNCTION,
+	"CHR":                          FUNCTION,
+	"INITCAP":                      FUNCTION,
+	"LPAD":                         FUNCTION,
+	"RPAD":                         FUNCTION,
+	"REGEXP_REPLACE":               FUNCTION,
+	"LISTAGG":                      FUNCTION,
+	"TRANSLATE":                    FUNCTION,
+	"SOUNDEX":                      FUNCTION,
 }
 
 var comparatorMap = map[string]TokenType{
@@ -868,6 +1310,9 @@ var comparatorMap = map[string]TokenType{
 	"<=":   COMPARATOR,
 }
 
+// comparatorCharacters lists all characters valid comparators are comprised out of
+const comparatorCharacters = "~*!=<>"
+
 // peekComparator peeks into the subsequent characters trying to identify valid comparator substrings, but
 // tries not to match on broken substrings that are not really valid comparators.
 func peekComparator(r *bufio.Reader) (string, error) {
@@ -886,7 +1331,7 @@ func peekComparator(r *bufio.Reader) (string, error) {
 		ch := s[len(s)-1]
 
 		// Check if character is plausible comparator
-		if strings.Contains("~*!=<>", string(ch)) {
+		if strings.Contains(comparatorCharacters, string(ch)) {
 			sequence += string(ch)
 		} else {
 			break
@@ -912,6 +1357,122 @@ func peekComparator(r *bufio.Reader) (string, error) {
 	return "", nil
 }
 
+// operatorMap defines operators known to all dialects
+var operatorMap = map[string]TokenType{
+	"+":  OPERATOR, // Addition
+	"-":  OPERATOR, // Subtraction
+	"*":  OPERATOR, // Multiplication
+	"/":  OPERATOR, // Division
+	"%":  OPERATOR, // Modulo
+	"||": OPERATOR, // Concatenation
+}
+
+// dialectOperatorMap defines operators only known to certain dialects, in addition to the ones of operatorMap
+var dialectOperatorMap = map[Dialect]map[string]TokenType{
+	PostgreSQL: {
+		"^":   OPERATOR, // Exponentiation
+		"&":   OPERATOR, // Bitwise AND
+		"|":   OPERATOR, // Bitwise OR
+		"<<":  OPERATOR, // Bitwise shift left
+		">>":  OPERATOR, // Bitwise shift right
+		"|/":  OPERATOR, // Square root
+		"||/": OPERATOR, // Cube root
+		"->":  OPERATOR, // JSON field
+		"->>": OPERATOR, // JSON field as text
+		"#>":  OPERATOR, // JSON path
+		"#>>": OPERATOR, // JSON path as text
+		"#-":  OPERATOR, // JSON delete path
+		"@>":  OPERATOR, // Contains
+		"<@":  OPERATOR, // Contained by
+		"?":   OPERATOR, // JSON key exists
+		"?|":  OPERATOR, // JSON any key exists
+		"?&":  OPERATOR, // JSON all keys exist
+		"@@":  OPERATOR, // Text search match
+		"&&":  OPERATOR, // Overlap
+		"<<=": OPERATOR, // Network contained by or equal
+		">>=": OPERATOR, // Network contains or equal
+		"<->": OPERATOR, // Distance
+		"=>":  OPERATOR, // Named argument
+		"~":   OPERATOR, // Regular expression match
+		"~*":  OPERATOR, // Regular expression match, case-insensitive
+		"!~":  OPERATOR, // Regular expression mismatch
+		"!~*": OPERATOR, // Regular expression mismatch, case-insensitive
+	},
+	MySQL: {
+		"^":   OPERATOR, // Bitwise XOR
+		"&":   OPERATOR, // Bitwise AND
+		"|":   OPERATOR, // Bitwise OR
+		"<<":  OPERATOR, // Bitwise shift left
+		">>":  OPERATOR, // Bitwise shift right
+		"->":  OPERATOR, // JSON field
+		"->>": OPERATOR, // JSON field as text
+		"&&":  OPERATOR, // Logical AND
+		"<=>": OPERATOR, // Null-safe equal
+	},
+	SQLite: {
+		"&":   OPERATOR, // Bitwise AND
+		"|":   OPERATOR, // Bitwise OR
+		"<<":  OPERATOR, // Bitwise shift left
+		">>":  OPERATOR, // Bitwise shift right
+		"->":  OPERATOR, // JSON field
+		"->>": OPERATOR, // JSON field as text
+	},
+	SQLServer: {
+		"^":  OPERATOR, // Bitwise XOR
+		"&":  OPERATOR, // Bitwise AND
+		"|":  OPERATOR, // Bitwise OR
+		"+=": OPERATOR, // Add and assign
+		"-=": OPERATOR, // Subtract and assign
+		"*=": OPERATOR, // Multiply and assign
+		"/=": OPERATOR, // Divide and assign
+	},
+	Oracle: {
+		"=>": OPERATOR, // Named argument
+	},
+}
+
+// maxOperatorLength is the length of the longest operator of any dialect
+const maxOperatorLength = 3
+
+// peekOperator peeks into the subsequent characters trying to identify the longest valid operator. Comment
+// start sequences are not operators, even though they are comprised out of operator characters.
+func peekOperator(r *bufio.Reader, operators map[string]TokenType) string {
+
+	// Peek as many characters as the longest operator might have, plus one to check what follows
+	b, _ := r.Peek(maxOperatorLength + 1)
+	s := string(b)
+
+	// Abort if subsequent characters start a comment
+	if strings.HasPrefix(s, "--") || strings.HasPrefix(s, "/*") || strings.HasPrefix(s, "//") {
+		return ""
+	}
+
+	// Search for the longest operator matching the subsequent characters. An operator comprised out of
+	// comparator characters must not be followed by further comparator characters. It would be part of an
+	// invalid comparator sequence otherwise, e.g. "*=" or "=>>".
+	for i := len(s); i > 0; i-- {
+		if _, ok := operators[s[:i]]; ok {
+			if isComparatorSequence(s[:i]) && len(b) > i && isComparatorSequence(s[i:i+1]) {
+				return ""
+			}
+			return s[:i]
+		}
+	}
+
+	// Return empty string if sequence was not an operator
+	return ""
+}
+
+// isComparatorSequence checks whether a sequence is solely comprised out of comparator characters
+func isComparatorSequence(s string) bool {
+	for _, ch := range s {
+		if !strings.ContainsRune(comparatorCharacters, ch) {
+			return false
+		}
+	}
+	return s != ""
+}
+
 var punctuationMap = map[string]TokenType{
 	"(": STARTPARENTHESIS,
 	")": ENDPARENTHESIS,
@@ -920,5 +1481,6 @@ var punctuationMap = map[string]TokenType{
 	"{": STARTBRACE,
 	"}": ENDBRACKET,
 	",": COMMA,
+	";": SEMICOLON,
 	":": COLON,
 }
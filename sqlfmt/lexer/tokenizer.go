@@ -4,83 +4,305 @@ import (
 	"bufio"
 	"bytes"
 	"fmt"
+	"io"
 	"strings"
+	"unicode"
+	"unicode/utf8"
 )
 
-// DisableFunctionKeywords - Postgres has a few functions without parenthesis. They look like normal keywords,
-// but they might conflict with table/column names. To address ambiguity, Postgres clients must therefore
-// wrap affected names with double quotes. Otherwise, a name might be understood as a function keyword. In
-// other dialects clients wouldn't care and quote these names. Disable keyword functions to avoid ambiguous
-// names to be capitalized like function names.
-// Affected names: LOCALTIME, LOCALTIMESTAMP, CURRENT_DATE, CURRENT_TIME, CURRENT_TIMESTAMP, CURRENT_USER,
-// CURRENT_CATALOG, SESSION_USER, USER
-var DisableFunctionKeywords = false
+// Config defines how the tokenizer interprets the SQL input. It is passed per call, so multiple inputs can be
+// tokenized concurrently with different configurations.
+type Config struct {
+	Dialect Dialect // SQL dialect deciding which keywords, functions, types and operators are recognized
+
+	// DisableFunctionKeywords - Postgres has a few functions without parenthesis. They look like normal keywords,
+	// but they might conflict with table/column names. To address ambiguity, Postgres clients must therefore
+	// wrap affected names with double quotes. Otherwise, a name might be understood as a function keyword. In
+	// other dialects clients wouldn't care and quote these names. Disable keyword functions to avoid ambiguous
+	// names to be capitalized like function names.
+	// Affected names: LOCALTIME, LOCALTIMESTAMP, CURRENT_DATE, CURRENT_TIME, CURRENT_TIMESTAMP, CURRENT_USER,
+	// CURRENT_CATALOG, SESSION_USER, USER
+	DisableFunctionKeywords bool
+
+	// Registry defines additional functions, keywords, types and function keywords, e.g. of database extensions
+	Registry *Registry
+
+	// KeepTrivia attaches white-spaces, new-lines and tabs to the surrounding tokens instead of discarding them.
+	// Trivia following a token on the same line, up to and including the line break, becomes its trailing
+	// trivia. Any other trivia becomes leading trivia of the subsequent token, or of the EOF token at the end.
+	// The original input can then be reconstructed exactly from the tokens, see Reconstruct.
+	KeepTrivia bool
+}
 
 // Tokenize sql string and returns slice of Token. Ignores Token of white-space, new-line and tab, as
-// they have no semantic meaning.
+// they have no semantic meaning. Each Token carries its original text and location within the input.
+// Use TokenizeWithConfig with KeepTrivia enabled to retain them.
+// Keywords, functions, types and operators of all dialects are recognized.
 func Tokenize(sql string) ([]Token, error) {
+	return TokenizeWithConfig(sql, Config{})
+}
+
+// TokenizeWithConfig tokenizes the sql string like Tokenize, but according to the given configuration,
+// e.g. recognizing only keywords, functions, types and operators of a certain dialect.
+func TokenizeWithConfig(sql string, config Config) ([]Token, error) {
 
 	// Prepare tokenizer
-	t := &tokenizer{
-		r: bufio.NewReader(strings.NewReader(sql)),
-	}
+	t := NewTokenizer(strings.NewReader(sql), config)
 
 	// Execute tokenizer
 	var tokens []Token
 	for {
 
 		// Get next token
-		token, err := t.scan()
+		token, err := t.Next()
 		if err != nil {
-			return nil, fmt.Errorf("tokenizer error: %w", err)
+			return nil, err
 		}
 
-		// Abort loop at the end
-		if token.Type == EOF {
-
-			// Append EOF token to tokens, because parser will also run until EOF token
-			tokens = append(tokens, token)
+		// Append token to token slice
+		tokens = append(tokens, token)
 
-			// Return generated sequence of tokens
+		// Return generated sequence of tokens at the end. The EOF token is included, because parser will
+		// also run until EOF token.
+		if token.Type == EOF {
 			return tokens, nil
 		}
+	}
+}
 
-		// Skip empty formatting token
-		if token.Type == WHITESPACE {
-			continue
+// Tokenizer reads tokens one by one from an io.Reader, so that large inputs, such as database dumps, can be
+// processed without keeping them in memory as a whole
+type Tokenizer struct {
+	t       *tokenizer
+	config  Config
+	pending *Token // Token read ahead while collecting trailing trivia of the previous one
+	err     error  // Error encountered while collecting trailing trivia, returned with the next call
+}
+
+// NewTokenizer creates a tokenizer reading from the given reader according to the given configuration
+func NewTokenizer(r io.Reader, config Config) *Tokenizer {
+	return &Tokenizer{
+		t: &tokenizer{
+			r:      bufio.NewReader(r),
+			config: config,
+			tables: tablesOf(config.Dialect),
+			pos:    Position{Offset: 0, Line: 1, Column: 1},
+		},
+		config: config,
+	}
+}
+
+// Next returns the next token, ignoring white-spaces, new-lines and tabs like Tokenize. An EOF token is
+// returned at the end of the input, and repeatedly with any subsequent call.
+func (z *Tokenizer) Next() (Token, error) {
+
+	// Collect trivia preceding the next significant token
+	var leading strings.Builder
+	for {
+
+		// Get next token
+		token, err := z.read()
+		if err != nil {
+			return Token{}, err
 		}
-		if token.Type == NEWLINE {
+
+		// Skip empty formatting token, but keep it as trivia if desired
+		if isTrivia(token) {
+			if z.config.KeepTrivia {
+				leading.WriteString(token.Raw)
+			}
 			continue
 		}
-		if token.Type == TAB {
-			continue
+
+		// Attach collected trivia to the token
+		token.Leading = leading.String()
+
+		// Collect trivia following the token on the same line, unless it is the end
+		if z.config.KeepTrivia && token.Type != EOF {
+			z.readTrailing(&token)
 		}
 
-		// Append token to token slice
-		tokens = append(tokens, token)
+		// Return significant token
+		return token, nil
 	}
 }
 
+// read returns the token read ahead, if there is one, or scans the next one
+func (z *Tokenizer) read() (Token, error) {
+	if z.err != nil {
+		return Token{}, z.err
+	}
+	if z.pending != nil {
+		token := *z.pending
+		z.pending = nil
+		return token, nil
+	}
+	token, err := z.t.next()
+	if err != nil {
+		return Token{}, z.t.newError(err)
+	}
+	return token, nil
+}
+
+// readTrailing attaches trivia following the token on the same line, up to and including the line break. The
+// first token not belonging to it is kept for the next read.
+func (z *Tokenizer) readTrailing(token *Token) {
+	for {
+		next, err := z.read()
+		if err != nil {
+			z.err = err
+			return
+		}
+		if !isTrivia(next) {
+			z.pending = &next
+			return
+		}
+		token.Trailing += next.Raw
+		if next.Type == NEWLINE && next.Raw != "\r" { // Line ends after \n of a \r\n sequence
+			return
+		}
+	}
+}
+
+// isTrivia returns true if the token is a white-space, new-line or tab without semantic meaning
+func isTrivia(token Token) bool {
+	return token.Type == WHITESPACE || token.Type == NEWLINE || token.Type == TAB
+}
+
 // tokenizer holds a working buffer to process and defines functions to execute against it
 type tokenizer struct {
-	r *bufio.Reader
+	r      *bufio.Reader
+	config Config        // Configuration defining how to interpret the input
+	tables *lookupTables // Keywords, functions and operators of the selected dialect
+
+	raw      bytes.Buffer     // Original characters consumed for the token currently being scanned
+	rawSize  int              // Byte size of the last character read, required to revert it on unread
+	pos      Position         // Location of the next character to be scanned
+	line     strings.Builder  // Text of the current line up to the next character, required to annotate errors
+	previous TokenType        // Type of the last significant token, ignoring whitespaces and comments
+	context  statementContext // Position within the current statement, deciding about context keywords
+	data     bool             // Whether data of COPY ... FROM STDIN is read next
+}
+
+// next scans the next token and enriches it with its original text and its location within the input
+func (t *tokenizer) next() (Token, error) {
+
+	// Scan next token
+	token, err := t.scan()
+	if err != nil {
+		return Token{}, err
+	}
+
+	// Attach original text and location, then move on to the start of the next token
+	token.Raw = t.raw.String()
+	token.Start = t.pos
+	token.End = t.pos.advance(token.Raw)
+	t.pos = token.End
+	t.raw.Reset()
+
+	// Remember text of the current line consumed so far
+	if i := strings.LastIndexByte(token.Raw, '\n'); i >= 0 {
+		t.line.Reset()
+		t.line.WriteString(token.Raw[i+1:])
+	} else {
+		t.line.WriteString(token.Raw)
+	}
+
+	// Turn names into keywords, which are only keywords at their position within the statement, e.g. WINDOW
+	if token.Type == IDENT {
+		if ttype, ok := t.context.keyword(token.Value, t.previous, t.tables.context); ok {
+			token.Type = ttype
+			token.Value = strings.ToUpper(token.Value)
+		}
+	}
+
+	// Remember last significant token type, required to distinguish unary from binary operators and to
+	// recognize context keywords. Data of COPY ... FROM STDIN is no part of any statement.
+	switch token.Type {
+	case WHITESPACE, NEWLINE, TAB, COMMENT, DATA:
+	default:
+		t.context.update(token, t.previous)
+		t.previous = token.Type
+	}
+
+	// Read data of COPY ... FROM STDIN with the next token, it starts on the line following the statement
+	if t.context.data && strings.HasSuffix(token.Raw, "\n") {
+		t.context.data = false
+		t.data = true
+	}
+
+	// Return enriched token
+	return token, nil
+}
+
+// readRune reads the next character from the buffer and remembers it as part of the current token's raw text
+func (t *tokenizer) readRune() (rune, int, error) {
+	ch, size, err := t.r.ReadRune()
+	if err != nil {
+		t.rawSize = 0
+		return ch, size, err
+	}
+	t.raw.WriteRune(ch)
+	t.rawSize = size
+	return ch, size, nil
+}
+
+// unreadRune reverts the last character read from the buffer and removes it from the current token's raw text
+func (t *tokenizer) unreadRune() error {
+	if err := t.r.UnreadRune(); err != nil {
+		return err
+	}
+	t.raw.Truncate(t.raw.Len() - t.rawSize)
+	t.rawSize = 0
+	return nil
 }
 
 // scan reads the first character of the buffer and, depending on it, proceeds to read additional ones until a
 // full token is detected and returns it
 func (t *tokenizer) scan() (Token, error) {
 
-	// Peek if next characters represent a valid comparator. If so, read the according amount of bytes
-	// from the buffer and return comparator token
-	if comparatorNext, _ := peekComparator(t.r); comparatorNext != "" {
+	// Read data of COPY ... FROM STDIN as a whole, it is no SQL and must not be changed
+	if t.data {
+		t.data = false
+		return t.readData()
+	}
+
+	// Peek if next characters represent a bind parameter placeholder. If so, read the according amount of
+	// bytes from the buffer and return placeholder token
+	if placeholderNext := t.peekPlaceholder(); placeholderNext != "" {
+		for range placeholderNext {
+			_, _, _ = t.readRune()
+		}
+		return Token{Type: PLACEHOLDER, Value: placeholderNext}, nil
+	}
+
+	// Peek if next characters represent a valid comparator or operator. If so, read the according amount of
+	// bytes from the buffer and return comparator or operator token. The longest match wins, e.g. the
+	// operator "<@" over the comparator "<".
+	comparatorNext, _ := peekComparator(t.r)
+	operatorNext := peekOperator(t.r, t.tables.operators)
+	if sequenceNext := t.peekUnknownOperator(max(len(operatorNext), len(comparatorNext))); sequenceNext != "" {
+
+		// Read operator unknown to the dialect as a whole, e.g. "->>" of ANSI SQL, instead of splitting it into
+		// fragments of known operators and comparators
+		for i := len(sequenceNext); i > 0; i-- {
+			_, _, _ = t.readRune()
+		}
+		return Token{Type: OPERATOR, Value: sequenceNext}, nil
+	} else if len(operatorNext) > len(comparatorNext) && !t.isUnaryValue(operatorNext) {
+		for i := len(operatorNext); i > 0; i-- {
+			_, _, _ = t.readRune()
+		}
+		return Token{Type: OPERATOR, Value: operatorNext}, nil
+	} else if comparatorNext != "" && len(comparatorNext) >= len(operatorNext) {
 		for i := len(comparatorNext); i > 0; i-- {
-			_, _, _ = t.r.ReadRune()
+			_, _, _ = t.readRune()
 		}
 		return Token{Type: COMPARATOR, Value: comparatorNext}, nil
 	}
 
 	// Read first character from buffer
-	ch, _, errCh := t.r.ReadRune()
+	ch, _, errCh := t.readRune()
 	if errCh != nil {
 		if errCh.Error() == "EOF" {
 			return Token{Type: EOF, Value: "EOF"}, nil
@@ -110,7 +332,7 @@ func (t *tokenizer) scan() (Token, error) {
 		if isColon(ch) {
 
 			// Read next character if there is one
-			nextCh, _, errNext := t.r.ReadRune()
+			nextCh, _, errNext := t.readRune()
 			if errNext != nil {
 				if errNext.Error() == "EOF" { // Single colon was at the end of the string, which is okay
 					return Token{Type: COLON, Value: buf.String()}, nil
@@ -123,7 +345,7 @@ func (t *tokenizer) scan() (Token, error) {
 			if isColon(nextCh) {
 				return Token{Type: DOUBLECOLON, Value: fmt.Sprintf("%s%s", buf.String(), string(nextCh))}, nil
 			} else {
-				_ = t.r.UnreadRune() // Revert last read, because it belonged to the next token
+				_ = t.unreadRune() // Revert last read, because it belonged to the next token
 				return Token{Type: COLON, Value: buf.String()}, nil
 			}
 		}
@@ -136,6 +358,16 @@ func (t *tokenizer) scan() (Token, error) {
 		// Return with error in case of unexpected value
 		return Token{}, fmt.Errorf("invalid punctuation value: %v", buf.String())
 
+	case isDigit(ch) || (isPeriod(ch) && t.peekSubsequent(isDigit)) || (isSign(ch) && t.peekNumber(0)):
+
+		// Read numeric literal and return number token
+		if t.readNumber(&buf) {
+			return Token{Type: NUMBER, Value: buf.String()}, nil
+		}
+
+		// Continue reading a name otherwise, which just started with digits, e.g. MySQL's 1st_column
+		break
+
 	case isSlash(ch) || isDash(ch):
 
 		// Abort wrong comment indications
@@ -154,6 +386,11 @@ func (t *tokenizer) scan() (Token, error) {
 			return Token{}, errComment
 		}
 
+		// Return optimizer hint, if block comment starts with a plus sign, e.g. /*+ INDEX(t idx) */
+		if strings.HasPrefix(comment, "/*+") {
+			return Token{Type: HINT, Value: comment}, nil
+		}
+
 		// Return comment if one was read
 		if comment != "" {
 			return Token{Type: COMMENT, Value: comment}, nil
@@ -162,37 +399,124 @@ func (t *tokenizer) scan() (Token, error) {
 		// Continue after select with reading other tokens otherwise
 		break
 
-	case isSingleQuote(ch):
+	case isDoubleQuote(ch):
 
-		// Read subsequent characters until closing single quote
+		// Read subsequent characters until closing double quote
+		if errQuoted := t.readQuoted(&buf, isDoubleQuote, false); errQuoted != nil {
+			return Token{}, errQuoted
+		}
+
+		// Return quoted identifier token, unless it is qualifying a subsequent name, e.g. "schema".table
+		if !t.peekSubsequent(isPeriod) {
+			return Token{Type: QUOTED_IDENT, Value: buf.String()}, nil
+		}
+
+		// Continue reading the remaining qualified name otherwise
+		break
+
+	case isDollar(ch):
+
+		// Check if dollar sign opens a dollar-quoted string, e.g. $$...$$ or $tag$...$tag$. Otherwise, it is read
+		// as a common value.
+		tag := peekDollarTag(t.r)
+		if tag == "" {
+			break
+		}
+
+		// Read remaining characters of the opening delimiter
+		for i := len(tag) - 1; i > 0; i-- {
+			chTag, _, _ := t.readRune()
+			buf.WriteRune(chTag)
+		}
+
+		// Read subsequent characters until closing delimiter. The content is preserved byte for byte.
+		contentStart := buf.Len()
 		for {
-			chNext, _, errNext := t.r.ReadRune()
+			chNext, _, errNext := t.readRune()
 			if errNext != nil {
 				if errNext.Error() == "EOF" {
-					return Token{}, fmt.Errorf("unexpected EOF expected closing quote")
-				} else {
-					return Token{}, errNext
+					return Token{}, fmt.Errorf("unexpected EOF expected closing %s", tag)
 				}
+				return Token{}, errNext
 			}
 
 			// Append character to value
 			buf.WriteRune(chNext)
 
-			// Break loop once closing quote is found
-			if isSingleQuote(chNext) {
+			// Break loop once closing delimiter is found
+			if isDollar(chNext) && bytes.HasSuffix(buf.Bytes()[contentStart:], []byte(tag)) {
 				break
 			}
 		}
 
+		// Return dollar-quoted string as string token
+		return Token{Type: STRING, Value: buf.String()}, nil
+
+	case isSingleQuote(ch):
+
+		// Read subsequent characters until closing single quote
+		if errQuoted := t.readQuoted(&buf, isSingleQuote, false); errQuoted != nil {
+			return Token{}, errQuoted
+		}
+
+		// Return string token
+		return Token{Type: STRING, Value: buf.String()}, nil
+
+	case isStringPrefix(ch) && t.peekSubsequent(isSingleQuote):
+
+		// Read opening quote of prefixed string, e.g. E'...', N'...', B'...' or X'...'
+		chQuote, _, _ := t.readRune()
+		buf.WriteRune(chQuote)
+
+		// Read subsequent characters until closing single quote. Only escape strings support backslash escapes.
+		if errQuoted := t.readQuoted(&buf, isSingleQuote, isEscapePrefix(ch)); errQuoted != nil {
+			return Token{}, errQuoted
+		}
+
 		// Return string token
 		return Token{Type: STRING, Value: buf.String()}, nil
+
+	case isUnicodePrefix(ch) && t.peekUnicodeQuote():
+
+		// Read ampersand and opening quote of unicode string or identifier, e.g. U&'d\0061t' or U&"d\0061t"
+		chAmpersand, _, _ := t.readRune()
+		chQuote, _, _ := t.readRune()
+		buf.WriteRune(chAmpersand)
+		buf.WriteRune(chQuote)
+
+		// Read unicode identifier until closing double quote
+		if isDoubleQuote(chQuote) {
+			if errQuoted := t.readQuoted(&buf, isDoubleQuote, false); errQuoted != nil {
+				return Token{}, errQuoted
+			}
+			return Token{Type: QUOTED_IDENT, Value: buf.String()}, nil
+		}
+
+		// Read unicode string until closing single quote
+		if errQuoted := t.readQuoted(&buf, isSingleQuote, false); errQuoted != nil {
+			return Token{}, errQuoted
+		}
+		return Token{Type: STRING, Value: buf.String()}, nil
 	}
 
 	// Read subsequent characters until value is complete
 	var comparator = ""
 	var comparatorErr error
+loop:
 	for {
 
+		// Stop if next character starts operator sequence. Except for the asterisk of a qualified wildcard,
+		// e.g. "t.*". Operators comprised out of comparator characters must not split an invalid comparator
+		// sequence either, e.g. 'b<>>2' into 'b<' and '>>'.
+		if operator := peekOperator(t.r, t.tables.operators); operator != "" {
+			switch {
+			case operator == "*" && strings.HasSuffix(buf.String(), "."):
+			case comparatorErr != nil && isComparatorSequence(operator):
+			default:
+				break loop // Nothing was read yet, no need to unread
+			}
+		}
+
 		// Stop if next character starts comparator sequence. But only if previous check didn't return
 		// an invalid comparator sequence, otherwise an invalid comparator might turn into a valid one
 		// after reading further bytes. For example, ~~~ might be understood as ~~. An input like 'a~~~1'
@@ -205,7 +529,7 @@ func (t *tokenizer) scan() (Token, error) {
 		}
 
 		// Read next character
-		chNext, _, errNext := t.r.ReadRune()
+		chNext, _, errNext := t.readRune()
 		if errNext != nil {
 			if errNext.Error() == "EOF" {
 				break
@@ -216,12 +540,34 @@ func (t *tokenizer) scan() (Token, error) {
 
 		// Stop if next character doesn't belong to the value anymore. Unread last unnecessary character.
 		if isPunctuation(chNext) || isSingleQuote(chNext) || isWhitespace(chNext) || isNewline(chNext) || isTab(chNext) {
-			_ = t.r.UnreadRune()
+			_ = t.unreadRune()
 			break
 		}
 
 		// Append character to value
 		buf.WriteRune(chNext)
+
+		// Read double-quoted part of a qualified name as a whole, e.g. public."Order Details"
+		if isDoubleQuote(chNext) {
+			if errQuoted := t.readQuoted(&buf, isDoubleQuote, false); errQuoted != nil {
+				return Token{}, errQuoted
+			}
+		}
+	}
+
+	// Return quoted identifier without any sanitization or lookup, if any part of the name was double-quoted.
+	// Quoted names are never keywords or functions, even if they are named like one, e.g. "user" or "select".
+	// Only the schema of a function may be quoted, e.g. "myschema".fn(a).
+	if strings.ContainsRune(buf.String(), '"') {
+		idx := strings.LastIndex(buf.String(), ".")
+		if idx > 0 && !strings.ContainsRune(buf.String()[idx:], '"') {
+			key := strings.ToUpper(buf.String()[idx+1:])
+			qualified := strings.ToUpper(strings.ReplaceAll(buf.String()[:idx], `"`, "")) + "." + key
+			if t.isFunctionCall(key, qualified) {
+				return Token{Type: FUNCTION, Value: buf.String()[:idx] + "." + key}, nil
+			}
+		}
+		return Token{Type: QUOTED_IDENT, Value: buf.String()}, nil
 	}
 
 	// Prepare default lookup key and token value
@@ -237,20 +583,18 @@ func (t *tokenizer) scan() (Token, error) {
 	}
 
 	// Check if value is function name, indicated by a lookup match and a subsequent parenthesis
-	if ttype, ok := functionMap[key]; ok {
-		if ttype == FUNCTION && t.peekSubsequent(isParenthesisStart) {
-			return Token{Type: FUNCTION, Value: val}, nil
-		}
+	if t.isFunctionCall(key, strings.ToUpper(buf.String())) {
+		return Token{Type: FUNCTION, Value: val}, nil
 	}
 
 	// Check if value is keyword. Subsequent parenthesis would not indicate a function but a sub query.
-	if ttype, ok := keywordMap[val]; ok { // Use val instead of key, because "." should not be splitted for keyword lookups!
+	if ttype, ok := t.lookupKeyword(val); ok { // Use val instead of key, because "." should not be splitted for keyword lookups!
 
 		// Ambiguous edge case. Table name might (such as "user") might collide with Postgres'
 		// parenthesis-less function "USER". To address ambiguity, Postgres clients must put "user" into
 		// double quotes. However, in other databases this ambiguity does not exist, so clients would never
 		// double quote in this situation. By default, these function keywords are enabled and handled as such.
-		if ttype == FUNCTIONKEYWORD && DisableFunctionKeywords {
+		if ttype == FUNCTIONKEYWORD && t.config.DisableFunctionKeywords {
 			return Token{Type: IDENT, Value: buf.String()}, nil
 		}
 
@@ -262,17 +606,364 @@ func (t *tokenizer) scan() (Token, error) {
 	return Token{Type: IDENT, Value: buf.String()}, nil
 }
 
+// isFunctionCall checks whether a name is a function of the selected dialect or a registered one, which is
+// followed by a parenthesis. Registered functions might also be qualified by a schema, e.g. "myschema.fn", and
+// might be separated from their parenthesis by whitespace, e.g. "fn (a)".
+func (t *tokenizer) isFunctionCall(name string, qualified string) bool {
+	if t.config.Registry.isFunction(name, qualified) {
+		return t.peekParenthesis()
+	}
+	ttype, ok := t.tables.functions[name]
+	return ok && ttype == FUNCTION && t.peekSubsequent(isParenthesisStart)
+}
+
+// lookupKeyword looks up the token type of a keyword. Registered keywords take precedence over the ones of the
+// selected dialect.
+func (t *tokenizer) lookupKeyword(name string) (TokenType, bool) {
+	if ttype, ok := t.config.Registry.keyword(strings.ToUpper(name)); ok {
+		return ttype, true
+	}
+	ttype, ok := t.tables.keywords[name]
+	return ttype, ok
+}
+
+// readData reads data of COPY ... FROM STDIN up to and including its terminating line "\.", or up to the end of
+// the input. The line break following the terminating line is not part of the data.
+func (t *tokenizer) readData() (Token, error) {
+	var line bytes.Buffer
+	for {
+		ch, _, err := t.readRune()
+		if err != nil {
+			if err.Error() == "EOF" { // Data was not terminated, it ends with the input
+				return Token{Type: DATA, Value: t.raw.String()}, nil
+			}
+			return Token{}, err
+		}
+
+		// Start over with each line, the data ends with a line only comprised out of "\."
+		if isNewline(ch) {
+			line.Reset()
+			continue
+		}
+		line.WriteRune(ch)
+		if line.Len() == 2 && line.String() == `\.` {
+			if b, errPeek := t.r.Peek(1); errPeek != nil || isNewline(rune(b[0])) {
+				return Token{Type: DATA, Value: t.raw.String()}, nil
+			}
+		}
+	}
+}
+
+// readQuoted reads subsequent characters until the closing quote. A doubled quote character is an escaped
+// quote and does not terminate the quoted sequence, e.g. "Say ""hello""". If backslash escapes are enabled,
+// as within escape strings like E'it\'s', any character following a backslash is escaped too.
+func (t *tokenizer) readQuoted(buf *bytes.Buffer, isQuote func(ch rune) bool, backslashEscapes bool) error {
+	for {
+		chNext, _, errNext := t.readRune()
+		if errNext != nil {
+			if errNext.Error() == "EOF" {
+				return fmt.Errorf("unexpected EOF expected closing quote")
+			}
+			return errNext
+		}
+
+		// Append character to value
+		buf.WriteRune(chNext)
+
+		// Append escaped character without further evaluation
+		if backslashEscapes && isBackslash(chNext) {
+			chEscaped, _, errEscaped := t.readRune()
+			if errEscaped != nil {
+				return fmt.Errorf("unexpected EOF expected closing quote")
+			}
+			buf.WriteRune(chEscaped)
+			continue
+		}
+
+		// Stop at closing quote, unless it is escaped by a subsequent quote
+		if isQuote(chNext) {
+			if !t.peekSubsequent(isQuote) {
+				return nil
+			}
+			chEscaped, _, _ := t.readRune()
+			buf.WriteRune(chEscaped)
+		}
+	}
+}
+
+// isUnaryValue checks whether an operator sequence is rather part of the subsequent value than a binary
+// operator. This is the case if there is no preceding operand, e.g. the sign of a negative number ("= -1") or
+// of a negated name ("a / -b"), or the wildcard of a column list ("SELECT *"). Operands are not preceded by
+// operators, comparators, opening parenthesis, commas or keywords.
+func (t *tokenizer) isUnaryValue(operator string) bool {
+
+	// Operators following an operand are binary operators
+	if isOperand(t.previous) {
+		return false
+	}
+
+	// Asterisk without preceding operand is a wildcard
+	if operator == "*" {
+		return true
+	}
+
+	// Sign is part of the number or name, if it is directly followed by it, e.g. -1, -.5 or -b
+	if operator == "-" || operator == "+" {
+		return t.peekNumber(1) || t.peekName(1)
+	}
+
+	// Return false as operator is standalone otherwise
+	return false
+}
+
+// peekUnknownOperator peeks into the subsequent sequence of operator and comparator characters and returns it,
+// if it is longer than the known operator or comparator of the given length at its start. A single sign
+// following the known one is not part of the sequence, but of the subsequent value, e.g. "=-1" or "*-b". Invalid
+// comparator sequences directly followed by a value are not returned either, they are kept untouched as part of
+// the value, e.g. "===4". It does not consume characters.
+func (t *tokenizer) peekUnknownOperator(known int) string {
+
+	// Peek step by step into subsequent characters, until the sequence ends or a comment starts
+	sequence := ""
+	for steps := 1; ; steps++ {
+		b, errPeek := t.r.Peek(steps)
+		if errPeek != nil || !strings.ContainsRune(t.tables.symbols, rune(b[len(b)-1])) {
+			break
+		}
+		s := string(b)
+		if strings.HasSuffix(s, "--") || strings.HasSuffix(s, "/*") || strings.HasSuffix(s, "//") {
+			sequence = s[:len(s)-2]
+			break
+		}
+		sequence = s
+	}
+
+	// Return sequence, if it is not sufficiently covered by the known operator or comparator
+	switch {
+	case len(sequence) <= known:
+		return ""
+	case len(sequence) == known+1 && isSign(rune(sequence[known])):
+		return ""
+	case isComparatorSequence(sequence) && (t.peekNumber(len(sequence)) || t.peekName(len(sequence))):
+		return ""
+	}
+	return sequence
+}
+
+// peekParenthesis checks whether the subsequent characters, after skipping whitespaces, open a parenthesis. It
+// does not consume characters.
+func (t *tokenizer) peekParenthesis() bool {
+	for skip := 1; ; skip++ {
+		b, errPeek := t.r.Peek(skip)
+		if errPeek != nil {
+			return false
+		}
+		ch := rune(b[skip-1])
+		if isParenthesisStart(ch) {
+			return true
+		}
+		if !isWhitespace(ch) && !isNewline(ch) && !isTab(ch) {
+			return false
+		}
+	}
+}
+
+// peekName checks whether the subsequent characters, after skipping the given amount of bytes, start an unquoted
+// name, e.g. b. It does not consume characters.
+func (t *tokenizer) peekName(skip int) bool {
+	b, _ := t.r.Peek(skip + 1)
+	return len(b) > skip && isNameStart(rune(b[skip]))
+}
+
+// peekNumber checks whether the subsequent characters, after skipping the given amount of bytes, start an
+// unsigned number, e.g. 1 or .5. It does not consume characters.
+func (t *tokenizer) peekNumber(skip int) bool {
+	b, _ := t.r.Peek(skip + 2)
+	if len(b) > skip && isDigit(rune(b[skip])) {
+		return true
+	}
+	return len(b) > skip+1 && isPeriod(rune(b[skip])) && isDigit(rune(b[skip+1]))
+}
+
+// readNumber reads the remaining characters of a numeric literal, whose first character is already written
+// to the buffer. Supported are integers, decimals and exponents, e.g. 42, 1.5, .5 or 1e-5, digits separated
+// by underscores, e.g. 1_000_000, as well as hexadecimal, octal and binary integers, e.g. 0x1F, 0o17 or
+// 0b101. It returns false if the characters turn out to be the beginning of a name, e.g. 1st_column.
+func (t *tokenizer) readNumber(buf *bytes.Buffer) bool {
+
+	// Read first digit or period following the sign
+	if isSign(rune(buf.Bytes()[0])) {
+		ch, _, _ := t.readRune()
+		buf.WriteRune(ch)
+	}
+
+	// Read radix prefixed integer, e.g. 0x1F, 0o17 or 0b101. Underscores may follow the prefix, e.g. 0x_1F.
+	if strings.TrimLeft(buf.String(), "+-") == "0" {
+		b, _ := t.r.Peek(3)
+		if len(b) > 1 {
+			if isRadixDigit := radixDigits(rune(b[0])); isRadixDigit != nil {
+				if isRadixDigit(rune(b[1])) || (len(b) > 2 && b[1] == '_' && isRadixDigit(rune(b[2]))) {
+					ch, _, _ := t.readRune()
+					buf.WriteRune(ch)
+					t.readDigits(buf, isRadixDigit)
+					return !t.peekSubsequent(isNameCharacter)
+				}
+			}
+		}
+	}
+
+	// Read integer part, unless the number started with a period, followed by the fractional part
+	if !isPeriod(rune(buf.Bytes()[buf.Len()-1])) {
+		t.readDigits(buf, isDigit)
+		if t.peekSubsequent(isPeriod) {
+			ch, _, _ := t.readRune()
+			buf.WriteRune(ch)
+		}
+	}
+	t.readDigits(buf, isDigit)
+
+	// Read exponent, if exponent marker is followed by digits, e.g. 1e5, 1E+5 or 1.5e-5
+	b, _ := t.r.Peek(3)
+	if len(b) > 1 && (b[0] == 'e' || b[0] == 'E') {
+		if isDigit(rune(b[1])) || (len(b) > 2 && isSign(rune(b[1])) && isDigit(rune(b[2]))) {
+			ch, _, _ := t.readRune()
+			buf.WriteRune(ch)
+			if isSign(rune(b[1])) {
+				chSign, _, _ := t.readRune()
+				buf.WriteRune(chSign)
+			}
+			t.readDigits(buf, isDigit)
+		}
+	}
+
+	// Return whether number is complete or actually the beginning of a name
+	return !t.peekSubsequent(isNameCharacter)
+}
+
+// readDigits reads subsequent digits into the buffer. Single underscores between digits are accepted as
+// separators, e.g. 1_000_000.
+func (t *tokenizer) readDigits(buf *bytes.Buffer, isDigitOf func(ch rune) bool) {
+	for {
+		b, _ := t.r.Peek(2)
+		switch {
+		case len(b) > 0 && isDigitOf(rune(b[0])):
+		case len(b) > 1 && b[0] == '_' && isDigitOf(rune(b[1])):
+		default:
+			return
+		}
+		ch, _, _ := t.readRune()
+		buf.WriteRune(ch)
+	}
+}
+
+// peekPlaceholder peeks into the subsequent characters trying to identify a bind parameter placeholder of
+// common driver styles, e.g. $1 (PostgreSQL), ? or ?1 (MySQL, SQLite, JDBC), :name or :1 (Oracle, sqlx) and
+// @p1 or @name (SQL Server). Named placeholders may reference nested names, e.g. :user.id (sqlx). Question
+// marks, colons and at signs following an operand are not placeholders, but operators or slices, e.g.
+// data ? 'key' or arr[1:2].
+func (t *tokenizer) peekPlaceholder() string {
+
+	// Peek first character to decide the placeholder style
+	b, errPeek := t.r.Peek(1)
+	if errPeek != nil {
+		return ""
+	}
+	prefix := rune(b[0])
+
+	// Decide which characters may follow the prefix
+	var isFirst, isSubsequent func(ch rune) bool
+	switch {
+	case isDollar(prefix):
+		isFirst, isSubsequent = isDigit, isDigit
+	case prefix == '?' && !isOperand(t.previous):
+		isFirst, isSubsequent = nil, isDigit
+	case isColon(prefix) && !isOperand(t.previous) && t.previous != STARTBRACKET:
+		isFirst, isSubsequent = isNameCharacter, isNameCharacter
+	case prefix == '@' && !isOperand(t.previous):
+		isFirst, isSubsequent = isNameStart, isNameCharacter
+	default:
+		return ""
+	}
+
+	// Peek step by step into subsequent characters to search for the end of the placeholder
+	steps := 1
+	for {
+		b, errPeek = t.r.Peek(steps + 1)
+		if errPeek != nil {
+			break
+		}
+		ch := rune(b[steps])
+		if steps == 1 && isFirst != nil {
+			if !isFirst(ch) {
+				return "" // Not a placeholder, prefix is not followed by a valid character
+			}
+		} else if isColon(prefix) && isPeriod(ch) {
+			if next, errNext := t.r.Peek(steps + 2); errNext != nil || !isNameStart(rune(next[steps+1])) {
+				break // Period not followed by a nested name ends the placeholder
+			}
+		} else if !isSubsequent(ch) {
+			break
+		}
+		steps++
+	}
+
+	// Return empty string if prefix requires subsequent characters but there are none
+	if steps == 1 && isFirst != nil {
+		return ""
+	}
+
+	// Return placeholder
+	return string(b[:steps])
+}
+
+// peekUnicodeQuote looks into the subsequent characters following a "U" searching for the ampersand and
+// quote sequence of a unicode string or identifier, e.g. U&'...' or U&"...". It does not consume characters.
+func (t *tokenizer) peekUnicodeQuote() bool {
+	b, errPeek := t.r.Peek(2)
+	if errPeek != nil {
+		return false
+	}
+	return b[0] == '&' && (isSingleQuote(rune(b[1])) || isDoubleQuote(rune(b[1])))
+}
+
+// peekDollarTag peeks into the subsequent characters following a dollar sign, which was already read, and
+// returns the full opening delimiter of a dollar-quoted string, e.g. "$$" or "$tag$". An empty string is
+// returned if the characters do not form a valid delimiter. Tags follow the rules of unquoted identifiers,
+// so they must not start with a digit, which distinguishes them from placeholders like $1.
+func peekDollarTag(r *bufio.Reader) string {
+
+	// Peek step by step into subsequent characters to search for the closing dollar sign of the tag
+	for steps := 1; ; steps++ {
+		b, errPeek := r.Peek(steps)
+		if errPeek != nil {
+			return ""
+		}
+		ch := rune(b[len(b)-1])
+
+		// Return complete delimiter once tag is closed
+		if isDollar(ch) {
+			return "$" + string(b)
+		}
+
+		// Abort if character is not valid within a tag
+		if ch == '_' || unicode.IsLetter(ch) || ch >= utf8.RuneSelf || (steps > 1 && unicode.IsDigit(ch)) {
+			continue
+		}
+		return ""
+	}
+}
+
 // peekSubsequent looks into the subsequent characters searching for a certain follow-up character but
 // reverts all read characters at the end.
 func (t *tokenizer) peekSubsequent(isCharacter func(ch rune) bool) bool {
 
 	// Unread character at the end
 	defer func() {
-		_ = t.r.UnreadRune()
+		_ = t.unreadRune()
 	}()
 
 	// Read character
-	nextCh, _, errNext := t.r.ReadRune()
+	nextCh, _, errNext := t.readRune()
 	if errNext != nil {
 		return false
 	}
@@ -300,28 +991,46 @@ func (t *tokenizer) readComment(buf *bytes.Buffer, chPrev rune) (string, error)
 		return "", nil
 	}
 
-	// Read subsequent characters until closing single quote
+	// Read subsequent characters until end of comment. Block comments might be nested, depending on the
+	// dialect, e.g. /* outer /* inner */ outer */. They only end with the outermost termination sequence then.
+	nested := t.config.Dialect.nestsComments()
+	depth := 0
 	for {
-		chNext, _, errNext := t.r.ReadRune()
+		chNext, _, errNext := t.readRune()
 		if errNext != nil {
 			if singleLine && errNext.Error() == "EOF" {
 				return buf.String(), nil
+			} else if errNext.Error() == "EOF" {
+				return buf.String(), fmt.Errorf("unexpected EOF expected closing */")
 			} else {
 				return buf.String(), errNext
 			}
 		}
 
-		// Stop reading single-line comment at new line
+		// Stop reading single-line comment at new line. Unread the newline, because it is not part of the comment.
 		if singleLine && isNewline(chNext) {
+			_ = t.unreadRune()
 			return buf.String(), nil
 		}
 
 		// Append character to value
 		buf.WriteRune(chNext)
 
-		// Stop reading multi-line comment at termination sequence
-		if !singleLine && isAsterisk(chPrev) && isSlash(chNext) {
-			return buf.String(), nil
+		// Track opening and termination sequences of multi-line comments. Characters of a detected sequence
+		// must not be part of another one, e.g. "/*/" does not terminate the comment it opens.
+		if !singleLine {
+			if isSlash(chPrev) && isAsterisk(chNext) && (depth == 0 || nested) {
+				depth++
+				chNext = 0
+			} else if isAsterisk(chPrev) && isSlash(chNext) {
+				depth--
+				chNext = 0
+			}
+
+			// Stop reading multi-line comment at outermost termination sequence
+			if depth == 0 {
+				return buf.String(), nil
+			}
 		}
 
 		// Remember last ch
@@ -329,6 +1038,58 @@ func (t *tokenizer) readComment(buf *bytes.Buffer, chPrev rune) (string, error)
 	}
 }
 
+// isOperand checks whether a token type represents a value, which an operator could be applied to
+func isOperand(ttype TokenType) bool {
+	switch ttype {
+	case IDENT, QUOTED_IDENT, STRING, NUMBER, PLACEHOLDER, TYPE, NULL, END, FUNCTIONKEYWORD, ENDPARENTHESIS, ENDBRACKET:
+		return true
+	}
+	return false
+}
+
+func isDigit(ch rune) bool {
+	return ch >= '0' && ch <= '9'
+}
+
+func isHexDigit(ch rune) bool {
+	return isDigit(ch) || (ch >= 'a' && ch <= 'f') || (ch >= 'A' && ch <= 'F')
+}
+
+func isOctalDigit(ch rune) bool {
+	return ch >= '0' && ch <= '7'
+}
+
+func isBinaryDigit(ch rune) bool {
+	return ch == '0' || ch == '1'
+}
+
+// radixDigits returns the digit check of a radix prefix following a leading zero, e.g. 0x, 0o or 0b
+func radixDigits(prefix rune) func(ch rune) bool {
+	switch prefix {
+	case 'x', 'X':
+		return isHexDigit
+	case 'o', 'O':
+		return isOctalDigit
+	case 'b', 'B':
+		return isBinaryDigit
+	}
+	return nil
+}
+
+func isSign(ch rune) bool {
+	return ch == '-' || ch == '+'
+}
+
+// isNameStart checks whether a character may start an unquoted name
+func isNameStart(ch rune) bool {
+	return ch == '_' || (ch >= 'a' && ch <= 'z') || (ch >= 'A' && ch <= 'Z')
+}
+
+// isNameCharacter checks whether a character may be part of an unquoted name
+func isNameCharacter(ch rune) bool {
+	return isNameStart(ch) || isDigit(ch)
+}
+
 func isPunctuation(ch rune) bool {
 	_, is := punctuationMap[string(ch)]
 	return is
@@ -358,6 +1119,38 @@ func isSingleQuote(ch rune) bool {
 	return ch == '\''
 }
 
+func isDoubleQuote(ch rune) bool {
+	return ch == '"'
+}
+
+func isBackslash(ch rune) bool {
+	return ch == '\\'
+}
+
+// isStringPrefix checks whether a character may introduce a prefixed string literal, such as an escape
+// string (E'...'), a national character string (N'...'), a bit string (B'...') or a hex string (X'...')
+func isStringPrefix(ch rune) bool {
+	return strings.ContainsRune("EeNnBbXx", ch)
+}
+
+// isEscapePrefix checks whether a character introduces an escape string supporting backslash escapes
+func isEscapePrefix(ch rune) bool {
+	return ch == 'E' || ch == 'e'
+}
+
+// isUnicodePrefix checks whether a character may introduce a unicode string or identifier (U&'...')
+func isUnicodePrefix(ch rune) bool {
+	return ch == 'U' || ch == 'u'
+}
+
+func isDollar(ch rune) bool {
+	return ch == '$'
+}
+
+func isPeriod(ch rune) bool {
+	return ch == '.'
+}
+
 func isSlash(ch rune) bool {
 	return ch == '/'
 }
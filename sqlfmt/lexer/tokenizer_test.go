@@ -10,39 +10,777 @@ import (
 
 func TestTokenize(t *testing.T) {
 	var testingSQLStatement = strings.Trim(`select name, age, sum, sum(case xxx) from users where name xxx and age = 'xxx' limit 100 except 100`, "`")
+	pos := func(offset int) Position { return Position{Offset: offset, Line: 1, Column: offset + 1} }
 	want := []Token{
-		{Type: SELECT, Value: "SELECT"},
-		{Type: IDENT, Value: "name"},
-		{Type: COMMA, Value: ","},
-		{Type: IDENT, Value: "age"},
-		{Type: COMMA, Value: ","},
-		{Type: IDENT, Value: "sum"},
-		{Type: COMMA, Value: ","},
-		{Type: FUNCTION, Value: "SUM"},
-		{Type: STARTPARENTHESIS, Value: "("},
-		{Type: CASE, Value: "CASE"},
-		{Type: IDENT, Value: "xxx"},
-		{Type: ENDPARENTHESIS, Value: ")"},
-		{Type: FROM, Value: "FROM"},
-		{Type: IDENT, Value: "users"},
-		{Type: WHERE, Value: "WHERE"},
-		{Type: IDENT, Value: "name"},
-		{Type: IDENT, Value: "xxx"},
-		{Type: AND, Value: "AND"},
-		{Type: IDENT, Value: "age"},
-		{Type: COMPARATOR, Value: "="},
-		{Type: STRING, Value: "'xxx'"},
-		{Type: LIMIT, Value: "LIMIT"},
-		{Type: IDENT, Value: "100"},
-		{Type: EXCEPT, Value: "EXCEPT"},
-		{Type: IDENT, Value: "100"},
-		{Type: EOF, Value: "EOF"},
+		{Type: SELECT, Value: "SELECT", Raw: "select", Start: pos(0), End: pos(6)},
+		{Type: IDENT, Value: "name", Raw: "name", Start: pos(7), End: pos(11)},
+		{Type: COMMA, Value: ",", Raw: ",", Start: pos(11), End: pos(12)},
+		{Type: IDENT, Value: "age", Raw: "age", Start: pos(13), End: pos(16)},
+		{Type: COMMA, Value: ",", Raw: ",", Start: pos(16), End: pos(17)},
+		{Type: IDENT, Value: "sum", Raw: "sum", Start: pos(18), End: pos(21)},
+		{Type: COMMA, Value: ",", Raw: ",", Start: pos(21), End: pos(22)},
+		{Type: FUNCTION, Value: "SUM", Raw: "sum", Start: pos(23), End: pos(26)},
+		{Type: STARTPARENTHESIS, Value: "(", Raw: "(", Start: pos(26), End: pos(27)},
+		{Type: CASE, Value: "CASE", Raw: "case", Start: pos(27), End: pos(31)},
+		{Type: IDENT, Value: "xxx", Raw: "xxx", Start: pos(32), End: pos(35)},
+		{Type: ENDPARENTHESIS, Value: ")", Raw: ")", Start: pos(35), End: pos(36)},
+		{Type: FROM, Value: "FROM", Raw: "from", Start: pos(37), End: pos(41)},
+		{Type: IDENT, Value: "users", Raw: "users", Start: pos(42), End: pos(47)},
+		{Type: WHERE, Value: "WHERE", Raw: "where", Start: pos(48), End: pos(53)},
+		{Type: IDENT, Value: "name", Raw: "name", Start: pos(54), End: pos(58)},
+		{Type: IDENT, Value: "xxx", Raw: "xxx", Start: pos(59), End: pos(62)},
+		{Type: AND, Value: "AND", Raw: "and", Start: pos(63), End: pos(66)},
+		{Type: IDENT, Value: "age", Raw: "age", Start: pos(67), End: pos(70)},
+		{Type: COMPARATOR, Value: "=", Raw: "=", Start: pos(71), End: pos(72)},
+		{Type: STRING, Value: "'xxx'", Raw: "'xxx'", Start: pos(73), End: pos(78)},
+		{Type: LIMIT, Value: "LIMIT", Raw: "limit", Start: pos(79), End: pos(84)},
+		{Type: NUMBER, Value: "100", Raw: "100", Start: pos(85), End: pos(88)},
+		{Type: EXCEPT, Value: "EXCEPT", Raw: "except", Start: pos(89), End: pos(95)},
+		{Type: NUMBER, Value: "100", Raw: "100", Start: pos(96), End: pos(99)},
+		{Type: EOF, Value: "EOF", Raw: "", Start: pos(99), End: pos(99)},
 	}
 	got, err := Tokenize(testingSQLStatement)
 	assert.Nil(t, err)
 	assert.Equal(t, want, got)
 }
 
+func TestTokenize_Positions(t *testing.T) {
+	sql := "select\n  näme,\n\t\"x\" -- comment\nfrom users"
+	got, err := Tokenize(sql)
+	assert.Nil(t, err)
+
+	want := []struct {
+		raw   string
+		start Position
+		end   Position
+	}{
+		{raw: "select", start: Position{Offset: 0, Line: 1, Column: 1}, end: Position{Offset: 6, Line: 1, Column: 7}},
+		{raw: "näme", start: Position{Offset: 9, Line: 2, Column: 3}, end: Position{Offset: 14, Line: 2, Column: 7}},
+		{raw: ",", start: Position{Offset: 14, Line: 2, Column: 7}, end: Position{Offset: 15, Line: 2, Column: 8}},
+		{raw: "\"x\"", start: Position{Offset: 17, Line: 3, Column: 2}, end: Position{Offset: 20, Line: 3, Column: 5}},
+		{raw: "-- comment", start: Position{Offset: 21, Line: 3, Column: 6}, end: Position{Offset: 31, Line: 3, Column: 16}},
+		{raw: "from", start: Position{Offset: 32, Line: 4, Column: 1}, end: Position{Offset: 36, Line: 4, Column: 5}},
+		{raw: "users", start: Position{Offset: 37, Line: 4, Column: 6}, end: Position{Offset: 42, Line: 4, Column: 11}},
+		{raw: "", start: Position{Offset: 42, Line: 4, Column: 11}, end: Position{Offset: 42, Line: 4, Column: 11}},
+	}
+	if !assert.Len(t, got, len(want)) {
+		return
+	}
+	for i, w := range want {
+		assert.Equalf(t, w.raw, got[i].Raw, "raw text of token %d", i)
+		assert.Equalf(t, w.start, got[i].Start, "start of token %d", i)
+		assert.Equalf(t, w.end, got[i].End, "end of token %d", i)
+	}
+}
+
+func TestTokenizeWithConfig_KeepTrivia(t *testing.T) {
+	sql := "  select a,  b -- comment\r\n\n\tfrom users\t\n"
+	got, err := TokenizeWithConfig(sql, Config{KeepTrivia: true})
+	assert.Nil(t, err)
+
+	want := []struct {
+		raw      string
+		leading  string
+		trailing string
+	}{
+		{raw: "select", leading: "  ", trailing: " "},
+		{raw: "a", leading: "", trailing: ""},
+		{raw: ",", leading: "", trailing: "  "},
+		{raw: "b", leading: "", trailing: " "},
+		{raw: "-- comment", leading: "", trailing: "\r\n"},
+		{raw: "from", leading: "\n\t", trailing: " "},
+		{raw: "users", leading: "", trailing: "\t\n"},
+		{raw: "", leading: "", trailing: ""},
+	}
+	if !assert.Len(t, got, len(want)) {
+		return
+	}
+	for i, w := range want {
+		assert.Equalf(t, w.raw, got[i].Raw, "raw text of token %d", i)
+		assert.Equalf(t, w.leading, got[i].Leading, "leading trivia of token %d", i)
+		assert.Equalf(t, w.trailing, got[i].Trailing, "trailing trivia of token %d", i)
+	}
+
+	// Verify that the input can be reconstructed exactly
+	assert.Equal(t, sql, Reconstruct(got))
+
+	// Verify that trivia is not attached by default
+	got, err = Tokenize(sql)
+	assert.Nil(t, err)
+	for _, token := range got {
+		assert.Empty(t, token.Leading)
+		assert.Empty(t, token.Trailing)
+	}
+}
+
+func TestTokenizer_Next(t *testing.T) {
+	sql := "select a from t;\nselect b from u; -- end\n"
+	tokenizer := NewTokenizer(strings.NewReader(sql), Config{KeepTrivia: true})
+
+	// Read tokens one by one until the end
+	var got []Token
+	for {
+		token, err := tokenizer.Next()
+		if !assert.Nil(t, err) {
+			return
+		}
+		got = append(got, token)
+		if token.Type == EOF {
+			break
+		}
+	}
+
+	// Verify that tokens equal those of the whole input tokenized at once
+	want, err := TokenizeWithConfig(sql, Config{KeepTrivia: true})
+	assert.Nil(t, err)
+	assert.Equal(t, want, got)
+	assert.Equal(t, sql, Reconstruct(got))
+
+	// Verify that the EOF token is returned repeatedly at the end
+	token, err := tokenizer.Next()
+	assert.Nil(t, err)
+	assert.Equal(t, EOF, token.Type)
+}
+
+func TestTokenizer_NextError(t *testing.T) {
+	tokenizer := NewTokenizer(strings.NewReader("select 'open"), Config{})
+
+	// Verify that tokens before the error are returned
+	token, err := tokenizer.Next()
+	assert.Nil(t, err)
+	assert.Equal(t, SELECT, token.Type)
+
+	// Verify that the error is returned once it is reached
+	_, err = tokenizer.Next()
+	assert.NotNil(t, err)
+}
+
+func TestTokenize_QuotedIdent(t *testing.T) {
+	tests := []struct {
+		sql  string
+		want []Token
+	}{
+		{
+			sql: `select "select" from "user"`,
+			want: []Token{
+				{Type: SELECT, Value: "SELECT"},
+				{Type: QUOTED_IDENT, Value: `"select"`},
+				{Type: FROM, Value: "FROM"},
+				{Type: QUOTED_IDENT, Value: `"user"`},
+				{Type: EOF, Value: "EOF"},
+			},
+		},
+		{
+			sql: `"Order Details"."Unit Price", public."user", "t".col, "Say ""hi"""`,
+			want: []Token{
+				{Type: QUOTED_IDENT, Value: `"Order Details"."Unit Price"`},
+				{Type: COMMA, Value: ","},
+				{Type: QUOTED_IDENT, Value: `public."user"`},
+				{Type: COMMA, Value: ","},
+				{Type: QUOTED_IDENT, Value: `"t".col`},
+				{Type: COMMA, Value: ","},
+				{Type: QUOTED_IDENT, Value: `"Say ""hi"""`},
+				{Type: EOF, Value: "EOF"},
+			},
+		},
+		{
+			sql: `"upper"(x)`,
+			want: []Token{
+				{Type: QUOTED_IDENT, Value: `"upper"`},
+				{Type: STARTPARENTHESIS, Value: "("},
+				{Type: IDENT, Value: "x"},
+				{Type: ENDPARENTHESIS, Value: ")"},
+				{Type: EOF, Value: "EOF"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.sql, func(t *testing.T) {
+			got, err := Tokenize(tt.sql)
+			assert.Nil(t, err)
+			if assert.Len(t, got, len(tt.want)) {
+				for i := range tt.want {
+					assert.Equal(t, tt.want[i].Type, got[i].Type)
+					assert.Equal(t, tt.want[i].Value, got[i].Value)
+				}
+			}
+		})
+	}
+
+	// Unterminated quoted identifier
+	_, err := Tokenize(`select "abc`)
+	assert.Error(t, err)
+}
+
+func TestTokenize_DollarQuoted(t *testing.T) {
+	tests := []struct {
+		sql  string
+		want []Token
+	}{
+		{
+			sql: "as $$ select a + b; $$ language sql",
+			want: []Token{
+				{Type: AS, Value: "AS"},
+				{Type: STRING, Value: "$$ select a + b; $$"},
+				{Type: IDENT, Value: "language"},
+				{Type: IDENT, Value: "sql"},
+				{Type: EOF, Value: "EOF"},
+			},
+		},
+		{
+			sql: "do $body$\nBEGIN\n  raise notice 'it''s $$ here';\nEND\n$body$",
+			want: []Token{
+				{Type: DO, Value: "DO"},
+				{Type: STRING, Value: "$body$\nBEGIN\n  raise notice 'it''s $$ here';\nEND\n$body$"},
+				{Type: EOF, Value: "EOF"},
+			},
+		},
+		{
+			sql: "a=$_1$x$_1$, $1",
+			want: []Token{
+				{Type: IDENT, Value: "a"},
+				{Type: COMPARATOR, Value: "="},
+				{Type: STRING, Value: "$_1$x$_1$"},
+				{Type: COMMA, Value: ","},
+				{Type: PLACEHOLDER, Value: "$1"},
+				{Type: EOF, Value: "EOF"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.sql, func(t *testing.T) {
+			got, err := Tokenize(tt.sql)
+			assert.Nil(t, err)
+			if assert.Len(t, got, len(tt.want)) {
+				for i := range tt.want {
+					assert.Equal(t, tt.want[i].Type, got[i].Type)
+					assert.Equal(t, tt.want[i].Value, got[i].Value)
+				}
+			}
+		})
+	}
+
+	// Unterminated dollar-quoted string
+	_, err := Tokenize(`select $tag$abc$$`)
+	assert.Error(t, err)
+}
+
+func TestTokenize_CopyData(t *testing.T) {
+	tests := []struct {
+		sql  string
+		want []Token
+	}{
+		{
+			sql: "copy t (a) from stdin;\nO'Brien\n\\.x\n\\.\nselect 1",
+			want: []Token{
+				{Type: COPY, Value: "COPY"},
+				{Type: IDENT, Value: "t"},
+				{Type: STARTPARENTHESIS, Value: "("},
+				{Type: IDENT, Value: "a"},
+				{Type: ENDPARENTHESIS, Value: ")"},
+				{Type: FROM, Value: "FROM"},
+				{Type: IDENT, Value: "stdin"},
+				{Type: SEMICOLON, Value: ";"},
+				{Type: DATA, Value: "O'Brien\n\\.x\n\\."},
+				{Type: SELECT, Value: "SELECT"},
+				{Type: NUMBER, Value: "1"},
+				{Type: EOF, Value: "EOF"},
+			},
+		},
+		{
+			sql: "copy t from stdin; -- data\n1\t'",
+			want: []Token{
+				{Type: COPY, Value: "COPY"},
+				{Type: IDENT, Value: "t"},
+				{Type: FROM, Value: "FROM"},
+				{Type: IDENT, Value: "stdin"},
+				{Type: SEMICOLON, Value: ";"},
+				{Type: COMMENT, Value: "-- data"},
+				{Type: DATA, Value: "1\t'"},
+				{Type: EOF, Value: "EOF"},
+			},
+		},
+		{
+			sql: "copy t from 'file';\nselect 'a'",
+			want: []Token{
+				{Type: COPY, Value: "COPY"},
+				{Type: IDENT, Value: "t"},
+				{Type: FROM, Value: "FROM"},
+				{Type: STRING, Value: "'file'"},
+				{Type: SEMICOLON, Value: ";"},
+				{Type: SELECT, Value: "SELECT"},
+				{Type: STRING, Value: "'a'"},
+				{Type: EOF, Value: "EOF"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.sql, func(t *testing.T) {
+			got, err := Tokenize(tt.sql)
+			assert.Nil(t, err)
+			if assert.Len(t, got, len(tt.want)) {
+				for i := range tt.want {
+					assert.Equal(t, tt.want[i].Type, got[i].Type)
+					assert.Equal(t, tt.want[i].Value, got[i].Value)
+				}
+			}
+		})
+	}
+}
+
+func TestTokenize_StringLiterals(t *testing.T) {
+	tests := []struct {
+		sql  string
+		want []Token
+	}{
+		{
+			sql: `'it''s', '', ''''`,
+			want: []Token{
+				{Type: STRING, Value: `'it''s'`},
+				{Type: COMMA, Value: ","},
+				{Type: STRING, Value: `''`},
+				{Type: COMMA, Value: ","},
+				{Type: STRING, Value: `''''`},
+				{Type: EOF, Value: "EOF"},
+			},
+		},
+		{
+			sql: `E'it\'s\n', e'a\\', 'c:\'`,
+			want: []Token{
+				{Type: STRING, Value: `E'it\'s\n'`},
+				{Type: COMMA, Value: ","},
+				{Type: STRING, Value: `e'a\\'`},
+				{Type: COMMA, Value: ","},
+				{Type: STRING, Value: `'c:\'`},
+				{Type: EOF, Value: "EOF"},
+			},
+		},
+		{
+			sql: `N'text', X'ff', b'1010', U&'d\0061t', U&"d\0061t"`,
+			want: []Token{
+				{Type: STRING, Value: `N'text'`},
+				{Type: COMMA, Value: ","},
+				{Type: STRING, Value: `X'ff'`},
+				{Type: COMMA, Value: ","},
+				{Type: STRING, Value: `b'1010'`},
+				{Type: COMMA, Value: ","},
+				{Type: STRING, Value: `U&'d\0061t'`},
+				{Type: COMMA, Value: ","},
+				{Type: QUOTED_IDENT, Value: `U&"d\0061t"`},
+				{Type: EOF, Value: "EOF"},
+			},
+		},
+		{
+			sql: `end='x'`,
+			want: []Token{
+				{Type: END, Value: "END"},
+				{Type: COMPARATOR, Value: "="},
+				{Type: STRING, Value: `'x'`},
+				{Type: EOF, Value: "EOF"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.sql, func(t *testing.T) {
+			got, err := Tokenize(tt.sql)
+			assert.Nil(t, err)
+			if assert.Len(t, got, len(tt.want)) {
+				for i := range tt.want {
+					assert.Equal(t, tt.want[i].Type, got[i].Type)
+					assert.Equal(t, tt.want[i].Value, got[i].Value)
+				}
+			}
+		})
+	}
+
+	// Unterminated escape string, the last quote is escaped
+	_, err := Tokenize(`select E'abc\'`)
+	assert.Error(t, err)
+}
+
+func TestTokenize_Operators(t *testing.T) {
+	tests := []struct {
+		sql  string
+		want []Token
+	}{
+		{
+			sql: `a+b-1*c/d%e||f`,
+			want: []Token{
+				{Type: IDENT, Value: "a"},
+				{Type: OPERATOR, Value: "+"},
+				{Type: IDENT, Value: "b"},
+				{Type: OPERATOR, Value: "-"},
+				{Type: NUMBER, Value: "1"},
+				{Type: OPERATOR, Value: "*"},
+				{Type: IDENT, Value: "c"},
+				{Type: OPERATOR, Value: "/"},
+				{Type: IDENT, Value: "d"},
+				{Type: OPERATOR, Value: "%"},
+				{Type: IDENT, Value: "e"},
+				{Type: OPERATOR, Value: "||"},
+				{Type: IDENT, Value: "f"},
+				{Type: EOF, Value: "EOF"},
+			},
+		},
+		{
+			sql: `data->'a'->>'b', data#>>'{a}', tags@>x, x<@tags, k?|y, a&&b, g=>1`,
+			want: []Token{
+				{Type: IDENT, Value: "data"},
+				{Type: OPERATOR, Value: "->"},
+				{Type: STRING, Value: "'a'"},
+				{Type: OPERATOR, Value: "->>"},
+				{Type: STRING, Value: "'b'"},
+				{Type: COMMA, Value: ","},
+				{Type: IDENT, Value: "data"},
+				{Type: OPERATOR, Value: "#>>"},
+				{Type: STRING, Value: "'{a}'"},
+				{Type: COMMA, Value: ","},
+				{Type: IDENT, Value: "tags"},
+				{Type: OPERATOR, Value: "@>"},
+				{Type: IDENT, Value: "x"},
+				{Type: COMMA, Value: ","},
+				{Type: IDENT, Value: "x"},
+				{Type: OPERATOR, Value: "<@"},
+				{Type: IDENT, Value: "tags"},
+				{Type: COMMA, Value: ","},
+				{Type: IDENT, Value: "k"},
+				{Type: OPERATOR, Value: "?|"},
+				{Type: IDENT, Value: "y"},
+				{Type: COMMA, Value: ","},
+				{Type: IDENT, Value: "a"},
+				{Type: OPERATOR, Value: "&&"},
+				{Type: IDENT, Value: "b"},
+				{Type: COMMA, Value: ","},
+				{Type: IDENT, Value: "g"},
+				{Type: OPERATOR, Value: "=>"},
+				{Type: NUMBER, Value: "1"},
+				{Type: EOF, Value: "EOF"},
+			},
+		},
+		{
+			sql: `select *, t.*, count(*), -1, 1e-5 where a = -.5 -- comment`,
+			want: []Token{
+				{Type: SELECT, Value: "SELECT"},
+				{Type: IDENT, Value: "*"},
+				{Type: COMMA, Value: ","},
+				{Type: IDENT, Value: "t.*"},
+				{Type: COMMA, Value: ","},
+				{Type: FUNCTION, Value: "COUNT"},
+				{Type: STARTPARENTHESIS, Value: "("},
+				{Type: IDENT, Value: "*"},
+				{Type: ENDPARENTHESIS, Value: ")"},
+				{Type: COMMA, Value: ","},
+				{Type: NUMBER, Value: "-1"},
+				{Type: COMMA, Value: ","},
+				{Type: NUMBER, Value: "1e-5"},
+				{Type: WHERE, Value: "WHERE"},
+				{Type: IDENT, Value: "a"},
+				{Type: COMPARATOR, Value: "="},
+				{Type: NUMBER, Value: "-.5"},
+				{Type: COMMENT, Value: "-- comment"},
+				{Type: EOF, Value: "EOF"},
+			},
+		},
+		{
+			sql: `-y, a-b, a / -b, (+c), b = -b, c~*'z', c!~*'z', c~'z'`,
+			want: []Token{
+				{Type: IDENT, Value: "-y"},
+				{Type: COMMA, Value: ","},
+				{Type: IDENT, Value: "a"},
+				{Type: OPERATOR, Value: "-"},
+				{Type: IDENT, Value: "b"},
+				{Type: COMMA, Value: ","},
+				{Type: IDENT, Value: "a"},
+				{Type: OPERATOR, Value: "/"},
+				{Type: IDENT, Value: "-b"},
+				{Type: COMMA, Value: ","},
+				{Type: STARTPARENTHESIS, Value: "("},
+				{Type: IDENT, Value: "+c"},
+				{Type: ENDPARENTHESIS, Value: ")"},
+				{Type: COMMA, Value: ","},
+				{Type: IDENT, Value: "b"},
+				{Type: COMPARATOR, Value: "="},
+				{Type: IDENT, Value: "-b"},
+				{Type: COMMA, Value: ","},
+				{Type: IDENT, Value: "c"},
+				{Type: OPERATOR, Value: "~*"},
+				{Type: STRING, Value: "'z'"},
+				{Type: COMMA, Value: ","},
+				{Type: IDENT, Value: "c"},
+				{Type: OPERATOR, Value: "!~*"},
+				{Type: STRING, Value: "'z'"},
+				{Type: COMMA, Value: ","},
+				{Type: IDENT, Value: "c"},
+				{Type: OPERATOR, Value: "~"},
+				{Type: STRING, Value: "'z'"},
+				{Type: EOF, Value: "EOF"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.sql, func(t *testing.T) {
+			got, err := Tokenize(tt.sql)
+			assert.Nil(t, err)
+			if assert.Len(t, got, len(tt.want)) {
+				for i := range tt.want {
+					assert.Equal(t, tt.want[i].Type, got[i].Type)
+					assert.Equal(t, tt.want[i].Value, got[i].Value)
+				}
+			}
+		})
+	}
+}
+
+func TestTokenize_Numbers(t *testing.T) {
+	tests := []struct {
+		sql  string
+		want []Token
+	}{
+		{
+			sql: "select 42, 1.5, .5, 1., 1e5, 1.5E-3, 2e+10, 1_000_000, 0x1F, 0X_ff, 0o17, 0b1010",
+			want: []Token{
+				{Type: SELECT, Value: "SELECT"},
+				{Type: NUMBER, Value: "42"},
+				{Type: COMMA, Value: ","},
+				{Type: NUMBER, Value: "1.5"},
+				{Type: COMMA, Value: ","},
+				{Type: NUMBER, Value: ".5"},
+				{Type: COMMA, Value: ","},
+				{Type: NUMBER, Value: "1."},
+				{Type: COMMA, Value: ","},
+				{Type: NUMBER, Value: "1e5"},
+				{Type: COMMA, Value: ","},
+				{Type: NUMBER, Value: "1.5E-3"},
+				{Type: COMMA, Value: ","},
+				{Type: NUMBER, Value: "2e+10"},
+				{Type: COMMA, Value: ","},
+				{Type: NUMBER, Value: "1_000_000"},
+				{Type: COMMA, Value: ","},
+				{Type: NUMBER, Value: "0x1F"},
+				{Type: COMMA, Value: ","},
+				{Type: NUMBER, Value: "0X_ff"},
+				{Type: COMMA, Value: ","},
+				{Type: NUMBER, Value: "0o17"},
+				{Type: COMMA, Value: ","},
+				{Type: NUMBER, Value: "0b1010"},
+				{Type: EOF, Value: "EOF"},
+			},
+		},
+		{
+			sql: "a-1, 1-2, x=-1.5e-3, +.5, 1st_column, 1e, 0x, t1.5col",
+			want: []Token{
+				{Type: IDENT, Value: "a"},
+				{Type: OPERATOR, Value: "-"},
+				{Type: NUMBER, Value: "1"},
+				{Type: COMMA, Value: ","},
+				{Type: NUMBER, Value: "1"},
+				{Type: OPERATOR, Value: "-"},
+				{Type: NUMBER, Value: "2"},
+				{Type: COMMA, Value: ","},
+				{Type: IDENT, Value: "x"},
+				{Type: COMPARATOR, Value: "="},
+				{Type: NUMBER, Value: "-1.5e-3"},
+				{Type: COMMA, Value: ","},
+				{Type: NUMBER, Value: "+.5"},
+				{Type: COMMA, Value: ","},
+				{Type: IDENT, Value: "1st_column"},
+				{Type: COMMA, Value: ","},
+				{Type: IDENT, Value: "1e"},
+				{Type: COMMA, Value: ","},
+				{Type: IDENT, Value: "0x"},
+				{Type: COMMA, Value: ","},
+				{Type: IDENT, Value: "t1.5col"},
+				{Type: EOF, Value: "EOF"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.sql, func(t *testing.T) {
+			got, err := Tokenize(tt.sql)
+			assert.Nil(t, err)
+			if assert.Len(t, got, len(tt.want)) {
+				for i := range tt.want {
+					assert.Equal(t, tt.want[i].Type, got[i].Type)
+					assert.Equal(t, tt.want[i].Value, got[i].Value)
+				}
+			}
+		})
+	}
+}
+
+func TestTokenize_Placeholders(t *testing.T) {
+	tests := []struct {
+		sql  string
+		want []Token
+	}{
+		{
+			sql: "where a = $1 and b=$12::int",
+			want: []Token{
+				{Type: WHERE, Value: "WHERE"},
+				{Type: IDENT, Value: "a"},
+				{Type: COMPARATOR, Value: "="},
+				{Type: PLACEHOLDER, Value: "$1"},
+				{Type: AND, Value: "AND"},
+				{Type: IDENT, Value: "b"},
+				{Type: COMPARATOR, Value: "="},
+				{Type: PLACEHOLDER, Value: "$12"},
+				{Type: DOUBLECOLON, Value: "::"},
+				{Type: TYPE, Value: "INT"},
+				{Type: EOF, Value: "EOF"},
+			},
+		},
+		{
+			sql: "values (?, ?2, :name, :user.id, :1, @p1, @name)",
+			want: []Token{
+				{Type: VALUES, Value: "VALUES"},
+				{Type: STARTPARENTHESIS, Value: "("},
+				{Type: PLACEHOLDER, Value: "?"},
+				{Type: COMMA, Value: ","},
+				{Type: PLACEHOLDER, Value: "?2"},
+				{Type: COMMA, Value: ","},
+				{Type: PLACEHOLDER, Value: ":name"},
+				{Type: COMMA, Value: ","},
+				{Type: PLACEHOLDER, Value: ":user.id"},
+				{Type: COMMA, Value: ","},
+				{Type: PLACEHOLDER, Value: ":1"},
+				{Type: COMMA, Value: ","},
+				{Type: PLACEHOLDER, Value: "@p1"},
+				{Type: COMMA, Value: ","},
+				{Type: PLACEHOLDER, Value: "@name"},
+				{Type: ENDPARENTHESIS, Value: ")"},
+				{Type: EOF, Value: "EOF"},
+			},
+		},
+		{
+			sql: "where data ? 'key' and arr[1:2] = x and b::text = c",
+			want: []Token{
+				{Type: WHERE, Value: "WHERE"},
+				{Type: IDENT, Value: "data"},
+				{Type: OPERATOR, Value: "?"},
+				{Type: STRING, Value: "'key'"},
+				{Type: AND, Value: "AND"},
+				{Type: IDENT, Value: "arr"},
+				{Type: STARTBRACKET, Value: "["},
+				{Type: NUMBER, Value: "1"},
+				{Type: COLON, Value: ":"},
+				{Type: NUMBER, Value: "2"},
+				{Type: ENDBRACKET, Value: "]"},
+				{Type: COMPARATOR, Value: "="},
+				{Type: IDENT, Value: "x"},
+				{Type: AND, Value: "AND"},
+				{Type: IDENT, Value: "b"},
+				{Type: DOUBLECOLON, Value: "::"},
+				{Type: TYPE, Value: "TEXT"},
+				{Type: COMPARATOR, Value: "="},
+				{Type: IDENT, Value: "c"},
+				{Type: EOF, Value: "EOF"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.sql, func(t *testing.T) {
+			got, err := Tokenize(tt.sql)
+			assert.Nil(t, err)
+			if assert.Len(t, got, len(tt.want)) {
+				for i := range tt.want {
+					assert.Equal(t, tt.want[i].Type, got[i].Type)
+					assert.Equal(t, tt.want[i].Value, got[i].Value)
+				}
+			}
+		})
+	}
+}
+
+func TestTokenizeWithConfig_Comments(t *testing.T) {
+	tests := []struct {
+		sql     string
+		dialect Dialect
+		want    []Token
+	}{
+		{
+			sql:     "select /* outer /* inner */ still comment */ a",
+			dialect: PostgreSQL,
+			want: []Token{
+				{Type: SELECT, Value: "SELECT"},
+				{Type: COMMENT, Value: "/* outer /* inner */ still comment */"},
+				{Type: IDENT, Value: "a"},
+				{Type: EOF, Value: "EOF"},
+			},
+		},
+		{
+			sql:     "select /* outer /* inner */ a",
+			dialect: MySQL,
+			want: []Token{
+				{Type: SELECT, Value: "SELECT"},
+				{Type: COMMENT, Value: "/* outer /* inner */"},
+				{Type: IDENT, Value: "a"},
+				{Type: EOF, Value: "EOF"},
+			},
+		},
+		{
+			sql:     "select /*/ comment */ a",
+			dialect: PostgreSQL,
+			want: []Token{
+				{Type: SELECT, Value: "SELECT"},
+				{Type: COMMENT, Value: "/*/ comment */"},
+				{Type: IDENT, Value: "a"},
+				{Type: EOF, Value: "EOF"},
+			},
+		},
+		{
+			sql:     "select /*+ INDEX(t idx) */ a",
+			dialect: Oracle,
+			want: []Token{
+				{Type: SELECT, Value: "SELECT"},
+				{Type: HINT, Value: "/*+ INDEX(t idx) */"},
+				{Type: IDENT, Value: "a"},
+				{Type: EOF, Value: "EOF"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.dialect.String()+": "+tt.sql, func(t *testing.T) {
+			got, err := TokenizeWithConfig(tt.sql, Config{Dialect: tt.dialect})
+			assert.Nil(t, err)
+			if assert.Len(t, got, len(tt.want)) {
+				for i := range tt.want {
+					assert.Equal(t, tt.want[i].Type, got[i].Type)
+					assert.Equal(t, tt.want[i].Value, got[i].Value)
+				}
+			}
+		})
+	}
+}
+
+func Test_peekOperator(t *testing.T) {
+	tests := []struct {
+		testSequence string
+		wantOperator string
+	}{
+		{testSequence: "+1", wantOperator: "+"},
+		{testSequence: "->>'a'", wantOperator: "->>"},
+		{testSequence: "->'a'", wantOperator: "->"},
+		{testSequence: "<@ b", wantOperator: "<@"},
+		{testSequence: "<<=x", wantOperator: "<<="},
+		{testSequence: "||b", wantOperator: "||"},
+		{testSequence: "-- comment", wantOperator: ""},
+		{testSequence: "/* comment */", wantOperator: ""},
+		{testSequence: "*==", wantOperator: ""}, // part of invalid comparator sequence
+		{testSequence: "=1", wantOperator: ""},  // comparator, not an operator
+		{testSequence: "a+b", wantOperator: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.testSequence, func(t *testing.T) {
+			r := bufio.NewReader(strings.NewReader(tt.testSequence))
+			assert.Equal(t, tt.wantOperator, peekOperator(r, tablesOf(Generic).operators))
+
+			// Check if original string is untouched
+			remaining, _ := r.ReadString('\n')
+			assert.Equal(t, tt.testSequence, remaining)
+		})
+	}
+}
+
 func Test_peekComparator(t *testing.T) {
 
 	tests := []struct {
@@ -163,3 +901,23 @@ func Test_peekComparator(t *testing.T) {
 		})
 	}
 }
+
+func TestTokenizeWithConfig_DisableFunctionKeywords(t *testing.T) {
+	sql := "select current_date from user"
+
+	// Function keywords enabled by default
+	got, err := TokenizeWithConfig(sql, Config{})
+	assert.Nil(t, err)
+	if assert.Len(t, got, 5) {
+		assert.Equal(t, Token{Type: FUNCTIONKEYWORD, Value: "CURRENT_DATE"}, Token{Type: got[1].Type, Value: got[1].Value})
+		assert.Equal(t, Token{Type: FUNCTIONKEYWORD, Value: "USER"}, Token{Type: got[3].Type, Value: got[3].Value})
+	}
+
+	// Function keywords disabled
+	got, err = TokenizeWithConfig(sql, Config{DisableFunctionKeywords: true})
+	assert.Nil(t, err)
+	if assert.Len(t, got, 5) {
+		assert.Equal(t, Token{Type: IDENT, Value: "current_date"}, Token{Type: got[1].Type, Value: got[1].Value})
+		assert.Equal(t, Token{Type: IDENT, Value: "user"}, Token{Type: got[3].Type, Value: got[3].Value})
+	}
+}
@@ -0,0 +1,188 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/noneymous/go-sqlfmt/sqlfmt/lexer"
+)
+
+// Error describes a failure to parse the tokens of an SQL query at a certain location. It can be retrieved from
+// wrapped errors via errors.As.
+type Error struct {
+	lexer.Position             // Location of the offending token
+	Text           string      // Original text of the offending token, empty at the end of the statement
+	Segment        lexer.Token // Token introducing the segment being parsed, e.g. "CASE", if any
+	Message        string      // Description of the failure, e.g. "unexpected end of statement"
+	Expected       string      // Description of what was expected instead, e.g. "END to close CASE started at ..."
+	Suggestion     string      // Keyword the offending token might be a misspelling of, e.g. "SELECT" for "SELCT"
+}
+
+// Error returns a human-readable description of the failure
+func (e *Error) Error() string {
+	msg := fmt.Sprintf("syntax error at %s: %s", e.Position, e.Message)
+	if e.Expected != "" {
+		msg += fmt.Sprintf(", expected %s", e.Expected)
+	}
+	if e.Suggestion != "" {
+		msg += fmt.Sprintf(", did you mean '%s'?", e.Suggestion)
+	}
+	return msg
+}
+
+// newStartError creates an error describing a token, which can not introduce a segment
+func newStartError(token lexer.Token) *Error {
+
+	// Describe unbalanced parentheses
+	if token.Type == lexer.ENDPARENTHESIS {
+		return &Error{
+			Position: token.Start,
+			Text:     token.Raw,
+			Message:  "unexpected ')' without matching '('",
+		}
+	}
+
+	// Describe invalid start token, suggesting the keyword that might have been meant
+	return &Error{
+		Position:   token.Start,
+		Text:       token.Raw,
+		Message:    fmt.Sprintf("invalid start token '%s'", token.Value),
+		Expected:   "keyword starting a statement or clause",
+		Suggestion: suggestKeyword(token, suggestKeywords),
+	}
+}
+
+// newEndError creates an error describing a segment, which is missing its closing token, e.g. "END" closing "CASE"
+func newEndError(segment lexer.Token, token lexer.Token) *Error {
+
+	// Describe the closing token expected
+	var expected string
+	switch segment.Type {
+	case lexer.CASE:
+		expected = fmt.Sprintf("END to close %s", segment.Value)
+	case lexer.FUNCTION:
+		expected = fmt.Sprintf("')' to close arguments of %s", segment.Value)
+	case lexer.TYPE:
+		expected = fmt.Sprintf("')' to close parameters of %s", segment.Value)
+	case lexer.OVER:
+		expected = fmt.Sprintf("')' to close window of %s", segment.Value)
+	default:
+		expected = fmt.Sprintf("')' to close '%s'", segment.Value)
+	}
+
+	// Return error
+	return &Error{
+		Position: token.Start,
+		Text:     token.Raw,
+		Segment:  segment,
+		Message:  "unexpected end of statement",
+		Expected: fmt.Sprintf("%s started at %s", expected, segment.Start),
+	}
+}
+
+// newClauseError creates an error describing the first name within the tokens, which is likely a misspelled
+// keyword introducing a clause, e.g. "FORM" of SELECT a FORM t. Such a name follows an operand and is followed by
+// another name, so that it can neither be an operand nor an alias itself. Returns nil if there is none.
+func newClauseError(tokens []lexer.Token) *Error {
+	for i := 1; i < len(tokens)-1; i++ {
+
+		// Skip names, which are not enclosed by an operand and a subsequent name or BY, e.g. GRUOP BY
+		switch tokens[i-1].Type {
+		case lexer.IDENT, lexer.QUOTED_IDENT, lexer.NUMBER, lexer.STRING, lexer.ENDPARENTHESIS:
+		default:
+			continue
+		}
+		switch tokens[i+1].Type {
+		case lexer.IDENT, lexer.QUOTED_IDENT, lexer.BY:
+		default:
+			continue
+		}
+
+		// Describe name, if it is similar enough to a keyword introducing a clause
+		if suggestion := suggestKeyword(tokens[i], suggestClauseKeywords); suggestion != "" {
+			return &Error{
+				Position:   tokens[i].Start,
+				Text:       tokens[i].Raw,
+				Message:    fmt.Sprintf("unexpected name '%s'", tokens[i].Value),
+				Expected:   "keyword starting a clause",
+				Suggestion: suggestion,
+			}
+		}
+	}
+	return nil
+}
+
+// suggestKeywords lists the keywords suggested for misspelled tokens. These are the keywords introducing
+// statements and clauses, which are likely to be misspelled when an invalid start token is encountered, e.g.
+// "form t".
+var suggestKeywords = []string{
+	"SELECT", "FROM", "WHERE", "JOIN", "INNER", "OUTER", "LEFT", "RIGHT", "NATURAL", "CROSS", "GROUP", "HAVING",
+	"ORDER", "LIMIT", "OFFSET", "FETCH", "UNION", "INTERSECT", "EXCEPT", "UPDATE", "SET", "RETURNING", "CREATE",
+	"ALTER", "DELETE", "DROP", "INSERT", "VALUES", "WITH", "LOCK", "SHOW", "DISCARD", "BEGIN", "SAVEPOINT",
+	"RELEASE", "ROLLBACK", "COMMIT", "ANALYZE", "VACUUM", "RESET", "COPY", "EXPLAIN", "MERGE", "CASE",
+}
+
+// suggestClauseKeywords lists the keywords suggested for misspelled names within a statement. These are the
+// keywords introducing clauses, which are likely to be misspelled between operands, e.g. SELECT a FORM t.
+var suggestClauseKeywords = []string{
+	"FROM", "WHERE", "JOIN", "INNER", "OUTER", "LEFT", "RIGHT", "NATURAL", "CROSS", "GROUP", "HAVING", "ORDER",
+	"LIMIT", "OFFSET", "FETCH", "UNION", "INTERSECT", "EXCEPT", "SET", "RETURNING", "VALUES",
+}
+
+// suggestKeyword returns the keyword of the given ones the token might be a misspelling of, or an empty string if
+// there is none similar enough. Short words allow a single edit, longer ones two, e.g. "SELCT" for "SELECT".
+func suggestKeyword(token lexer.Token, keywords []string) string {
+
+	// Only names can be misspelled keywords
+	if token.Type != lexer.IDENT {
+		return ""
+	}
+	word := strings.ToUpper(token.Value)
+	maxDistance := 1
+	if len(word) >= 6 {
+		maxDistance = 2
+	}
+
+	// Find most similar keyword
+	var suggestion string
+	for _, keyword := range keywords {
+		distance := editDistance(word, keyword)
+		if distance > 0 && distance <= maxDistance {
+			suggestion = keyword
+			maxDistance = distance - 1
+		}
+	}
+	return suggestion
+}
+
+// editDistance returns the number of insertions, deletions, substitutions and transpositions of adjacent
+// characters required to turn a into b
+func editDistance(a string, b string) int {
+
+	// Prepare distance matrix, the first row and column represent the distance to an empty string
+	d := make([][]int, len(a)+1)
+	for i := range d {
+		d[i] = make([]int, len(b)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+
+	// Calculate distances between all prefixes
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			d[i][j] = min(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				d[i][j] = min(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+
+	// Return distance of the complete strings
+	return d[len(a)][len(b)]
+}
@@ -0,0 +1,138 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/noneymous/go-sqlfmt/sqlfmt/lexer"
+)
+
+func TestError(t *testing.T) {
+	tests := []struct {
+		sql        string
+		want       lexer.Position
+		suggestion string
+		message    string
+	}{
+		{
+			sql:        "SELCT a FROM t",
+			want:       lexer.Position{Offset: 0, Line: 1, Column: 1},
+			suggestion: "SELECT",
+			message:    "syntax error at line 1, column 1: invalid start token 'SELCT', expected keyword starting a statement or clause, did you mean 'SELECT'?",
+		},
+		{
+			sql:        "form t",
+			want:       lexer.Position{Offset: 0, Line: 1, Column: 1},
+			suggestion: "FROM",
+			message:    "syntax error at line 1, column 1: invalid start token 'form', expected keyword starting a statement or clause, did you mean 'FROM'?",
+		},
+		{
+			sql:        "SELECT a FORM t",
+			want:       lexer.Position{Offset: 9, Line: 1, Column: 10},
+			suggestion: "FROM",
+			message:    "syntax error at line 1, column 10: unexpected name 'FORM', expected keyword starting a clause, did you mean 'FROM'?",
+		},
+		{
+			sql:        "select a from t where b = 1 gruop by a",
+			want:       lexer.Position{Offset: 28, Line: 1, Column: 29},
+			suggestion: "GROUP",
+			message:    "syntax error at line 1, column 29: unexpected name 'gruop', expected keyword starting a clause, did you mean 'GROUP'?",
+		},
+		{
+			sql:     "foo bar",
+			want:    lexer.Position{Offset: 0, Line: 1, Column: 1},
+			message: "syntax error at line 1, column 1: invalid start token 'foo', expected keyword starting a statement or clause",
+		},
+		{
+			sql:     "select a,\n  case when b then c\nfrom t",
+			want:    lexer.Position{Offset: 37, Line: 3, Column: 7},
+			message: "syntax error at line 3, column 7: unexpected end of statement, expected END to close CASE started at line 2, column 3",
+		},
+		{
+			sql:     "select count(a from t",
+			want:    lexer.Position{Offset: 21, Line: 1, Column: 22},
+			message: "syntax error at line 1, column 22: unexpected end of statement, expected ')' to close arguments of COUNT started at line 1, column 8",
+		},
+		{
+			sql:     "select rank() over (order by a from t",
+			want:    lexer.Position{Offset: 37, Line: 1, Column: 38},
+			message: "syntax error at line 1, column 38: unexpected end of statement, expected ')' to close window of OVER started at line 1, column 15",
+		},
+		{
+			sql:     "select (a from t",
+			want:    lexer.Position{Offset: 16, Line: 1, Column: 17},
+			message: "syntax error at line 1, column 17: unexpected end of statement, expected ')' to close '(' started at line 1, column 8",
+		},
+		{
+			sql:     "select a) from t",
+			want:    lexer.Position{Offset: 8, Line: 1, Column: 9},
+			message: "syntax error at line 1, column 9: unexpected ')' without matching '('",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.sql, func(t *testing.T) {
+			tokens, errTokenize := lexer.Tokenize(tt.sql)
+			if errTokenize != nil {
+				t.Fatalf("%v", errTokenize)
+			}
+			_, err := Parse(tokens, nil)
+
+			// Verify that the structured error can be retrieved from wrapped errors
+			var errParser *Error
+			if !errors.As(fmt.Errorf("wrapped: %w", err), &errParser) {
+				t.Fatalf("want parser error, got %v", err)
+			}
+			if errParser.Position != tt.want {
+				t.Errorf("want position %v, got %v", tt.want, errParser.Position)
+			}
+			if errParser.Suggestion != tt.suggestion {
+				t.Errorf("want suggestion %q, got %q", tt.suggestion, errParser.Suggestion)
+			}
+			if errParser.Error() != tt.message {
+				t.Errorf("want message %q, got %q", tt.message, errParser.Error())
+			}
+		})
+	}
+}
+
+func TestError_WithinStatement(t *testing.T) {
+
+	// Names similar to keywords are valid, unless they are followed by another name, e.g. the aliases "FORM"
+	for _, sql := range []string{"SELECT a FORM FROM t", "SELECT a FROM t form JOIN u ON true", "UPDATE t SET sets = 1"} {
+		t.Run(sql, func(t *testing.T) {
+			tokens, errTokenize := lexer.Tokenize(sql)
+			if errTokenize != nil {
+				t.Fatalf("%v", errTokenize)
+			}
+			if _, err := Parse(tokens, nil); err != nil {
+				t.Errorf("want no error, got %v", err)
+			}
+		})
+	}
+}
+
+func Test_editDistance(t *testing.T) {
+	tests := []struct {
+		a    string
+		b    string
+		want int
+	}{
+		{a: "SELECT", b: "SELECT", want: 0},
+		{a: "SELCT", b: "SELECT", want: 1},
+		{a: "FORM", b: "FROM", want: 1},
+		{a: "WEHRE", b: "WHERE", want: 1},
+		{a: "UDPATE", b: "UPDATE", want: 1},
+		{a: "ORDR", b: "ORDER", want: 1},
+		{a: "GROPU", b: "GROUP", want: 1},
+		{a: "", b: "SET", want: 3},
+		{a: "FOO", b: "FROM", want: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.a, func(t *testing.T) {
+			if got := editDistance(tt.a, tt.b); got != tt.want {
+				t.Errorf("want %d, got %d", tt.want, got)
+			}
+		})
+	}
+}
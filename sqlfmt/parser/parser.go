@@ -2,6 +2,9 @@ package parser
 
 import (
 	"fmt"
+	"strings"
+
+	"github.com/noneymous/go-sqlfmt/sqlfmt/ast"
 	"github.com/noneymous/go-sqlfmt/sqlfmt/formatters"
 	"github.com/noneymous/go-sqlfmt/sqlfmt/lexer"
 )
@@ -12,10 +15,20 @@ const joinStartRange = 3
 // Each Formatter is a logical segment of an SQL query. It may also be a group of such.
 func Parse(tokens []lexer.Token, options *formatters.Options) ([]formatters.Formatter, error) {
 
+	// Reject names, which are likely misspelled keywords, e.g. SELECT a FORM t
+	if errClause := newClauseError(tokens); errClause != nil {
+		return nil, errClause
+	}
+
 	// Prepare parser for segment
 	parser, errParser := NewParser(tokens, options)
 	if errParser != nil {
-		return nil, errParser
+		if options == nil || !options.Recover {
+			return nil, errParser
+		}
+
+		// Continue with a plain parser in recovering mode, the invalid start token is skipped while parsing
+		parser = &Parser{options: options, tokens: tokens}
 	}
 
 	// Parse tokens
@@ -28,6 +41,20 @@ func Parse(tokens []lexer.Token, options *formatters.Options) ([]formatters.Form
 	return result, nil
 }
 
+// ParseSegments parses a sequence of tokens returning a logically grouped slice of segments, which the
+// Formatters returned by Parse are built from.
+func ParseSegments(tokens []lexer.Token) ([]*ast.Segment, error) {
+
+	// Prepare parser for segment
+	parser, errParser := NewParser(tokens, nil)
+	if errParser != nil {
+		return nil, errParser
+	}
+
+	// Parse tokens
+	return parser.parseSegments()
+}
+
 // Parser initiates with a sequence of lexer tokens to be processed by the Parse() function.
 // Furthermore, it holds all necessary state variables and a set of options to be assigned to each Formatter.
 type Parser struct {
@@ -36,7 +63,7 @@ type Parser struct {
 	tokens   []lexer.Token
 	endTypes []lexer.TokenType
 
-	result []formatters.Formatter
+	result []ast.Node
 }
 
 // NewParser initializes a Parser with a sequence of lexer tokens representing an SQL query.
@@ -85,9 +112,29 @@ func NewParser(tokens []lexer.Token, options *formatters.Options) (*Parser, erro
 	case lexer.RETURNING:
 		return &Parser{options: options, tokens: tokens, endTypes: lexer.EndOfReturning}, nil
 	case lexer.CREATE:
+		switch createdObject(tokens) {
+		case lexer.INDEX:
+			return &Parser{options: options, tokens: tokens, endTypes: lexer.EndOfCreateIndex}, nil
+		case lexer.ROUTINE:
+			return &Parser{options: options, tokens: tokens, endTypes: lexer.EndOfCreateRoutine}, nil
+		case lexer.TRIGGER:
+			return &Parser{options: options, tokens: tokens, endTypes: lexer.EndOfCreateTrigger}, nil
+		case lexer.ROLE:
+			return &Parser{options: options, tokens: tokens, endTypes: lexer.EndOfCreateRole}, nil
+		case lexer.POLICY:
+			return &Parser{options: options, tokens: tokens, endTypes: lexer.EndOfCreatePolicy}, nil
+		}
 		return &Parser{options: options, tokens: tokens, endTypes: lexer.EndOfCreate}, nil
 	case lexer.ALTER:
+		switch {
+		case isAlterDefaultPrivileges(tokens):
+			return &Parser{options: options, tokens: tokens, endTypes: lexer.EndOfGrant}, nil
+		case tokens[1].Type == lexer.POLICY:
+			return &Parser{options: options, tokens: tokens, endTypes: lexer.EndOfCreatePolicy}, nil
+		}
 		return &Parser{options: options, tokens: tokens, endTypes: lexer.EndOfAlter}, nil
+	case lexer.GRANT, lexer.REVOKE:
+		return &Parser{options: options, tokens: tokens, endTypes: lexer.EndOfGrant}, nil
 	case lexer.DELETE:
 		return &Parser{options: options, tokens: tokens, endTypes: lexer.EndOfDelete}, nil
 	case lexer.DROP:
@@ -128,26 +175,68 @@ func NewParser(tokens []lexer.Token, options *formatters.Options) (*Parser, erro
 		return &Parser{options: options, tokens: tokens, endTypes: lexer.EndOfCopy}, nil
 	case lexer.EXPLAIN:
 		return &Parser{options: options, tokens: tokens, endTypes: lexer.EndOfExplain}, nil
+	case lexer.DO:
+		return &Parser{options: options, tokens: tokens, endTypes: lexer.EndOfDo}, nil
+	case lexer.MERGE:
+		return &Parser{options: options, tokens: tokens, endTypes: lexer.EndOfMerge}, nil
+	case lexer.OVER:
+		return &Parser{options: options, tokens: tokens, endTypes: lexer.EndOfOver}, nil
+	case lexer.WINDOW:
+		return &Parser{options: options, tokens: tokens, endTypes: lexer.EndOfWindow}, nil
+	case lexer.ON:
+		if isConflictStart(tokens, 0) {
+			return &Parser{options: options, tokens: tokens, endTypes: lexer.EndOfConflict}, nil
+		}
+		return nil, newStartError(tokens[0])
 	default:
-		return nil, fmt.Errorf("invalid start token '%s'", tokens[0].Value)
+		return nil, newStartError(tokens[0])
 	}
 }
 
 // Parse wraps parseSegment() and loops until EOF is reached. The initial sequence of tokens is usually
 // comprised out of multiple segments (SELECT, FROM, WHERE,...). Parse() loops until EOF to make sure all
-// segments are processed.
+// segments are processed. A Formatter is built for each segment.
 func (r *Parser) Parse() ([]formatters.Formatter, error) {
 
+	// Parse segments
+	segments, errSegments := r.parseSegments()
+	if errSegments != nil {
+		return nil, errSegments
+	}
+
+	// Build Formatters from segments
+	var result []formatters.Formatter
+	for _, segment := range segments {
+		segmentFormatter, errFormatter := r.buildFormatter(segment)
+		if errFormatter != nil {
+			if !r.options.Recover {
+				return nil, errFormatter
+			}
+
+			// Write segment verbatim in recovering mode, if no Formatter could be built
+			segmentFormatter, _ = r.buildFormatter(r.buildRegion(segment.Tokens(), errFormatter))
+		}
+		result = append(result, segmentFormatter)
+	}
+
+	// Return process result
+	return result, nil
+}
+
+// parseSegments loops until EOF is reached and returns the sequence of segments processed. Sequential segments
+// are processed by this loop. Nested segments are recursively processed by parseSegment().
+func (r *Parser) parseSegments() ([]*ast.Segment, error) {
+
 	// Prepare process variable
 	var offset int
+	var segments []*ast.Segment
 
 	// Verify that there is an EOF token at the end
 	if r.tokens[len(r.tokens)-1].Type != lexer.EOF {
 		return nil, fmt.Errorf("missing EOF token")
 	}
 
-	// Iterate and process segments until EOF is reached. Sequential segments are processed by this loop.
-	// Nested segments are recursively processed by parseSegment().
+	// Iterate and process segments until EOF is reached
 	for {
 
 		// Stop processing at EOF
@@ -155,27 +244,36 @@ func (r *Parser) Parse() ([]formatters.Formatter, error) {
 			break
 		}
 
-		// Prepare parser for segment
-		segmentParser, errSegmentParser := NewParser(r.tokens[offset:], r.options)
-		if errSegmentParser != nil {
-			return nil, errSegmentParser
+		// Prepare parser for segment and parse segment
+		segmentParser, errSegment := NewParser(r.tokens[offset:], r.options)
+		var idxEndSegment int
+		if errSegment == nil {
+			idxEndSegment, errSegment = segmentParser.parseSegment()
 		}
 
-		// Parse segment
-		idxEndSegment, errSegment := segmentParser.parseSegment()
+		// Keep region verbatim in recovering mode, if segment could not be parsed, and continue after it
 		if errSegment != nil {
-			return nil, errSegment
-		}
+			if !r.options.Recover {
+				return nil, errSegment
+			}
 
-		// Retrieve segment result
-		segmentFormatter := segmentParser.buildFormatter()
+			// A statement with an invalid start token is kept entirely, because subsequent keywords might belong
+			// to the unsupported statement, e.g. "SELECT" within "PREPARE name AS SELECT 1".
+			idxEndRegion := len(r.tokens) - 1
+			if offset > 0 || segmentParser != nil {
+				idxEndRegion = r.skipRegion(offset)
+			}
+			segments = append(segments, r.buildRegion(r.tokens[offset:idxEndRegion], errSegment))
+			offset = idxEndRegion
+			continue
+		}
 
 		// Append segment result to total result
-		r.result = append(r.result, segmentFormatter)
+		segments = append(segments, segmentParser.buildSegment(idxEndSegment))
 
 		// Increment offset counter to proceed with next segment, if available
 		switch r.tokens[offset].Type {
-		case lexer.STARTPARENTHESIS, lexer.CASE, lexer.FUNCTION, lexer.TYPE:
+		case lexer.STARTPARENTHESIS, lexer.CASE, lexer.FUNCTION, lexer.TYPE, lexer.OVER:
 			offset += idxEndSegment + 1 // Some types have end tags, e.g. "END" closing "CASE" or ")" closing "(". Next token starts after them.
 		default:
 			offset += idxEndSegment
@@ -183,7 +281,55 @@ func (r *Parser) Parse() ([]formatters.Formatter, error) {
 	}
 
 	// Return process result
-	return r.result, nil
+	return segments, nil
+}
+
+// skipRegion returns the index of the token, where parsing can be resumed after the segment starting at offset
+// failed to parse. Parsing is resumed at the next clause keyword outside of parentheses.
+func (r *Parser) skipRegion(offset int) int {
+
+	// Prepare process variable
+	var depth int
+
+	// Iterate tokens until a clause keyword is found
+	idx := offset + 1
+	for ; r.tokens[idx].Type != lexer.EOF; idx++ {
+		tokenCurrent := r.tokens[idx]
+		switch {
+		case tokenCurrent.Type == lexer.STARTPARENTHESIS:
+			depth++
+		case tokenCurrent.Type == lexer.ENDPARENTHESIS && depth > 0:
+			depth--
+		case depth == 0 && r.isResumeToken(idx):
+			return idx
+		}
+	}
+
+	// Return index of EOF token
+	return idx
+}
+
+// isResumeToken determines if the token at index idx is a clause keyword, where parsing can be resumed after
+// a region that could not be parsed
+func (r *Parser) isResumeToken(idx int) bool {
+	switch r.tokens[idx].Type {
+	case lexer.SELECT, lexer.FROM, lexer.WHERE, lexer.HAVING, lexer.ORDER, lexer.LIMIT, lexer.OFFSET, lexer.FETCH,
+		lexer.UNION, lexer.INTERSECT, lexer.EXCEPT, lexer.RETURNING, lexer.WINDOW:
+		return true
+	case lexer.GROUP:
+		return r.tokens[idx+1].Type == lexer.BY // There will always be an EOF token at the end
+	}
+	return false
+}
+
+// buildRegion creates a segment holding the tokens as they are, because they could not be parsed for the given
+// reason
+func (r *Parser) buildRegion(tokens []lexer.Token, err error) *ast.Segment {
+	var elements []ast.Node
+	for _, token := range tokens {
+		elements = append(elements, &ast.Token{Token: token})
+	}
+	return &ast.Segment{Children: elements, Err: err}
 }
 
 // parseSegment iterates a token segment, creates according Formatters and appends them to the final result.
@@ -205,7 +351,12 @@ func (r *Parser) parseSegment() (int, error) {
 
 		// Abort if no end token could be found and to prevent out-of-bound panics. Query might not be valid SQL.
 		if idx >= len(r.tokens) {
-			return 0, fmt.Errorf("could not find end token for '%s' token sequence", r.tokens[0].Value)
+			return 0, &Error{
+				Position: r.tokens[0].Start,
+				Text:     r.tokens[0].Raw,
+				Segment:  r.tokens[0],
+				Message:  fmt.Sprintf("could not find end of '%s' segment", r.tokens[0].Value),
+			}
 		}
 
 		// Get reference of token to analyze
@@ -214,8 +365,15 @@ func (r *Parser) parseSegment() (int, error) {
 		// Check for end token or new segment if current token is not first token
 		if idx > 0 {
 
-			// Check if token is end of segment
+			// Check if token is end of segment. Segments with an end token, e.g. ")" closing "(", must not be
+			// terminated by the end of the statement.
 			if r.isEndToken(idx) {
+				switch r.tokens[0].Type {
+				case lexer.STARTPARENTHESIS, lexer.CASE, lexer.FUNCTION, lexer.TYPE, lexer.OVER:
+					if tokenCurrent.Type == lexer.EOF {
+						return 0, newEndError(r.tokens[0], tokenCurrent)
+					}
+				}
 				return idx, nil
 			}
 
@@ -226,12 +384,17 @@ func (r *Parser) parseSegment() (int, error) {
 				// Create new parser for subsegment
 				segmentParser, errSegmentParser := NewParser(r.tokens[idx:], r.options)
 				if errSegmentParser != nil {
-					return 0, fmt.Errorf("invalid segment: %s", errSegmentParser)
+					return 0, errSegmentParser
 				}
 
 				// Check if segment parser actually contains a suitable end token
 				if !segmentParser.hasEndType() {
-					return 0, fmt.Errorf("'%s' segment has no end keyword", tokenCurrent.Value)
+					return 0, &Error{
+						Position: tokenCurrent.Start,
+						Text:     tokenCurrent.Raw,
+						Segment:  tokenCurrent,
+						Message:  fmt.Sprintf("'%s' segment has no end keyword", tokenCurrent.Value),
+					}
 				}
 
 				// Parse subsegment
@@ -241,17 +404,11 @@ func (r *Parser) parseSegment() (int, error) {
 				}
 
 				// Append subsegment result to parent segment result
-				segmentFormatter := segmentParser.buildFormatter()
-				if segmentFormatter == nil {
-					return 0, fmt.Errorf("invalid segment result: %#v", segmentParser.result)
-				}
-
-				// Append subsegment result to parent segment result
-				r.result = append(r.result, segmentFormatter)
+				r.result = append(r.result, segmentParser.buildSegment(idxEndSegment))
 
 				// Skip tokens that were processed as a subsegment parser
 				switch tokenCurrent.Type {
-				case lexer.STARTPARENTHESIS, lexer.CASE, lexer.FUNCTION, lexer.TYPE:
+				case lexer.STARTPARENTHESIS, lexer.CASE, lexer.FUNCTION, lexer.TYPE, lexer.OVER:
 					idx += idxEndSegment + 1 // Some types have end tags, e.g. "END" closing "CASE" or ")" closing "(". Next token starts after them.
 				default:
 					idx += idxEndSegment
@@ -263,13 +420,7 @@ func (r *Parser) parseSegment() (int, error) {
 		}
 
 		// Append token to result
-		r.result = append(r.result, formatters.Token{
-			Options: r.options,
-			Token: lexer.Token{
-				Type:  tokenCurrent.Type,
-				Value: tokenCurrent.Value,
-			},
-		})
+		r.result = append(r.result, &ast.Token{Token: tokenCurrent})
 
 		// Increase index to continue with next token
 		idx++
@@ -316,6 +467,32 @@ func (r *Parser) isEndToken(idx int) bool {
 		}
 	}
 
+	// ON CONFLICT and ON DUPLICATE KEY clauses end the preceding clauses of an INSERT statement, unless
+	// they are enclosed, e.g. by parentheses
+	if isConflictStart(r.tokens, idx) {
+		switch tokenFirst.Type {
+		case lexer.STARTPARENTHESIS, lexer.CASE, lexer.FUNCTION, lexer.TYPE, lexer.OVER:
+		default:
+			return true
+		}
+	}
+
+	// Clauses within window definitions of the WINDOW clause, e.g. ORDER BY, and the parenthesis closing them
+	// do not end it
+	if tokenFirst.Type == lexer.WINDOW && r.isWithinParenthesis() {
+		return false
+	}
+
+	// Options following the query of a CREATE VIEW statement, e.g. WITH NO DATA, end its clauses, unless they
+	// are enclosed, e.g. by parentheses
+	if isViewOptionStart(r.tokens, idx) {
+		switch tokenFirst.Type {
+		case lexer.STARTPARENTHESIS, lexer.CASE, lexer.FUNCTION, lexer.TYPE, lexer.OVER, lexer.CREATE:
+		default:
+			return true
+		}
+	}
+
 	// Check if token is end token
 	for _, tokenEndType := range r.endTypes {
 		if tokenCurrent.Type == tokenEndType || tokenCurrent.Type == lexer.EOF {
@@ -353,11 +530,118 @@ func (r *Parser) isNewSegment(idx int) bool {
 		return false
 	}
 
+	// Not a new segment, if window name following OVER instead of a window specification in parenthesis
+	if tokenCurrent.Type == lexer.OVER && tokenNext.Type != lexer.STARTPARENTHESIS {
+		return false
+	}
+
+	// Not a new segment, if clause within window specification or WINDOW clause, which lay out the partitioning,
+	// ordering and frame themselves. The parenthesis enclosing a window specification belongs to it, other
+	// parentheses, functions, types and CASE expressions are nested.
+	if tokenFirst.Type == lexer.OVER || tokenFirst.Type == lexer.WINDOW {
+		switch {
+		case tokenCurrent.Type == lexer.STARTPARENTHESIS && (tokenPrevious.Type == lexer.OVER || tokenPrevious.Type == lexer.AS):
+			return false
+		case tokenCurrent.Type == lexer.STARTPARENTHESIS, tokenCurrent.Type == lexer.FUNCTION,
+			tokenCurrent.Type == lexer.TYPE, tokenCurrent.Type == lexer.CASE:
+		default:
+			return false
+		}
+	}
+
+	// Not a new segment, if WITH introduces options of a CREATE statement instead of common table expressions,
+	// e.g. the storage parameters WITH (fillfactor = 70) or WITH NO DATA following the query of a view
+	if tokenCurrent.Type == lexer.WITH && tokenFirst.Type == lexer.CREATE && tokenNext.Type == lexer.STARTPARENTHESIS {
+		return false
+	}
+	if tokenCurrent.Type == lexer.WITH && isViewOptionStart(r.tokens, idx) {
+		return false
+	}
+
+	// Not a new segment, if clause within CREATE INDEX, which lays out its column list and options itself. Only
+	// functions, types, CASE expressions, parentheses within the column list and the WHERE clause are nested.
+	if tokenFirst.Type == lexer.CREATE && createdObject(r.tokens) == lexer.INDEX {
+		switch tokenCurrent.Type {
+		case lexer.STARTPARENTHESIS:
+			return r.isWithinParenthesis()
+		case lexer.FUNCTION, lexer.TYPE, lexer.CASE, lexer.WHERE:
+		default:
+			return false
+		}
+	}
+
+	// Not a new segment, if clause within CREATE FUNCTION or CREATE PROCEDURE, which lays out its signature and
+	// options itself, e.g. SET of a configuration parameter. Only functions, types, CASE expressions and
+	// parentheses within the parameter list are nested.
+	if tokenFirst.Type == lexer.CREATE && createdObject(r.tokens) == lexer.ROUTINE {
+		switch tokenCurrent.Type {
+		case lexer.STARTPARENTHESIS:
+			return r.isWithinParenthesis()
+		case lexer.FUNCTION, lexer.TYPE, lexer.CASE:
+		default:
+			return false
+		}
+	}
+
+	// Not a new segment, if clause within CREATE TRIGGER, which lays out its timing, events and condition itself,
+	// e.g. INSERT OR UPDATE. Only functions, types and CASE expressions are nested.
+	if tokenFirst.Type == lexer.CREATE && createdObject(r.tokens) == lexer.TRIGGER {
+		switch tokenCurrent.Type {
+		case lexer.FUNCTION, lexer.TYPE, lexer.CASE:
+		default:
+			return false
+		}
+	}
+
+	// Not a new segment, if clause within GRANT, REVOKE, ALTER DEFAULT PRIVILEGES or CREATE ROLE, which lay out
+	// their privileges, objects and options themselves, e.g. SELECT of GRANT SELECT
+	if tokenFirst.Type == lexer.GRANT || tokenFirst.Type == lexer.REVOKE || isAlterDefaultPrivileges(r.tokens) ||
+		tokenFirst.Type == lexer.CREATE && createdObject(r.tokens) == lexer.ROLE {
+		return false
+	}
+
+	// Not a new segment, if clause within CREATE POLICY or ALTER POLICY, which lays out its command and roles
+	// itself. The expressions of USING and WITH CHECK are nested, as well as functions, types and CASE expressions.
+	if isPolicy(r.tokens) {
+		switch {
+		case tokenCurrent.Type == lexer.STARTPARENTHESIS &&
+			(tokenPrevious.Type == lexer.USING || strings.EqualFold(tokenPrevious.Value, "CHECK")):
+		case tokenCurrent.Type == lexer.FUNCTION, tokenCurrent.Type == lexer.TYPE, tokenCurrent.Type == lexer.CASE:
+		default:
+			return false
+		}
+	}
+
+	// Not a new segment, if OR of CREATE OR REPLACE
+	if tokenCurrent.Type == lexer.OR && tokenNext.Type == lexer.REPLACE {
+		return false
+	}
+
 	// Not a new segment, if AND/OR within CASE
 	if tokenFirst.Type == lexer.CASE && (tokenCurrent.Type == lexer.AND || tokenCurrent.Type == lexer.OR) {
 		return false
 	}
 
+	// Not a new segment, if clause within ON CONFLICT or ON DUPLICATE KEY clause, which lays out its target and
+	// action itself. Only parentheses, functions, types and CASE expressions are nested.
+	if isConflictStart(r.tokens, 0) {
+		switch tokenCurrent.Type {
+		case lexer.STARTPARENTHESIS, lexer.FUNCTION, lexer.TYPE, lexer.CASE:
+		default:
+			return false
+		}
+	}
+
+	// Not a new segment, if clause within MERGE, which lays out its clauses and WHEN branches itself. Only
+	// parentheses, functions, types and CASE expressions are nested.
+	if tokenFirst.Type == lexer.MERGE {
+		switch tokenCurrent.Type {
+		case lexer.STARTPARENTHESIS, lexer.FUNCTION, lexer.TYPE, lexer.CASE:
+		default:
+			return false
+		}
+	}
+
 	//
 	// Positive indicators:
 	//
@@ -388,100 +672,272 @@ func (r *Parser) isNewSegment(idx int) bool {
 	return false
 }
 
-// buildFormatter creates a Formatter for the intermediate Parser subsegment, representing a
-// segment of the SQL query, which can then be appended to the result sequence
-func (r *Parser) buildFormatter() formatters.Formatter {
+// isWithinParenthesis determines if the tokens processed so far contain a parenthesis, which is not closed yet.
+// Nested parenthesis segments are not considered, only parentheses being part of the segment itself.
+func (r *Parser) isWithinParenthesis() bool {
+	var depth int
+	for _, node := range r.result {
+		if token, ok := node.(*ast.Token); ok {
+			switch token.Type {
+			case lexer.STARTPARENTHESIS:
+				depth++
+			case lexer.ENDPARENTHESIS:
+				depth--
+			}
+		}
+	}
+	return depth > 0
+}
+
+// createdObject returns the type of the keyword naming the kind of object created by a CREATE statement, e.g.
+// lexer.VIEW for CREATE OR REPLACE VIEW or lexer.INDEX for CREATE UNIQUE INDEX
+func createdObject(tokens []lexer.Token) lexer.TokenType {
+	for _, token := range tokens[1:] {
+		switch {
+		case token.Type == lexer.GROUP, strings.EqualFold(token.Value, "USER"):
+			return lexer.ROLE // Users and groups are roles with different defaults
+		case token.Type == lexer.OR, token.Type == lexer.REPLACE, token.Type == lexer.UNIQUE,
+			token.Type == lexer.MATERIALIZED, token.Type == lexer.EVENT, token.Type == lexer.IDENT:
+			// Modifiers, e.g. TEMPORARY
+		default:
+			return token.Type
+		}
+	}
+	return lexer.EOF
+}
+
+// isAlterDefaultPrivileges determines if the tokens form an ALTER DEFAULT PRIVILEGES statement, which grants or
+// revokes privileges on objects created in the future
+func isAlterDefaultPrivileges(tokens []lexer.Token) bool {
+	return len(tokens) > 2 && tokens[0].Type == lexer.ALTER && strings.EqualFold(tokens[1].Value, "DEFAULT") &&
+		strings.EqualFold(tokens[2].Value, "PRIVILEGES")
+}
+
+// isPolicy determines if the tokens form a CREATE POLICY or ALTER POLICY statement
+func isPolicy(tokens []lexer.Token) bool {
+	switch tokens[0].Type {
+	case lexer.CREATE:
+		return createdObject(tokens) == lexer.POLICY
+	case lexer.ALTER:
+		return len(tokens) > 1 && tokens[1].Type == lexer.POLICY
+	}
+	return false
+}
+
+// isViewOptionStart determines if the token at index idx introduces an option following the query of a
+// CREATE VIEW statement, i.e. WITH [NO] DATA or WITH [CASCADED | LOCAL] CHECK OPTION
+func isViewOptionStart(tokens []lexer.Token, idx int) bool {
+	if tokens[idx].Type != lexer.WITH || idx+2 >= len(tokens) {
+		return false
+	}
+	switch tokenNext := tokens[idx+1]; {
+	case tokenNext.Type == lexer.IDENT:
+
+		// Names of common table expressions are followed by AS or their column list, e.g. WITH data AS (...)
+		if tokens[idx+2].Type == lexer.AS || tokens[idx+2].Type == lexer.STARTPARENTHESIS {
+			return false
+		}
+		for _, option := range []string{"NO", "DATA", "CASCADED", "LOCAL", "CHECK"} {
+			if strings.EqualFold(tokenNext.Value, option) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isConflictStart determines if the token at index idx introduces an ON CONFLICT or ON DUPLICATE KEY clause
+func isConflictStart(tokens []lexer.Token, idx int) bool {
+	return tokens[idx].Type == lexer.ON && idx+1 < len(tokens) &&
+		(tokens[idx+1].Type == lexer.CONFLICT || tokens[idx+1].Type == lexer.DUPLICATE)
+}
+
+// buildSegment creates a segment from the intermediate Parser result, which ended at the token with index
+// idxEnd. The end token is added to the segment, if it closes the segment, e.g. ")" closing "(".
+func (r *Parser) buildSegment(idxEnd int) *ast.Segment {
 
 	// Get variables to work with
 	elements := r.result
+	tokenEnd := r.tokens[idxEnd]
+
+	// Add closing end token, unless the segment was unterminated
+	switch r.tokens[0].Type {
+	case lexer.STARTPARENTHESIS, lexer.CASE, lexer.FUNCTION, lexer.TYPE, lexer.OVER:
+		if tokenEnd.Type != lexer.EOF {
+			elements = append(elements, &ast.Token{Token: tokenEnd})
+		}
+	}
+
+	// Return segment
+	return &ast.Segment{Children: elements}
+}
+
+// buildElements creates Formatters for a sequence of segment children. Tokens are wrapped as they are, nested
+// segments are turned into Formatter groups.
+func (r *Parser) buildElements(children []ast.Node) ([]formatters.Formatter, error) {
+	var elements []formatters.Formatter
+	for _, child := range children {
+		switch node := child.(type) {
+		case *ast.Token:
+			elements = append(elements, formatters.Token{Options: r.options, Token: node.Token})
+		case *ast.Segment:
+			segmentFormatter, errFormatter := r.buildFormatter(node)
+			if errFormatter != nil {
+				return nil, errFormatter
+			}
+			if segmentFormatter == nil {
+				token := node.Tokens()[0]
+				return nil, &Error{
+					Position: token.Start,
+					Text:     token.Raw,
+					Segment:  token,
+					Message:  fmt.Sprintf("unsupported '%s' segment", token.Value),
+				}
+			}
+			elements = append(elements, segmentFormatter)
+		}
+	}
+	return elements, nil
+}
+
+// buildFormatter creates a Formatter for a segment of the SQL query, which can then be appended to the result
+// sequence
+func (r *Parser) buildFormatter(segment *ast.Segment) (formatters.Formatter, error) {
+
+	// Get variables to work with
+	elements, errElements := r.buildElements(segment.Children)
+	if errElements != nil {
+		return nil, errElements
+	}
+
+	// Write region verbatim, if it could not be parsed
+	if segment.Err != nil {
+		return &formatters.Verbatim{Options: r.options, Elements: elements, Err: segment.Err}, nil
+	}
 	firstElement, _ := elements[0].(formatters.Token)
 
 	// Build suitable Formatter group and return it
 	switch firstElement.Type {
 	case lexer.COMMENT: // Just in case first element of SQL string is query.
 		// Otherwise, comment is just a normal token within a series of elements of another formatter
-		return &formatters.Token{Options: r.options, Token: lexer.Token{
-			Type:  firstElement.Type,
-			Value: firstElement.Value,
-		},
-		}
+		return &formatters.Token{Options: r.options, Token: firstElement.Token}, nil
 	case lexer.SELECT:
-		return &formatters.Select{Options: r.options, Elements: elements}
+		return &formatters.Select{Options: r.options, Elements: elements}, nil
 	case lexer.FROM:
-		return &formatters.From{Options: r.options, Elements: elements}
+		return &formatters.From{Options: r.options, Elements: elements}, nil
 	case lexer.JOIN, lexer.INNER, lexer.OUTER, lexer.LEFT, lexer.RIGHT, lexer.NATURAL, lexer.CROSS:
-		return &formatters.Join{Options: r.options, Elements: elements}
+		return &formatters.Join{Options: r.options, Elements: elements}, nil
 	case lexer.WHERE:
-		return &formatters.Where{Options: r.options, Elements: elements}
+		return &formatters.Where{Options: r.options, Elements: elements}, nil
 	case lexer.AND:
-		return &formatters.And{Options: r.options, Elements: elements}
+		return &formatters.And{Options: r.options, Elements: elements}, nil
 	case lexer.OR:
-		return &formatters.Or{Options: r.options, Elements: elements}
+		return &formatters.Or{Options: r.options, Elements: elements}, nil
 	case lexer.GROUP:
-		return &formatters.GroupBy{Options: r.options, Elements: elements}
+		return &formatters.GroupBy{Options: r.options, Elements: elements}, nil
 	case lexer.ORDER:
-		return &formatters.OrderBy{Options: r.options, Elements: elements}
+		return &formatters.OrderBy{Options: r.options, Elements: elements}, nil
 	case lexer.HAVING:
-		return &formatters.Having{Options: r.options, Elements: elements}
+		return &formatters.Having{Options: r.options, Elements: elements}, nil
 	case lexer.LIMIT, lexer.OFFSET, lexer.FETCH:
-		return &formatters.Limit{Options: r.options, Elements: elements}
+		return &formatters.Limit{Options: r.options, Elements: elements}, nil
 	case lexer.UNION, lexer.INTERSECT, lexer.EXCEPT:
-		return &formatters.TieGroup{Options: r.options, Elements: elements}
+		return &formatters.TieGroup{Options: r.options, Elements: elements}, nil
 	case lexer.SET:
-		return &formatters.Set{Options: r.options, Elements: elements}
+		return &formatters.Set{Options: r.options, Elements: elements}, nil
 	case lexer.RETURNING:
-		return &formatters.Returning{Options: r.options, Elements: elements}
+		return &formatters.Returning{Options: r.options, Elements: elements}, nil
 	case lexer.LOCK:
-		return &formatters.Lock{Options: r.options, Elements: elements}
+		return &formatters.Lock{Options: r.options, Elements: elements}, nil
 	case lexer.INSERT:
-		return &formatters.Insert{Options: r.options, Elements: elements}
+		return &formatters.Insert{Options: r.options, Elements: elements}, nil
 	case lexer.VALUES:
-		return &formatters.Values{Options: r.options, Elements: elements}
+		return &formatters.Values{Options: r.options, Elements: elements}, nil
 	case lexer.WITH:
-		return &formatters.With{Options: r.options, Elements: elements}
+		return &formatters.With{Options: r.options, Elements: elements}, nil
+	case lexer.MERGE:
+		return &formatters.Merge{Options: r.options, Elements: elements}, nil
+	case lexer.OVER, lexer.WINDOW:
+		return &formatters.Window{Options: r.options, Elements: elements}, nil
+	case lexer.ON:
+		return &formatters.Conflict{Options: r.options, Elements: elements}, nil
 	case lexer.CASE:
 
-		// End token of CASE group("END") has to be added to the group
-		endToken := formatters.Token{Options: r.options, Token: lexer.Token{Type: lexer.END, Value: "END"}}
-		elements = append(elements, endToken)
-		return &formatters.Case{Options: r.options, Elements: elements}
+		// End token of CASE group ("END") has to be part of the group
+		elements = r.closeElements(elements, lexer.END, "END")
+		return &formatters.Case{Options: r.options, Elements: elements}, nil
 
 	case lexer.STARTPARENTHESIS:
 
-		// End token of sub query group (")") has to be added in the group
-		endToken := formatters.Token{Options: r.options, Token: lexer.Token{Type: lexer.ENDPARENTHESIS, Value: ")"}}
-		elements = append(elements, endToken)
+		// End token of sub query group (")") has to be part of the group
+		elements = r.closeElements(elements, lexer.ENDPARENTHESIS, ")")
 
 		// Create subquery indenter if first keyword is SELECT or related keyword. Subqueries are not a
 		// lot different to parenthesis groups, but this gives us additional information and format control
 		switch elements[1].(type) {
 		case *formatters.Select:
-			return &formatters.Subquery{Options: r.options, Elements: elements}
+			return &formatters.Subquery{Options: r.options, Elements: elements}, nil
 		}
 
 		// Return normal parenthesis group otherwise
-		return &formatters.Parenthesis{Options: r.options, Elements: elements}
+		return &formatters.Parenthesis{Options: r.options, Elements: elements}, nil
 
 	case lexer.FUNCTION:
 
-		// End token of function group (")") has to be added in the group
-		endToken := formatters.Token{Options: r.options, Token: lexer.Token{Type: lexer.ENDPARENTHESIS, Value: ")"}}
-		elements = append(elements, endToken)
-		return &formatters.Function{Options: r.options, Elements: elements}
+		// End token of function group (")") has to be part of the group
+		elements = r.closeElements(elements, lexer.ENDPARENTHESIS, ")")
+		return &formatters.Function{Options: r.options, Elements: elements}, nil
 
 	case lexer.TYPE:
 
-		// End token of TYPE group (")") has to be added in the group
-		endToken := formatters.Token{Options: r.options, Token: lexer.Token{Type: lexer.ENDPARENTHESIS, Value: ")"}}
-		elements = append(elements, endToken)
-		return &formatters.Type{Options: r.options, Elements: elements}
+		// End token of TYPE group (")") has to be part of the group
+		elements = r.closeElements(elements, lexer.ENDPARENTHESIS, ")")
+		return &formatters.Type{Options: r.options, Elements: elements}, nil
 
-	case lexer.CREATE, lexer.ALTER, lexer.UPDATE, lexer.DELETE, lexer.DROP,
+	case lexer.CREATE:
+		switch createdObject(segment.Tokens()) {
+		case lexer.VIEW:
+			return &formatters.CreateView{Options: r.options, Elements: elements}, nil
+		case lexer.INDEX:
+			return &formatters.CreateIndex{Options: r.options, Elements: elements}, nil
+		case lexer.ROUTINE:
+			return &formatters.Routine{Options: r.options, Elements: r.buildRoutine(elements)}, nil
+		case lexer.TRIGGER:
+			return &formatters.Trigger{Options: r.options, Elements: elements}, nil
+		case lexer.ROLE:
+			return &formatters.Role{Options: r.options, Elements: elements}, nil
+		case lexer.POLICY:
+			return &formatters.Policy{Options: r.options, Elements: elements}, nil
+		}
+		return &formatters.Generic{Options: r.options, Elements: elements}, nil
+	case lexer.GRANT, lexer.REVOKE:
+		return &formatters.Grant{Options: r.options, Elements: elements}, nil
+	case lexer.ALTER:
+		switch {
+		case isAlterDefaultPrivileges(segment.Tokens()):
+			return &formatters.Grant{Options: r.options, Elements: elements}, nil
+		case isPolicy(segment.Tokens()):
+			return &formatters.Policy{Options: r.options, Elements: elements}, nil
+		}
+		return &formatters.Generic{Options: r.options, Elements: elements}, nil
+	case lexer.DO:
+		return &formatters.Routine{Options: r.options, Elements: r.buildRoutine(elements)}, nil
+	case lexer.UPDATE, lexer.DELETE, lexer.DROP,
 		lexer.SHOW, lexer.DISCARD, lexer.BEGIN, lexer.SAVEPOINT, lexer.RELEASE, lexer.ROLLBACK, lexer.COMMIT,
 		lexer.ANALYZE, lexer.VACUUM, lexer.RESET, lexer.COPY, lexer.EXPLAIN:
-		return &formatters.Generic{Options: r.options, Elements: elements}
+		return &formatters.Generic{Options: r.options, Elements: elements}, nil
 	}
 
 	// Return nil as no group could be built
-	return nil
+	return nil, nil
+}
+
+// closeElements makes sure that the group elements end with the given end token. It is added, if the
+// segment was not terminated by it.
+func (r *Parser) closeElements(elements []formatters.Formatter, ttype lexer.TokenType, value string) []formatters.Formatter {
+	if last, ok := elements[len(elements)-1].(formatters.Token); ok && last.Type == ttype && len(elements) > 1 {
+		return elements
+	}
+	endToken := formatters.Token{Options: r.options, Token: lexer.Token{Type: ttype, Value: value}}
+	return append(elements, endToken)
 }
@@ -1,6 +1,7 @@
 package parser
 
 import (
+	"github.com/noneymous/go-sqlfmt/sqlfmt/ast"
 	"github.com/noneymous/go-sqlfmt/sqlfmt/formatters"
 	"reflect"
 	"testing"
@@ -289,6 +290,49 @@ func TestParse(t *testing.T) {
 	}
 }
 
+func TestParse_Recover(t *testing.T) {
+	options := formatters.DefaultOptions()
+	options.Recover = true
+
+	tokenSource := []lexer.Token{
+		{Type: lexer.SELECT, Value: "SELECT"},
+		{Type: lexer.IDENT, Value: "a"},
+		{Type: lexer.ENDPARENTHESIS, Value: ")"},
+		{Type: lexer.IDENT, Value: "b"},
+		{Type: lexer.FROM, Value: "FROM"},
+		{Type: lexer.IDENT, Value: "t"},
+		{Type: lexer.EOF, Value: "EOF"},
+	}
+	got, err := Parse(tokenSource, options)
+	if err != nil {
+		t.Fatalf("ERROR: %#v", err)
+	}
+
+	// Verify that the region between SELECT and FROM clause is kept verbatim
+	if len(got) != 3 {
+		t.Fatalf("want 3 formatters, got %#v", got)
+	}
+	verbatim, ok := got[1].(*formatters.Verbatim)
+	if !ok {
+		t.Fatalf("want verbatim formatter, got %#v", got[1])
+	}
+	want := []formatters.Formatter{
+		formatters.Token{Options: options, Token: lexer.Token{Type: lexer.ENDPARENTHESIS, Value: ")"}},
+		formatters.Token{Options: options, Token: lexer.Token{Type: lexer.IDENT, Value: "b"}},
+	}
+	if !reflect.DeepEqual(verbatim.Elements, want) || verbatim.Err == nil {
+		t.Errorf("\nwant %#v, \ngot  %#v", want, verbatim.Elements)
+	}
+	if _, ok = got[2].(*formatters.From); !ok {
+		t.Errorf("want FROM formatter, got %#v", got[2])
+	}
+
+	// Verify that the region is not recovered by default
+	if _, errDefault := Parse(tokenSource, formatters.DefaultOptions()); errDefault == nil {
+		t.Errorf("expected error")
+	}
+}
+
 func TestNewParser(t *testing.T) {
 	options := formatters.DefaultOptions()
 	testingData := []lexer.Token{
@@ -407,7 +451,7 @@ func Test_parseSegment(t *testing.T) {
 			// Convert token sequence to string sequence
 			var gotStmt []string
 			for _, v := range r.result {
-				if tok, ok := v.(formatters.Token); ok {
+				if tok, ok := v.(*ast.Token); ok {
 					gotStmt = append(gotStmt, tok.Value)
 				}
 			}
@@ -0,0 +1,461 @@
+package parser
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/noneymous/go-sqlfmt/sqlfmt/formatters"
+	"github.com/noneymous/go-sqlfmt/sqlfmt/lexer"
+)
+
+// plpgsqlStatements lists the words starting PL/pgSQL statements, which are written upper-cased, although they
+// are not necessarily known to the lexer. The words following some of them are upper-cased too, e.g. NOTICE of
+// RAISE NOTICE.
+var plpgsqlStatements = map[string][]string{
+	"PERFORM": nil, "RETURN": {"QUERY", "NEXT"}, "EXIT": nil, "CONTINUE": nil, "GET": {"STACKED", "CURRENT", "DIAGNOSTICS"},
+	"EXECUTE": nil, "OPEN": nil, "CLOSE": nil, "MOVE": nil, "ASSERT": nil, "CALL": nil, "NULL": nil,
+	"RAISE": {"DEBUG", "LOG", "INFO", "NOTICE", "WARNING", "EXCEPTION"},
+}
+
+// bodyFrame is a control structure of a PL/pgSQL body, which is not closed yet, e.g. a block or an IF statement
+type bodyFrame struct {
+	kind   string // Word opening the control structure, e.g. "BEGIN" or "IF"
+	levels int    // Number of indentation levels added by the control structure
+}
+
+// buildRoutine replaces dollar-quoted bodies of a CREATE FUNCTION, CREATE PROCEDURE or DO statement with Body
+// Formatters, if they are written in a supported language. Other bodies are kept as they are.
+func (r *Parser) buildRoutine(elements []formatters.Formatter) []formatters.Formatter {
+
+	// Determine the language of the routine. DO statements default to PL/pgSQL, created routines must declare it.
+	var language string
+	var isDo = false
+	var previousToken formatters.Token
+	for i, el := range elements {
+		if token, ok := el.(formatters.Token); ok {
+			if i == 0 && token.Type == lexer.DO {
+				isDo, language = true, "plpgsql"
+			}
+			if strings.EqualFold(previousToken.Value, "LANGUAGE") {
+				language = strings.ToLower(strings.Trim(token.Value, `"'`))
+			}
+			previousToken = token
+		} else {
+			previousToken = formatters.Token{}
+		}
+	}
+
+	// Replace dollar-quoted body, which follows AS of created routines
+	var result = make([]formatters.Formatter, 0, len(elements))
+	previousToken = formatters.Token{}
+	for _, el := range elements {
+		if token, ok := el.(formatters.Token); ok && token.Type == lexer.STRING && (isDo || previousToken.Type == lexer.AS) {
+			if body := r.buildBody(token.Token, language); body != nil {
+				el = body
+			}
+		}
+		result = append(result, el)
+		previousToken, _ = el.(formatters.Token)
+	}
+
+	// Return elements with formatted body
+	return result
+}
+
+// buildBody creates a Formatter for a dollar-quoted routine body written in the given language. Returns nil, if
+// the body should be kept as it is, e.g. because it is written in an unsupported language or could not be parsed.
+func (r *Parser) buildBody(token lexer.Token, language string) formatters.Formatter {
+
+	// Extract the delimiter and content of the dollar-quoted string
+	tag := dollarTag(token.Value)
+	if tag == "" || len(token.Value) < 2*len(tag) || !strings.HasSuffix(token.Value, tag) {
+		return nil
+	}
+	content := token.Value[len(tag) : len(token.Value)-len(tag)]
+
+	// Tokenize content like the surrounding statement
+	tokens, errTokenize := lexer.TokenizeWithConfig(content, lexer.Config{
+		Dialect:                 r.options.Dialect,
+		DisableFunctionKeywords: r.options.DisableFunctionKeywords,
+		Registry:                r.options.Registry,
+	})
+	if errTokenize != nil || len(tokens) < 2 {
+		return nil
+	}
+	tokens = mergeBodyTokens(tokens)
+
+	// Parse lines of the body according to its language
+	var lines []formatters.Formatter
+	var errLines error
+	switch language {
+	case "plpgsql":
+		lines, errLines = r.parsePlpgsql(tokens)
+	case "sql":
+		lines, errLines = r.parseSqlBody(tokens)
+	default:
+		return nil
+	}
+	if errLines != nil {
+		return nil
+	}
+
+	// Return body Formatter
+	return &formatters.Body{Options: r.options, Elements: lines, Tag: tag}
+}
+
+// parseSqlBody parses the statements of a body written in SQL, each of them is written like a standalone one
+func (r *Parser) parseSqlBody(tokens []lexer.Token) ([]formatters.Formatter, error) {
+
+	// Iterate statements and build a line for each of them
+	var lines []formatters.Formatter
+	for idx := 0; tokens[idx].Type != lexer.EOF; {
+		idxEnd := statementEnd(tokens, idx)
+		lines = append(lines, r.buildBodyStatement(tokens[idx:idxEnd]))
+		idx = idxEnd
+	}
+
+	// Return lines
+	return lines, nil
+}
+
+// parsePlpgsql parses the block structure of a body written in PL/pgSQL. Control structures, like blocks,
+// conditions and loops, indent the statements they contain. Embedded SQL statements are formatted like
+// standalone ones.
+func (r *Parser) parsePlpgsql(tokens []lexer.Token) ([]formatters.Formatter, error) {
+
+	// Prepare process variables
+	var lines []formatters.Formatter
+	var frames []bodyFrame
+	var depth int
+
+	// Prepare function returning the kind of the innermost control structure
+	top := func() string {
+		if len(frames) == 0 {
+			return ""
+		}
+		return frames[len(frames)-1].kind
+	}
+
+	// Prepare function appending a line with the given tokens and relative indentation
+	appendLine := func(lineTokens []lexer.Token, level int) {
+		var elements []formatters.Formatter
+		for _, token := range lineTokens {
+			elements = append(elements, formatters.Token{Options: r.options, Token: token})
+		}
+		line := &formatters.BodyLine{Options: r.options, Elements: elements}
+		line.AddIndent(depth + level)
+		lines = append(lines, line)
+	}
+
+	// Iterate tokens and build lines from them
+	for idx := 0; tokens[idx].Type != lexer.EOF; {
+		token := tokens[idx]
+		word := strings.ToUpper(token.Value)
+		if token.Type == lexer.STRING || token.Type == lexer.COMMENT {
+			word = ""
+		}
+
+		switch {
+
+		// Write comments and labels on lines of their own
+		case token.Type == lexer.COMMENT, strings.HasPrefix(token.Value, "<<"):
+			appendLine(tokens[idx:idx+1], 0)
+			idx++
+
+		// Open declaration section of a block
+		case word == "DECLARE":
+			appendLine(upperTokens(tokens[idx:idx+1]), 0)
+			frames = append(frames, bodyFrame{kind: word, levels: 1})
+			depth++
+			idx++
+
+		// Open statement section of a block, which closes its declaration section
+		case word == "BEGIN":
+			if top() == "DECLARE" {
+				frames = frames[:len(frames)-1]
+				depth--
+			}
+			appendLine(tokens[idx:idx+1], 0)
+			frames = append(frames, bodyFrame{kind: word, levels: 1})
+			depth++
+			idx++
+
+		// Open exception section of a block. Handlers are indented by one level, their statements by two.
+		case word == "EXCEPTION" && top() == "BEGIN":
+			appendLine(upperTokens(tokens[idx:idx+1]), -1)
+			frames[len(frames)-1] = bodyFrame{kind: word, levels: 2}
+			depth++
+			idx++
+
+		// Open condition, CASE statement or loop with a header written on a line of its own. Branches of CASE
+		// statements are indented by one level, their statements by two.
+		case word == "IF":
+			idxEnd := headerEnd(tokens, idx, "THEN") + 1
+			appendLine(upperTokens(tokens[idx:idxEnd]), 0)
+			frames = append(frames, bodyFrame{kind: word, levels: 1})
+			depth++
+			idx = idxEnd
+		case word == "CASE":
+			idxEnd := headerEnd(tokens, idx, "WHEN")
+			appendLine(upperTokens(tokens[idx:idxEnd]), 0)
+			frames = append(frames, bodyFrame{kind: word, levels: 2})
+			depth += 2
+			idx = idxEnd
+		case word == "LOOP", word == "WHILE", word == "FOR", word == "FOREACH":
+			idxEnd := idx + 1
+			if word != "LOOP" {
+				idxEnd = headerEnd(tokens, idx, "LOOP") + 1
+			}
+			appendLine(upperTokens(tokens[idx:idxEnd]), 0)
+			frames = append(frames, bodyFrame{kind: "LOOP", levels: 1})
+			depth++
+			idx = idxEnd
+
+		// Write branches of control structures on the level of their header
+		case (word == "ELSIF" || word == "ELSEIF") && top() == "IF":
+			idxEnd := headerEnd(tokens, idx, "THEN") + 1
+			appendLine(upperTokens(tokens[idx:idxEnd]), -1)
+			idx = idxEnd
+		case word == "ELSE" && (top() == "IF" || top() == "CASE"):
+			appendLine(tokens[idx:idx+1], -1)
+			idx++
+		case word == "WHEN" && (top() == "CASE" || top() == "EXCEPTION"):
+			idxEnd := headerEnd(tokens, idx, "THEN") + 1
+			appendLine(upperTokens(tokens[idx:idxEnd]), -1)
+			idx = idxEnd
+
+		// Close innermost control structure
+		case word == "END":
+			if len(frames) == 0 {
+				return nil, fmt.Errorf("unexpected END at %s", token.Start)
+			}
+			depth -= frames[len(frames)-1].levels
+			frames = frames[:len(frames)-1]
+			idxEnd := statementEnd(tokens, idx)
+			appendLine(upperTokens(tokens[idx:idxEnd]), 0)
+			idx = idxEnd
+
+		// Write common statements, embedded SQL statements are formatted like standalone ones
+		default:
+			idxEnd := statementEnd(tokens, idx)
+			line := r.buildBodyStatement(tokens[idx:idxEnd])
+			line.AddIndent(depth)
+			lines = append(lines, line)
+			idx = idxEnd
+		}
+
+		// Abort if the header of a control structure is not terminated
+		if idx > len(tokens)-1 {
+			return nil, fmt.Errorf("unexpected end of body")
+		}
+	}
+
+	// Abort if control structures were not closed
+	if len(frames) > 0 {
+		return nil, fmt.Errorf("missing END of %s", top())
+	}
+
+	// Return lines
+	return lines, nil
+}
+
+// buildBodyStatement creates a line for a single statement of a routine body, including its terminating
+// semicolon. Embedded SQL statements are parsed and formatted like standalone ones, possibly following the
+// words of a PL/pgSQL statement, e.g. RETURN QUERY. Other statements are written as they are.
+func (r *Parser) buildBodyStatement(tokens []lexer.Token) *formatters.BodyLine {
+
+	// Split statement into its PL/pgSQL prefix, its SQL statement and its terminator
+	var idxSql = len(tokens)
+	var idxEnd = len(tokens)
+	if tokens[len(tokens)-1].Type == lexer.SEMICOLON {
+		idxEnd--
+	}
+	tokens = upperTokens(tokens)
+	switch {
+	case isSqlStart(tokens[0].Type):
+		idxSql = 0
+	case len(tokens) > 2 && tokens[0].Value == "RETURN" && tokens[1].Value == "QUERY" && isSqlStart(tokens[2].Type):
+		idxSql = 2
+	}
+
+	// Add prefix tokens
+	var elements []formatters.Formatter
+	for _, token := range tokens[:min(idxSql, idxEnd)] {
+		elements = append(elements, formatters.Token{Options: r.options, Token: token})
+	}
+
+	// Parse embedded SQL statement. Parsing must not recover, the statement is written as it is otherwise.
+	if idxSql < idxEnd {
+		options := *r.options
+		options.Recover = false
+		statementTokens := append(append([]lexer.Token{}, tokens[idxSql:idxEnd]...), lexer.Token{Type: lexer.EOF})
+		statementFormatters, errParse := Parse(statementTokens, &options)
+		if errParse == nil {
+			elements = append(elements, statementFormatters...)
+		} else {
+			for _, token := range tokens[idxSql:idxEnd] {
+				elements = append(elements, formatters.Token{Options: r.options, Token: token})
+			}
+		}
+	}
+
+	// Add terminator
+	for _, token := range tokens[idxEnd:] {
+		elements = append(elements, formatters.Token{Options: r.options, Token: token})
+	}
+
+	// Return line
+	return &formatters.BodyLine{Options: r.options, Elements: elements}
+}
+
+// isSqlStart determines if a token of the given type starts an SQL statement, which can be embedded into a
+// routine body
+func isSqlStart(ttype lexer.TokenType) bool {
+	switch ttype {
+	case lexer.SELECT, lexer.INSERT, lexer.UPDATE, lexer.DELETE, lexer.WITH, lexer.MERGE, lexer.VALUES:
+		return true
+	}
+	return false
+}
+
+// statementEnd returns the index of the token following the statement starting at index idx, i.e. the index
+// after its terminating semicolon. Semicolons within parentheses do not terminate a statement.
+func statementEnd(tokens []lexer.Token, idx int) int {
+	var depth int
+	for ; tokens[idx].Type != lexer.EOF; idx++ {
+		switch tokens[idx].Type {
+		case lexer.STARTPARENTHESIS:
+			depth++
+		case lexer.ENDPARENTHESIS:
+			depth--
+		case lexer.SEMICOLON:
+			if depth <= 0 {
+				return idx + 1
+			}
+		}
+	}
+	return idx
+}
+
+// headerEnd returns the index of the first token with one of the given words following the header of a control
+// structure starting at index idx, e.g. THEN of IF ... THEN. Returns the index of the EOF token, if there is none.
+func headerEnd(tokens []lexer.Token, idx int, words ...string) int {
+	var depth int
+	for idx++; tokens[idx].Type != lexer.EOF; idx++ {
+		switch tokens[idx].Type {
+		case lexer.STARTPARENTHESIS:
+			depth++
+		case lexer.ENDPARENTHESIS:
+			depth--
+		case lexer.STRING, lexer.COMMENT:
+		default:
+			for _, word := range words {
+				if depth <= 0 && strings.EqualFold(tokens[idx].Value, word) {
+					return idx
+				}
+			}
+		}
+	}
+	return idx
+}
+
+// upperTokens returns a copy of the tokens of a PL/pgSQL statement or header, whose PL/pgSQL keywords are
+// upper-cased. These are the leading words of statements and the words closing headers of control structures.
+// Labels of loops are kept as written, even if they are named like keywords, e.g. "outer" of EXIT outer.
+func upperTokens(tokens []lexer.Token) []lexer.Token {
+	result := append([]lexer.Token{}, tokens...)
+	var follow []string // Words upper-cased, if they directly follow the leading word of the statement
+	for i, token := range result {
+		if isLabelReference(result, i) {
+			result[i].Value = token.Raw
+			follow = nil
+			continue
+		}
+		if token.Type != lexer.IDENT {
+			follow = nil
+			continue
+		}
+		word := strings.ToUpper(token.Value)
+		switch {
+		case i == 0 && slices.Contains([]string{"DECLARE", "EXCEPTION", "ELSIF", "ELSEIF", "LOOP", "WHILE", "FOREACH"}, word):
+			result[i].Value = word
+		case i == 0:
+			if statementFollow, ok := plpgsqlStatements[word]; ok {
+				result[i].Value = word
+				follow = statementFollow
+			}
+		case slices.Contains(follow, word):
+			result[i].Value = word
+		case word == "THEN", word == "LOOP":
+			result[i].Value = word
+			follow = nil
+		case word == "OTHERS" && result[0].Type == lexer.WHEN: // Condition of exception handlers catching any error
+			result[i].Value = word
+			follow = nil
+		default:
+			follow = nil
+		}
+	}
+	return result
+}
+
+// isLabelReference determines if the token at index i of a PL/pgSQL statement references the label of a loop,
+// e.g. "outer" of EXIT outer WHEN ..., CONTINUE outer or END LOOP outer
+func isLabelReference(tokens []lexer.Token, i int) bool {
+	if tokens[i].Type == lexer.WHEN || tokens[i].Type == lexer.SEMICOLON || tokens[i].Type == lexer.COMMENT {
+		return false
+	}
+	switch i {
+	case 1:
+		return strings.EqualFold(tokens[0].Value, "EXIT") || strings.EqualFold(tokens[0].Value, "CONTINUE")
+	case 2:
+		return strings.EqualFold(tokens[0].Value, "END") && strings.EqualFold(tokens[1].Value, "LOOP")
+	}
+	return false
+}
+
+// mergeBodyTokens merges tokens of a routine body, which the lexer splits, but which are single tokens in
+// PL/pgSQL. These are the assignment operator ":=", type references like "t.a%TYPE" and labels like "<<outer>>".
+func mergeBodyTokens(tokens []lexer.Token) []lexer.Token {
+	var result []lexer.Token
+	for i := 0; i < len(tokens); i++ {
+		token := tokens[i]
+		switch {
+		case token.Type == lexer.COLON && tokens[i+1].Value == "=" && tokens[i+1].Start.Offset == token.End.Offset:
+			token = mergeTokens(lexer.COMPARATOR, tokens[i:i+2], ":=")
+			i++
+		case token.Type == lexer.IDENT && i+2 < len(tokens) && tokens[i+1].Value == "%" &&
+			(strings.EqualFold(tokens[i+2].Value, "TYPE") || strings.EqualFold(tokens[i+2].Value, "ROWTYPE")):
+			token = mergeTokens(lexer.IDENT, tokens[i:i+3], token.Value+"%"+strings.ToUpper(tokens[i+2].Value))
+			i += 2
+		case token.Value == "<<" && i+2 < len(tokens) && tokens[i+2].Value == ">>":
+			token = mergeTokens(lexer.IDENT, tokens[i:i+3], "<<"+tokens[i+1].Raw+">>")
+			i += 2
+		}
+		result = append(result, token)
+	}
+	return result
+}
+
+// mergeTokens returns a single token of the given type and value spanning the given tokens
+func mergeTokens(ttype lexer.TokenType, tokens []lexer.Token, value string) lexer.Token {
+	var raw string
+	for _, token := range tokens {
+		raw += token.Raw
+	}
+	return lexer.Token{Type: ttype, Value: value, Raw: raw, Start: tokens[0].Start, End: tokens[len(tokens)-1].End}
+}
+
+// dollarTag returns the opening delimiter of a dollar-quoted string, e.g. "$$" or "$body$". Returns an empty
+// string, if the value is not dollar-quoted.
+func dollarTag(value string) string {
+	if !strings.HasPrefix(value, "$") {
+		return ""
+	}
+	idx := strings.Index(value[1:], "$")
+	if idx < 0 {
+		return ""
+	}
+	return value[:idx+2]
+}
@@ -0,0 +1,20 @@
+package parser
+
+import (
+	"github.com/noneymous/go-sqlfmt/sqlfmt/ast"
+	"github.com/noneymous/go-sqlfmt/sqlfmt/lexer"
+)
+
+// ParseStatement parses the tokens of a single statement returning a typed syntax tree, e.g. an
+// *ast.SelectStmt. It is built from the same segments the Formatters returned by Parse are built from.
+func ParseStatement(tokens []lexer.Token) (ast.Statement, error) {
+
+	// Parse tokens into segments
+	segments, errParse := ParseSegments(tokens)
+	if errParse != nil {
+		return nil, errParse
+	}
+
+	// Build and return typed statement
+	return ast.NewStatement(segments), nil
+}
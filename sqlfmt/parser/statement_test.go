@@ -0,0 +1,187 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/noneymous/go-sqlfmt/sqlfmt/ast"
+	"github.com/noneymous/go-sqlfmt/sqlfmt/lexer"
+)
+
+func TestParseStatement(t *testing.T) {
+	tests := []struct {
+		name  string
+		sql   string
+		check func(t *testing.T, stmt ast.Statement)
+	}{
+		{
+			name: "SELECT statement with joins and conditions",
+			sql:  `select distinct a, count(*) as total from t1 u left join t2 on u.id = t2.id and t2.x > 1 where a = 1 and (b = 2 or c in (select id from t3)) group by a order by a desc limit 10 offset 5`,
+			check: func(t *testing.T, stmt ast.Statement) {
+				s := stmt.(*ast.SelectStmt)
+				assertEqual(t, true, s.Distinct)
+				assertTexts(t, []string{"a", "count ( * ) as total"}, s.Columns)
+				assertEqual(t, "total", s.Columns[1].(*ast.AliasedExpr).Alias)
+				assertEqual(t, "u", s.From[0].Alias)
+				assertEqual(t, "LEFT JOIN", s.Joins[0].Type)
+				assertEqual(t, "u.id = t2.id and t2.x > 1", ast.Text(s.Joins[0].On))
+				assertTexts(t, []string{"a = 1", "( b = 2 or c in ( select id from t3 ) )"}, ast.Conditions(s.Where))
+				assertTexts(t, []string{"a"}, s.GroupBy)
+				assertTexts(t, []string{"a desc"}, s.OrderBy)
+				assertEqual(t, "10", ast.Text(s.Limit))
+				assertEqual(t, "5", ast.Text(s.Offset))
+				assertTables(t, []string{"t1", "t2", "t3"}, stmt)
+			},
+		},
+		{
+			name: "SELECT statement with common table expressions and UNION",
+			sql:  `with x as (select * from a), y(c) as (select 1) select * from x union all select c from y`,
+			check: func(t *testing.T, stmt ast.Statement) {
+				s := stmt.(*ast.SelectStmt)
+				assertEqual(t, 2, len(s.With.CTEs))
+				assertEqual(t, "y", s.With.CTEs[1].Name)
+				assertTexts(t, []string{"c"}, s.With.CTEs[1].Columns)
+				assertEqual(t, "UNION ALL", s.SetOp.Op)
+				assertTexts(t, []string{"c"}, s.SetOp.Select.Columns)
+				assertTables(t, []string{"a"}, stmt)
+			},
+		},
+		{
+			name: "SELECT statement with recursive common table expression",
+			sql:  `with recursive r as (select 1 union all select n from r) select * from r, u where a in (select b from r)`,
+			check: func(t *testing.T, stmt ast.Statement) {
+				assertTables(t, []string{"u"}, stmt)
+			},
+		},
+		{
+			name: "SELECT statement with common table expression named like its table",
+			sql:  `with t as (select * from t where a = 1) select * from t`,
+			check: func(t *testing.T, stmt ast.Statement) {
+				assertTables(t, []string{"t"}, stmt)
+			},
+		},
+		{
+			name: "SELECT statement with window functions",
+			sql:  `select a, row_number() over (partition by a order by b) as rn from t where x = 1 window w as (order by a) order by a`,
+			check: func(t *testing.T, stmt ast.Statement) {
+				s := stmt.(*ast.SelectStmt)
+				assertEqual(t, "rn", s.Columns[1].(*ast.AliasedExpr).Alias)
+				assertEqual(t, "x = 1", ast.Text(s.Where))
+				assertTexts(t, []string{"a"}, s.OrderBy)
+			},
+		},
+		{
+			name: "INSERT statement with values",
+			sql:  `insert into t (a, b) values (1, 'x'), ($1, :p) returning id`,
+			check: func(t *testing.T, stmt ast.Statement) {
+				s := stmt.(*ast.InsertStmt)
+				assertEqual(t, "t", ast.Text(s.Table))
+				assertTexts(t, []string{"a", "b"}, s.Columns)
+				assertEqual(t, 2, len(s.Values))
+				assertTexts(t, []string{"$1", ":p"}, s.Values[1])
+				assertTexts(t, []string{"id"}, s.Returning)
+			},
+		},
+		{
+			name: "INSERT statement with SELECT",
+			sql:  `insert into t(a) select a from u where b = 2`,
+			check: func(t *testing.T, stmt ast.Statement) {
+				s := stmt.(*ast.InsertStmt)
+				assertTexts(t, []string{"a"}, s.Columns)
+				assertEqual(t, "b = 2", ast.Text(s.Select.Where))
+				assertTables(t, []string{"t", "u"}, stmt)
+			},
+		},
+		{
+			name: "INSERT statement with ON CONFLICT",
+			sql:  `insert into t (id, a) select id, a from u where b = 2 on conflict (id) do update set a = excluded.a where t.c = 1 returning id`,
+			check: func(t *testing.T, stmt ast.Statement) {
+				s := stmt.(*ast.InsertStmt)
+				assertEqual(t, "b = 2", ast.Text(s.Select.Where))
+				assertTexts(t, []string{"id"}, s.Conflict.Target)
+				assertEqual(t, false, s.Conflict.DoNothing)
+				assertEqual(t, 1, len(s.Conflict.Set))
+				assertEqual(t, "excluded.a", ast.Text(s.Conflict.Set[0].Value))
+				assertEqual(t, "t.c = 1", ast.Text(s.Conflict.Where))
+				assertTexts(t, []string{"id"}, s.Returning)
+			},
+		},
+		{
+			name: "INSERT statement with ON DUPLICATE KEY UPDATE",
+			sql:  `insert into t (a, b) values (1, 2) on duplicate key update a = values(a), b = 2`,
+			check: func(t *testing.T, stmt ast.Statement) {
+				s := stmt.(*ast.InsertStmt)
+				assertEqual(t, 1, len(s.Values))
+				assertEqual(t, 2, len(s.Conflict.Set))
+				assertEqual(t, "b", ast.Text(s.Conflict.Set[1].Column))
+			},
+		},
+		{
+			name: "UPDATE statement",
+			sql:  `update t set a = 1, b = b + 1 from u where t.id = u.id returning *`,
+			check: func(t *testing.T, stmt ast.Statement) {
+				s := stmt.(*ast.UpdateStmt)
+				assertEqual(t, 2, len(s.Set))
+				assertEqual(t, "b", ast.Text(s.Set[1].Column))
+				assertEqual(t, "b + 1", ast.Text(s.Set[1].Value))
+				assertEqual(t, "t.id = u.id", ast.Text(s.Where))
+				assertTables(t, []string{"t", "u"}, stmt)
+			},
+		},
+		{
+			name: "DELETE statement",
+			sql:  `delete from t where a = 1 and not exists (select 1 from v)`,
+			check: func(t *testing.T, stmt ast.Statement) {
+				s := stmt.(*ast.DeleteStmt)
+				assertEqual(t, "t", ast.Text(s.Table))
+				assertEqual(t, "NOT", ast.Conditions(s.Where)[1].(*ast.UnaryExpr).Op)
+				assertTables(t, []string{"t", "v"}, stmt)
+			},
+		},
+		{
+			name: "Other statement",
+			sql:  `create table x (a int)`,
+			check: func(t *testing.T, stmt ast.Statement) {
+				assertEqual(t, "create table x ( a int )", ast.Text(stmt.(*ast.GenericStmt)))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tokens, errTokenize := lexer.Tokenize(tt.sql)
+			if errTokenize != nil {
+				t.Fatalf("%v", errTokenize)
+			}
+			stmt, errParse := ParseStatement(tokens)
+			if errParse != nil {
+				t.Fatalf("%v", errParse)
+			}
+			tt.check(t, stmt)
+		})
+	}
+}
+
+func assertEqual(t *testing.T, want interface{}, got interface{}) {
+	t.Helper()
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("want %v, got %v", want, got)
+	}
+}
+
+func assertTexts(t *testing.T, want []string, exprs []ast.Expr) {
+	t.Helper()
+	var got []string
+	for _, expr := range exprs {
+		got = append(got, ast.Text(expr))
+	}
+	assertEqual(t, want, got)
+}
+
+func assertTables(t *testing.T, want []string, node ast.Node) {
+	t.Helper()
+	var got []string
+	for _, table := range ast.Tables(node) {
+		got = append(got, table.Name)
+	}
+	assertEqual(t, want, got)
+}
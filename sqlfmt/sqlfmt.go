@@ -4,51 +4,425 @@ import (
 	"bufio"
 	"bytes"
 	"fmt"
+	"github.com/noneymous/go-sqlfmt/sqlfmt/ast"
 	"github.com/noneymous/go-sqlfmt/sqlfmt/formatters"
 	"github.com/noneymous/go-sqlfmt/sqlfmt/lexer"
 	"github.com/noneymous/go-sqlfmt/sqlfmt/parser"
+	"io"
 	"strings"
 )
 
-// Format parse tokens, and build
+// Warning describes a region of the SQL input, which could not be formatted in recovering mode and was written
+// verbatim instead
+type Warning struct {
+	Start   lexer.Position // Location of the first character of the region
+	End     lexer.Position // Location directly after the last character of the region
+	Text    string         // Original text of the region's tokens separated by single white-spaces
+	Message string         // Reason why the region could not be formatted
+}
+
+// String returns a human-readable representation of the warning
+func (w Warning) String() string {
+	return fmt.Sprintf("region at %s written verbatim: %s", w.Start, w.Message)
+}
+
+// Format parse tokens, and build. The SQL string may be a script comprised out of multiple statements
+// separated by semicolons. Each statement is formatted individually and joined again afterward.
 func Format(sql string, options *formatters.Options) (string, error) {
+	sqlFormatted, _, err := FormatWithWarnings(sql, options)
+	return sqlFormatted, err
+}
+
+// FormatWithWarnings formats an SQL string like Format, but additionally returns the regions, which could not be
+// formatted and were written verbatim. Regions are only written verbatim, if recovering mode is enabled by the
+// options, otherwise an error is returned for them.
+func FormatWithWarnings(sql string, options *formatters.Options) (string, []Warning, error) {
+
+	// Prepare reader, splitting tokens into individual statements
+	reader := newStatementReader(lexer.NewTokenizer(strings.NewReader(sql), tokenizerConfig(options)))
+
+	// Read and format each statement
+	var sb strings.Builder
+	var warnings []Warning
+	for i := 0; ; i++ {
+		stmtFormatted, stmt, stmtWarnings, ok, errNext := formatNext(reader, options)
+		if errNext != nil {
+			return "", nil, errNext
+		}
+		if !ok {
+			break
+		}
+
+		// Append formatted statement and remember regions written verbatim
+		sb.WriteString(joinStatement(stmtFormatted, stmt, i == 0, options))
+		warnings = append(warnings, stmtWarnings...)
+	}
+	sqlFormatted := sb.String()
+
+	// Safety check, compare if formatted query still has the same logic as input
+	if !CompareSemantic(sql, sqlFormatted) {
+		fmt.Println(sqlFormatted)
+		return "", nil, fmt.Errorf("formatted result does not match input semantically")
+	}
+
+	// Return successfully formatted SQL string
+	return sqlFormatted, warnings, nil
+}
+
+// FormatStream formats an SQL script like Format, but reads it from r and writes the result to w statement by
+// statement. Only a single statement is kept in memory at a time, so that large scripts, such as database
+// dumps, can be formatted with bounded memory. Statements written before an error occurred remain written.
+// Regions written verbatim in recovering mode are not reported, use FormatWithWarnings to retrieve them.
+func FormatStream(r io.Reader, w io.Writer, options *formatters.Options) error {
+
+	// Prepare reader, splitting tokens into individual statements
+	reader := newStatementReader(lexer.NewTokenizer(r, tokenizerConfig(options)))
+
+	// Read, format and write each statement
+	for i := 0; ; i++ {
+		stmtFormatted, stmt, _, ok, errNext := formatNext(reader, options)
+		if errNext != nil {
+			return errNext
+		}
+		if !ok {
+			return nil
+		}
+
+		// Safety check, compare if formatted statement still has the same logic as input
+		if !CompareSemantic(stmt.source(), stmtFormatted) {
+			return fmt.Errorf("formatted result does not match input semantically at %s", stmt.tokens[0].Start)
+		}
+
+		// Write formatted statement
+		if _, errWrite := io.WriteString(w, joinStatement(stmtFormatted, stmt, i == 0, options)); errWrite != nil {
+			return errWrite
+		}
+	}
+}
+
+// ParseStatements parses an SQL script into typed syntax trees, one for each statement, e.g. to analyze the
+// tables read by a SELECT statement. Empty statements, e.g. a semicolon without preceding statement, and
+// statements only comprised out of comments are skipped.
+func ParseStatements(sql string, options *formatters.Options) ([]ast.Statement, error) {
+
+	// Prepare reader, splitting tokens into individual statements
+	reader := newStatementReader(lexer.NewTokenizer(strings.NewReader(sql), tokenizerConfig(options)))
 
-	// Tokenize SQL query string
-	tokens, errTokenize := lexer.Tokenize(sql)
-	if errTokenize != nil {
-		return "", fmt.Errorf("tokenization error: %w", errTokenize)
+	// Read and parse each statement
+	var stmts []ast.Statement
+	for {
+		stmt, ok, errRead := reader.read()
+		if errRead != nil {
+			return nil, fmt.Errorf("tokenization error: %w", errRead)
+		}
+		if !ok {
+			return stmts, nil
+		}
+
+		// Skip data of COPY ... FROM STDIN, leading comments and empty statements
+		if stmt.data {
+			continue
+		}
+		tokens := stmt.tokens
+		for len(tokens) > 1 && tokens[0].Type == lexer.COMMENT {
+			tokens = tokens[1:]
+		}
+		if len(tokens) == 1 {
+			continue
+		}
+
+		// Parse statement
+		stmtParsed, errParse := parser.ParseStatement(tokens)
+		if errParse != nil {
+			return nil, fmt.Errorf("parse error: %w", errParse)
+		}
+		stmts = append(stmts, stmtParsed)
+	}
+}
+
+// tokenizerConfig returns the tokenizer configuration according to the formatting options
+func tokenizerConfig(options *formatters.Options) lexer.Config {
+	return lexer.Config{
+		Dialect:                 options.Dialect,
+		DisableFunctionKeywords: options.DisableFunctionKeywords,
+		Registry:                options.Registry,
+	}
+}
+
+// formatNext reads the next statement and formats it, including its terminator and trailing comments. Returns
+// false if there are no statements left. In recovering mode, regions of the statement, which could not be
+// formatted, are written verbatim and returned as warnings.
+func formatNext(reader *statementReader, options *formatters.Options) (string, statement, []Warning, bool, error) {
+
+	// Read next statement
+	stmt, ok, errRead := reader.read()
+	if errRead != nil {
+		return "", statement{}, nil, false, fmt.Errorf("tokenization error: %w", errRead)
+	}
+	if !ok {
+		return "", statement{}, nil, false, nil
+	}
+
+	// Return data of COPY ... FROM STDIN verbatim
+	if stmt.data {
+		return stmt.tokens[0].Raw, stmt, nil, true, nil
+	}
+
+	// Format statement, unless it is empty, e.g. a semicolon without preceding statement
+	var stmtFormatted string
+	var warnings []Warning
+	if len(stmt.tokens) > 1 || !stmt.terminated {
+		var errFormat error
+		stmtFormatted, warnings, errFormat = formatStatement(stmt.tokens, options)
+
+		// Write whole statement verbatim in recovering mode, if it could not be formatted or its logic changed
+		if options.Recover && errFormat == nil && !CompareSemantic(statement{tokens: stmt.tokens}.source(), stmtFormatted) {
+			errFormat = fmt.Errorf("formatted result does not match input semantically")
+		}
+		if options.Recover && errFormat != nil {
+			stmtFormatted, warnings = formatVerbatim(stmt.tokens[:len(stmt.tokens)-1], errFormat, options)
+			errFormat = nil
+		}
+		if errFormat != nil {
+			return "", statement{}, nil, false, errFormat
+		}
+	}
+
+	// Append terminator again, if statement was terminated, followed by comments on the same line
+	if stmt.terminated {
+		stmtFormatted += ";"
+	}
+	for _, comment := range stmt.trailing {
+		stmtFormatted += options.Whitespace + comment.Value
+	}
+
+	// Return formatted statement
+	return stmtFormatted, stmt, warnings, true, nil
+}
+
+// joinStatement prepares a formatted statement to be appended to the preceding ones. Statements are separated by
+// an empty line and padded with left spacing if desired, the empty line is padded too. Data of COPY ... FROM
+// STDIN directly follows its statement on the next line and is neither separated nor padded.
+func joinStatement(stmtFormatted string, stmt statement, first bool, options *formatters.Options) string {
+	if stmt.data {
+		return options.Newline + stmtFormatted
+	}
+	if !first {
+		stmtFormatted = options.Newline + stmtFormatted
+	}
+	if options.Padding != "" {
+		stmtFormatted = addPadding(stmtFormatted, options.Padding)
+	}
+	if !first {
+		stmtFormatted = options.Newline + stmtFormatted
+	}
+	return stmtFormatted
+}
+
+// statement is a sequence of tokens representing a single statement of an SQL script
+type statement struct {
+	tokens     []lexer.Token // Tokens of the statement, always terminated by an EOF token
+	terminated bool          // Whether the statement was terminated by a semicolon
+	trailing   []lexer.Token // Comments following the semicolon on the same line
+	data       bool          // Whether the statement is data of COPY ... FROM STDIN, which is kept verbatim
+}
+
+// source returns the original text of the statement. Tokens are separated by line breaks, which do not change
+// the semantic, but terminate single-line comments.
+func (s statement) source() string {
+	var parts []string
+	for _, token := range s.tokens {
+		parts = append(parts, token.Raw)
+	}
+	if s.terminated {
+		parts = append(parts, ";")
+	}
+	for _, comment := range s.trailing {
+		parts = append(parts, comment.Raw)
+	}
+	return strings.Join(parts, "\n")
+}
+
+// statementReader splits a sequence of tokens into individual statements at semicolons. Semicolons within
+// parentheses do not terminate a statement. Comments following a semicolon on the same line belong to the
+// terminated statement, any other comments belong to the next statement. Data of COPY ... FROM STDIN following a
+// statement is returned as a statement of its own.
+type statementReader struct {
+	tokenizer *lexer.Tokenizer
+	pending   *lexer.Token // Token read ahead while looking for comments following a semicolon
+	count     int          // Number of statements read so far
+	done      bool         // Whether the end of the input was reached
+}
+
+// newStatementReader creates a statement reader consuming tokens of the given tokenizer
+func newStatementReader(tokenizer *lexer.Tokenizer) *statementReader {
+	return &statementReader{tokenizer: tokenizer}
+}
+
+// read returns the next statement. Returns false if there are no statements left.
+func (s *statementReader) read() (statement, bool, error) {
+
+	// Return if there is nothing left
+	if s.done {
+		return statement{}, false, nil
+	}
+
+	// Prepare process variables
+	var current []lexer.Token
+	var depth int
+
+	// Iterate tokens until the current statement is terminated
+	for {
+
+		// Get next token
+		token, err := s.next()
+		if err != nil {
+			return statement{}, false, err
+		}
+
+		// Decide whether token terminates the current statement
+		switch {
+		case token.Type == lexer.STARTPARENTHESIS:
+			depth++
+		case token.Type == lexer.ENDPARENTHESIS && depth > 0:
+			depth--
+		case token.Type == lexer.SEMICOLON && depth == 0:
+			current = append(current, lexer.Token{Type: lexer.EOF, Value: "EOF", Start: token.Start, End: token.End})
+			stmt := statement{tokens: current, terminated: true}
+			if errTrailing := s.readTrailing(&stmt); errTrailing != nil {
+				return statement{}, false, errTrailing
+			}
+			s.count++
+			return stmt, true, nil
+		case token.Type == lexer.DATA && len(current) == 0:
+			current = append(current, token, lexer.Token{Type: lexer.EOF, Value: "EOF", Start: token.End, End: token.End})
+			s.count++
+			return statement{tokens: current, data: true}, true, nil
+		case token.Type == lexer.EOF:
+			s.done = true
+
+			// Return remaining statement, unless there is nothing left after the last terminator
+			if len(current) > 0 || s.count == 0 {
+				s.count++
+				return statement{tokens: append(current, token)}, true, nil
+			}
+			return statement{}, false, nil
+		}
+		current = append(current, token)
+	}
+}
+
+// readTrailing attaches comments following the semicolon on the same line to the terminated statement. The
+// first token not belonging to it is kept for the next statement.
+func (s *statementReader) readTrailing(stmt *statement) error {
+	lastToken := stmt.tokens[len(stmt.tokens)-1]
+	for {
+
+		// Get next token
+		token, err := s.next()
+		if err != nil {
+			return err
+		}
+
+		// Attach comment to terminated statement, if it follows on the same line as the semicolon
+		if token.Type == lexer.COMMENT && lastToken.End.Line == token.Start.Line && !isLineComment(lastToken) {
+			stmt.trailing = append(stmt.trailing, token)
+			lastToken = token
+			continue
+		}
+
+		// Keep token for the next statement
+		s.pending = &token
+		return nil
+	}
+}
+
+// next returns the token read ahead, if there is one, or reads the next one
+func (s *statementReader) next() (lexer.Token, error) {
+	if s.pending != nil {
+		token := *s.pending
+		s.pending = nil
+		return token, nil
+	}
+	return s.tokenizer.Next()
+}
+
+// formatStatement parses the tokens of a single statement and formats them into a prettified and uniformly
+// formatted SQL string. In recovering mode, regions written verbatim are returned as warnings.
+func formatStatement(tokens []lexer.Token, options *formatters.Options) (string, []Warning, error) {
+
+	// Put leading comments on lines of their own, the statement itself starts on a new line after them
+	var lines []string
+	for len(tokens) > 1 && tokens[0].Type == lexer.COMMENT {
+		lines = append(lines, tokens[0].Value)
+		tokens = tokens[1:]
+	}
+	if len(tokens) == 1 {
+		return strings.Join(lines, options.Newline), nil, nil
 	}
 
 	// Parse tokens and group them into a sequence of query segments
 	tokensParsed, errParse := parser.Parse(tokens, options)
 	if errParse != nil {
-		return "", fmt.Errorf("parse error: %w", errParse)
+		return "", nil, fmt.Errorf("parse error: %w", errParse)
 	}
 
 	// Format parsed tokens into prettified and uniformly formatted SQL string
 	var buf bytes.Buffer
+	var warnings []Warning
 	for i, tokenParsed := range tokensParsed {
 		if err := tokenParsed.Format(&buf, tokensParsed, i); err != nil {
-			return "", err
+			return "", nil, err
+		}
+
+		// Remember regions, which were written verbatim
+		if verbatim, ok := tokenParsed.(*formatters.Verbatim); ok {
+			warnings = append(warnings, newWarning(verbatim.Elements, verbatim.Err))
 		}
 	}
 
-	// Get formatted SQL string
-	sqlFormatted := strings.Trim(buf.String(), "\n")
+	// Return formatted SQL string
+	lines = append(lines, strings.Trim(buf.String(), "\n"))
+	return strings.Join(lines, options.Newline), warnings, nil
+}
+
+// formatVerbatim writes the tokens of a statement as they are, because it could not be formatted for the given
+// reason. The statement is returned along with the according warning.
+func formatVerbatim(tokens []lexer.Token, err error, options *formatters.Options) (string, []Warning) {
 
-	// Add left spacing if desired
-	if options.Padding != "" {
-		sqlFormatted = addPadding(sqlFormatted, options.Padding)
+	// Prepare verbatim Formatter
+	verbatim := &formatters.Verbatim{Options: options, Err: err}
+	for _, token := range tokens {
+		verbatim.Elements = append(verbatim.Elements, formatters.Token{Options: options, Token: token})
 	}
 
-	// Safety check, compare if formatted query still has the same logic as input
-	if !CompareSemantic(sql, sqlFormatted) {
-		fmt.Println(sqlFormatted)
-		return "", fmt.Errorf("formatted result does not match input semantically")
+	// Write tokens and return them along with the warning
+	var buf bytes.Buffer
+	_ = verbatim.Format(&buf, nil, 0)
+	return strings.Trim(buf.String(), "\n"), []Warning{newWarning(verbatim.Elements, err)}
+}
+
+// newWarning creates a warning for a region written verbatim
+func newWarning(elements []formatters.Formatter, err error) Warning {
+	var texts []string
+	var warning Warning
+	for _, el := range elements {
+		if token, ok := el.(formatters.Token); ok {
+			if len(texts) == 0 {
+				warning.Start = token.Start
+			}
+			warning.End = token.End
+			texts = append(texts, token.Raw)
+		}
 	}
+	warning.Text = strings.Join(texts, " ")
+	warning.Message = err.Error()
+	return warning
+}
 
-	// Return successfully formatted SQL string
-	return sqlFormatted, nil
+// isLineComment returns true if token is a single-line comment (-- or //), which must be followed by a newline
+func isLineComment(token lexer.Token) bool {
+	return token.Type == lexer.COMMENT && !strings.HasPrefix(token.Value, "/*")
 }
 
 // CompareSemantic compares a formatted SQL string with the original input and checks whether they are
@@ -90,20 +464,31 @@ func addPadding(s string, leftPadding string) string {
 // e.g. by putting a part of the SQL query at the end of a one-line comment where it would be ignored.
 func removeComments(str string) string {
 	var strNew string
-	var quoted bool
+	var quote rune   // Currently open quote character, either single quote (string) or double quote (identifier)
+	var escapes bool // Whether the open quote is an escape string (E'...') supporting backslash escapes
+	var escaped bool // Whether the current character is escaped by a preceding backslash
 	var skip bool
 	for i, c := range str {
 
-		// Don't remove quoted strings, even if they might contain comment indicators
-		if !quoted && c == '\'' {
-			quoted = true
+		// Don't remove quoted strings or identifiers, even if they might contain comment indicators
+		if quote == 0 && !skip && (c == '\'' || c == '"') {
+			quote = c
+			escapes = c == '\'' && i > 0 && (str[i-1] == 'E' || str[i-1] == 'e')
+			strNew += string(c)
+			continue
+		} else if quote != 0 && escaped {
+			escaped = false
+			strNew += string(c)
+			continue
+		} else if quote != 0 && escapes && c == '\\' {
+			escaped = true
 			strNew += string(c)
 			continue
-		} else if quoted && c == '\'' {
-			quoted = false
+		} else if quote != 0 && c == quote {
+			quote = 0
 			strNew += string(c)
 			continue
-		} else if quoted {
+		} else if quote != 0 {
 			strNew += string(c)
 			continue
 		}
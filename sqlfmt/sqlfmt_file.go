@@ -2,7 +2,9 @@ package sqlfmt
 
 import (
 	"bytes"
+	"errors"
 	"github.com/noneymous/go-sqlfmt/sqlfmt/formatters"
+	"github.com/noneymous/go-sqlfmt/sqlfmt/lexer"
 	"go/ast"
 	"go/format"
 	"go/parser"
@@ -44,13 +46,23 @@ func FormatFile(filename string, src []byte, options *formatters.Options) ([]byt
 			}
 
 			// Format SQL string
-			sqlFormatted, errFormat := Format(sql, options)
+			sqlFormatted, warnings, errFormat := FormatWithWarnings(sql, options)
 			if errFormat != nil {
 
-				// Log invalid queries for debugging
+				// Log invalid queries for debugging, pointing at the offending character if it is known
 				log.Println(errFormat)
-				log.Println(strings.Trim(strings.Trim(sql, "\n"), " "))
+				var errLexer *lexer.Error
+				if errors.As(errFormat, &errLexer) {
+					log.Println("\n" + errLexer.Snippet)
+				} else {
+					log.Println(strings.Trim(strings.Trim(sql, "\n"), " "))
+				}
 			} else {
+
+				// Log regions, which were written verbatim in recovering mode
+				for _, warning := range warnings {
+					log.Println(warning)
+				}
 				astReplace(n, quoteChar+sqlFormatted+quoteChar)
 			}
 		}
@@ -1,10 +1,16 @@
 package sqlfmt
 
 import (
+	"bytes"
 	"fmt"
+	"reflect"
+	"strings"
+	"sync"
 	"testing"
 
+	"github.com/noneymous/go-sqlfmt/sqlfmt/ast"
 	"github.com/noneymous/go-sqlfmt/sqlfmt/formatters"
+	"github.com/noneymous/go-sqlfmt/sqlfmt/lexer"
 )
 
 func TestFormat(t *testing.T) {
@@ -191,8 +197,7 @@ FROM cte_quantity;`,
 		{
 			name: "Fragment FROM",
 			sql:  `from "table where a=1"`,
-			want: `FROM "table
-WHERE a = 1"`,
+			want: `FROM "table where a=1"`, // Double-quoted identifier is kept as a whole
 		},
 		{
 			name: "Fragment WHERE",
@@ -1634,6 +1639,901 @@ SELECT
 FROM archived_hosts`,
 		},
 
+		/*
+		 * Double-quoted identifiers
+		 */
+		{
+			name: "Quoted identifiers named like keywords",
+			sql:  `select "user".id, "Order Details"."Unit Price" as "select", public."user" from "user" join public."Order Details" on "user".id = "Order Details".user_id where "a""b" = 1`,
+			want: `SELECT
+  "user".id,
+  "Order Details"."Unit Price" AS "select",
+  public."user"
+FROM "user"
+JOIN public."Order Details" ON "user".id = "Order Details".user_id
+WHERE "a""b" = 1`,
+		},
+		{
+			name: "Quoted identifiers in update",
+			sql:  `update "user" set "Name" = 'x', "Group" = 2, c = 3 returning "Name", "Group", id`,
+			want: `UPDATE "user"
+SET
+  "Name" = 'x',
+  "Group" = 2,
+  c = 3
+RETURNING
+  "Name",
+  "Group",
+  id`,
+		},
+
+		/*
+		 * Dollar-quoted strings
+		 */
+		{
+			name: "Dollar-quoted string in select",
+			sql:  `select $1, $tag$it's $$ NOT upper-cased$tag$ from t where a = $2`,
+			want: `SELECT
+  $1,
+  $tag$it's $$ NOT upper-cased$tag$
+FROM t
+WHERE a = $2`,
+		},
+		{
+			name: "Do block with dollar-quoted body",
+			sql: `do $body$
+begin
+  raise notice 'select from where';
+end
+$body$`,
+			want: `DO $body$
+BEGIN
+  RAISE NOTICE 'select from where';
+END
+$body$`,
+		},
+		{
+			name: "Function with dollar-quoted body of unsupported language",
+			sql:  `create function f() returns int language plpython3u as $$ return 1  # select from where $$`,
+			want: `CREATE FUNCTION f()
+RETURNS INT
+LANGUAGE plpython3u
+AS $$ return 1  # select from where $$`,
+		},
+
+		/*
+		 * String literals
+		 */
+		{
+			name: "String literals with escapes and prefixes",
+			sql:  `select 'it''s', E'it\'s -- not a comment', N'national', X'ff', b'1010', U&'d\0061t' from t where a = 'x--y'`,
+			want: `SELECT
+  'it''s',
+  E'it\'s -- not a comment',
+  N'national',
+  X'ff',
+  b'1010',
+  U&'d\0061t'
+FROM t
+WHERE a = 'x--y'`,
+		},
+
+		/*
+		 * Operators
+		 */
+		{
+			name: "Arithmetic, concatenation and JSON operators",
+			sql:  `select a+b, price*qty/2, -1, t.*, count(*), first_name||' '||last_name as name, data->>'name', data->'a'->>'b', data#>>'{a,b}' from t where tags @> array['x'] and x = -1 and y<->z < 5 and a<=>b`,
+			want: `SELECT
+  a + b,
+  price * qty / 2,
+  -1,
+  t.*,
+  COUNT(*),
+  first_name || ' ' || last_name AS name,
+  data ->> 'name',
+  data -> 'a' ->> 'b',
+  data #>> '{a,b}'
+FROM t
+WHERE
+  tags @> ARRAY ['x']
+  AND x = -1
+  AND y <-> z < 5
+  AND a <=> b`,
+		},
+		{
+			name: "Unary signs and regular expression operators",
+			sql:  `select -y, a -b, a * -b, a / -b, -(a + b) from t where b = -b and c ~* 'z' and c !~* 'z' and c ~ 'z'`,
+			want: `SELECT
+  -y,
+  a - b,
+  a * -b,
+  a / -b,
+  - (a + b)
+FROM t
+WHERE
+  b = -b
+  AND c ~* 'z'
+  AND c !~* 'z'
+  AND c ~ 'z'`,
+		},
+
+		/*
+		 * Scripts with multiple statements
+		 */
+		{
+			name: "Script with multiple statements and comments",
+			sql: `-- migration
+create table a (id int);
+insert into a values (1); -- trailing comment
+/* block */ select * from a where id = 1;
+update a set id = 2`,
+			want: `-- migration
+CREATE TABLE a ( id INT);
+
+INSERT INTO a
+VALUES
+  (1); -- trailing comment
+
+/* block */
+SELECT
+  *
+FROM a
+WHERE id = 1;
+
+UPDATE a
+SET id = 2`,
+		},
+		{
+			name: "Script with empty statement and trailing comment",
+			sql:  `select 1;; -- done`,
+			want: `SELECT
+  1;
+
+; -- done`,
+		},
+
+		/*
+		 * Numbers
+		 */
+		{
+			name: "Numeric literals",
+			sql:  `select 1.5e-3*price, .5, 0x1F, 1_000_000 from t where a = -1 and b=1e10 and c > +2.5 limit 10 offset 20`,
+			want: `SELECT
+  1.5e-3 * price,
+  .5,
+  0x1F,
+  1_000_000
+FROM t
+WHERE
+  a = -1
+  AND b = 1e10
+  AND c > +2.5
+LIMIT 10
+OFFSET 20`,
+		},
+
+		/*
+		 * Placeholders
+		 */
+		{
+			name: "Bind parameter placeholders of different styles",
+			sql:  `select a, b from t where a = $1 and b = ? and c=:name and d = @p1 and e = :user.id and data ? 'key'`,
+			want: `SELECT
+  a,
+  b
+FROM t
+WHERE
+  a = $1
+  AND b = ?
+  AND c = :name
+  AND d = @p1
+  AND e = :user.id
+  AND data ? 'key'`,
+		},
+		{
+			name: "Placeholders in UPDATE statement",
+			sql:  `update t set a = :a, b = :b, c = :c where id = :id`,
+			want: `UPDATE t
+SET
+  a = :a,
+  b = :b,
+  c = :c
+WHERE id = :id`,
+		},
+
+		/*
+		 * Comments and optimizer hints
+		 */
+		{
+			name: "Optimizer hints stay inline after SELECT",
+			sql:  `select /*+ INDEX(t idx) */ a, b from t`,
+			want: `SELECT /*+ INDEX(t idx) */
+  a,
+  b
+FROM t`,
+		},
+		{
+			name: "Optimizer hints followed by DISTINCT and parenthesis",
+			sql:  `select /*+ x */ distinct a, b from (select /*+ y */ (a + b) from u) t`,
+			want: `SELECT /*+ x */ DISTINCT a,
+  b
+FROM (
+  SELECT /*+ y */ (a + b)
+  FROM u
+) t`,
+		},
+		{
+			name: "Optimizer hints in UPDATE and DELETE statements",
+			sql:  `update /*+ INDEX(t idx) */ t set a = 1 where b = 2; delete /*+ FULL(t) */ from t where a = 1`,
+			want: `UPDATE /*+ INDEX(t idx) */ t
+SET a = 1
+WHERE b = 2;
+
+DELETE /*+ FULL(t) */
+FROM t
+WHERE a = 1`,
+		},
+		{
+			name: "Nested block comment",
+			sql:  `select a /* outer /* inner */ still comment */ from t`,
+			want: `SELECT
+  a /* outer /* inner */ still comment */
+FROM t`,
+		},
+
+		/*
+		 * MERGE statements
+		 */
+		{
+			name: "MERGE with all branch actions",
+			sql:  `merge into customers c using staging s on c.id = s.id and c.region = s.region when matched and s.deleted then delete when matched then update set name = s.name, email = lower(s.email) when not matched then insert (id, name, email) values (s.id, s.name, s.email)`,
+			want: `MERGE INTO customers c
+USING staging s
+ON c.id = s.id AND c.region = s.region
+WHEN MATCHED AND s.deleted THEN
+  DELETE
+WHEN MATCHED THEN
+  UPDATE SET
+    name = s.name,
+    email = LOWER(s.email)
+WHEN NOT MATCHED THEN
+  INSERT (id, name, email)
+  VALUES (s.id, s.name, s.email)`,
+		},
+		{
+			name: "MERGE with subquery source",
+			sql:  `merge into t using (select id, a from u where b = 1) s on (t.id = s.id) when not matched by source then delete when not matched then insert values (s.id, s.a)`,
+			want: `MERGE INTO t
+USING (
+  SELECT
+    id,
+    a
+  FROM u
+  WHERE b = 1
+) s
+ON (t.id = s.id)
+WHEN NOT MATCHED BY source THEN
+  DELETE
+WHEN NOT MATCHED THEN
+  INSERT VALUES (s.id, s.a)`,
+		},
+		{
+			name: "MERGE with common table expression",
+			sql:  `with x as (select 1 as id) merge into t using x on t.id = x.id when matched then do nothing`,
+			want: `WITH x AS (
+  SELECT
+    1 AS id
+)
+MERGE INTO t
+USING x
+ON t.id = x.id
+WHEN MATCHED THEN
+  DO NOTHING`,
+		},
+		{
+			name: "MERGE with Oracle conditions",
+			sql:  `merge into t using s on (t.id = s.id) when matched then update set t.a = s.a where s.b > 0 delete where s.c = 1 when not matched then insert (t.id) values (s.id) where s.d = 2`,
+			want: `MERGE INTO t
+USING s
+ON (t.id = s.id)
+WHEN MATCHED THEN
+  UPDATE SET
+    t.a = s.a
+  WHERE s.b > 0
+  DELETE WHERE s.c = 1
+WHEN NOT MATCHED THEN
+  INSERT (t.id)
+  VALUES (s.id)
+  WHERE s.d = 2`,
+		},
+
+		/*
+		 * INSERT with conflict clauses
+		 */
+		{
+			name: "INSERT with ON CONFLICT DO UPDATE",
+			sql:  `insert into t (id, a, b) values (1, 'x', 2) on conflict (id) do update set a = excluded.a, b = excluded.b, c = excluded.c where t.d = 1 returning id`,
+			want: `INSERT INTO t
+  (id, a, b)
+VALUES
+  (1, 'x', 2)
+ON CONFLICT (id) DO UPDATE SET
+  a = excluded.a,
+  b = excluded.b,
+  c = excluded.c
+WHERE t.d = 1
+RETURNING id`,
+		},
+		{
+			name: "INSERT with SELECT and ON CONFLICT DO NOTHING",
+			sql:  `insert into t (id) select id from u where x = 1 on conflict do nothing`,
+			want: `INSERT INTO t
+  (id)
+SELECT
+  id
+FROM u
+WHERE x = 1
+ON CONFLICT DO NOTHING`,
+		},
+		{
+			name: "INSERT with ON CONFLICT ON CONSTRAINT",
+			sql:  `insert into t (id, a) values (1, 2) on conflict on constraint t_pkey do update set a = excluded.a`,
+			want: `INSERT INTO t
+  (id, a)
+VALUES
+  (1, 2)
+ON CONFLICT ON constraint t_pkey DO UPDATE SET a = excluded.a`,
+		},
+		{
+			name: "INSERT with ON CONFLICT DO UPDATE and comment",
+			sql: `insert into t (a, b) values (1, 2) on conflict (id) do update set a = excluded.a, -- keep b
+b = excluded.b`,
+			want: `INSERT INTO t
+  (a, b)
+VALUES
+  (1, 2)
+ON CONFLICT (id) DO UPDATE SET
+  a = excluded.a, -- keep b
+  b = excluded.b`,
+		},
+		{
+			name: "INSERT with ON DUPLICATE KEY UPDATE",
+			sql:  `insert into t (a, b) values (1, 2) on duplicate key update a = values(a), b = 2, c = 3, d = 4`,
+			want: `INSERT INTO t
+  (a, b)
+VALUES
+  (1, 2)
+ON DUPLICATE KEY UPDATE
+  a = VALUES (a),
+  b = 2,
+  c = 3,
+  d = 4`,
+		},
+
+		/*
+		 * Window functions
+		 */
+		{
+			name: "Window functions with frame and WINDOW clause",
+			sql:  `select a, row_number() over (partition by a order by b rows between unbounded preceding and current row) as rn, sum(c) over w from t where x = 1 window w as (partition by a order by b) order by a`,
+			want: `SELECT
+  a,
+  ROW_NUMBER() OVER (
+    PARTITION BY a
+    ORDER BY b
+    ROWS BETWEEN UNBOUNDED PRECEDING AND CURRENT ROW
+  ) AS rn,
+  SUM(c) OVER w
+FROM t
+WHERE x = 1
+WINDOW w AS (
+  PARTITION BY a
+  ORDER BY b
+)
+ORDER BY a`,
+		},
+		{
+			name: "Window functions with single part specifications",
+			sql:  `select count(*) over (), sum(a) over (partition by b) from t`,
+			want: `SELECT
+  COUNT(*) OVER (),
+  SUM(a) OVER (PARTITION BY b)
+FROM t`,
+		},
+		{
+			name: "Window functions with filter and frame exclusion",
+			sql:  `select sum(x) filter (where y > 0) over (order by b desc range between interval '1 day' preceding and current row exclude current row) from t`,
+			want: `SELECT
+  SUM(x) FILTER (
+    WHERE y > 0
+  ) OVER (
+    ORDER BY b DESC
+    RANGE BETWEEN INTERVAL '1 day' PRECEDING AND CURRENT ROW EXCLUDE CURRENT ROW
+  )
+FROM t`,
+		},
+		{
+			name: "Window functions with several named windows",
+			sql:  `select lag(b, 1) over w1, lead(b) over w2 from t group by a, b having count(*) > 1 window w1 as (partition by a), w2 as (w1 order by ts rows between 1 preceding and 1 following exclude no others)`,
+			want: `SELECT
+  LAG(b, 1) OVER w1,
+  LEAD(b) OVER w2
+FROM t
+GROUP BY a, b
+HAVING COUNT(*) > 1
+WINDOW
+  w1 AS (PARTITION BY a),
+  w2 AS (
+    w1
+    ORDER BY ts
+    ROWS BETWEEN 1 PRECEDING AND 1 FOLLOWING EXCLUDE NO OTHERS
+  )`,
+		},
+		{
+			name: "Window functions within subquery",
+			sql:  `select * from (select a, rank() over (partition by a order by b desc nulls last) r from t) x where r = 1`,
+			want: `SELECT
+  *
+FROM (
+  SELECT
+    a,
+    RANK() OVER (
+      PARTITION BY a
+      ORDER BY b DESC NULLS LAST
+    ) r
+  FROM t
+) x
+WHERE r = 1`,
+		},
+
+		{
+			name: "Window keywords as column names",
+			sql:  `select window, range, row, current, no, partition from t where range > 1 order by preceding`,
+			want: `SELECT
+  window,
+  range,
+  row,
+  current,
+  no,
+  partition
+FROM t
+WHERE range > 1
+ORDER BY preceding`,
+		},
+		{
+			name: "Window keywords as column names within window specification",
+			sql:  `select sum(a) over (partition by range order by row) from t`,
+			want: `SELECT
+  SUM(a) OVER (
+    PARTITION BY range
+    ORDER BY row
+  )
+FROM t`,
+		},
+
+		/*
+		 * CREATE VIEW and CREATE INDEX statements
+		 */
+		{
+			name: "CREATE OR REPLACE VIEW",
+			sql:  `create or replace view v as select a, b from t where c = 1`,
+			want: `CREATE OR REPLACE VIEW v AS
+SELECT
+  a,
+  b
+FROM t
+WHERE c = 1`,
+		},
+		{
+			name: "CREATE MATERIALIZED VIEW WITH NO DATA",
+			sql:  `create materialized view if not exists mv as select a, count(*) from t group by a with no data`,
+			want: `CREATE MATERIALIZED VIEW IF NOT EXISTS mv AS
+SELECT
+  a,
+  COUNT(*)
+FROM t
+GROUP BY a
+WITH NO DATA`,
+		},
+		{
+			name: "CREATE VIEW with options and common table expression",
+			sql:  `create view v (x, y) with (security_barrier) as with data as (select 1 a) select a, a from data with cascaded check option`,
+			want: `CREATE VIEW v (x, y) WITH (security_barrier) AS
+WITH data AS (
+  SELECT
+    1 a
+)
+SELECT
+  a,
+  a
+FROM data
+WITH CASCADED CHECK OPTION`,
+		},
+		{
+			name: "CREATE UNIQUE INDEX with options",
+			sql:  `create unique index concurrently if not exists idx_t_a on public.t using gin (a, lower(b)) include (c) with (fillfactor = 70) tablespace fast where d is not null`,
+			want: `CREATE UNIQUE INDEX CONCURRENTLY IF NOT EXISTS idx_t_a ON public.t USING gin (a, LOWER(b))
+INCLUDE (c)
+WITH (fillfactor = 70)
+TABLESPACE fast
+WHERE d IS NOT NULL`,
+		},
+		{
+			name: "CREATE INDEX on expressions",
+			sql:  `create index on t (lower(a), (b + c))`,
+			want: `CREATE INDEX ON t (LOWER(a), (b + c))`,
+		},
+		{
+			name: "CREATE partial INDEX",
+			sql:  `create index idx on t (a desc nulls last) where a > 1 and b < 2`,
+			want: `CREATE INDEX idx ON t (a DESC NULLS LAST)
+WHERE a > 1 AND b < 2`,
+		},
+
+		{
+			name: "CREATE keywords as column names",
+			sql:  `update t set index = 1, unique = 2 where view = 3 and replace is null`,
+			want: `UPDATE t
+SET
+  index = 1,
+  unique = 2
+WHERE view = 3 AND replace IS NULL`,
+		},
+		{
+			name: "CREATE keywords as column names within CREATE INDEX",
+			sql:  `create unique index idx on t (index) include (view)`,
+			want: `CREATE UNIQUE INDEX idx ON t (index)
+INCLUDE (view)`,
+		},
+
+		/*
+		 * CREATE FUNCTION, CREATE PROCEDURE and DO statements
+		 */
+		{
+			name: "CREATE FUNCTION with PL/pgSQL body",
+			sql:  `create or replace function f(a int, b text default 'x') returns table (x int, y text) language plpgsql stable security definer as $$ declare v int := 1; begin if a > 1 then return query select a, b; elsif a = 0 then null; else loop v := v + 1; exit when v > 10; end loop; end if; end; $$`,
+			want: `CREATE OR REPLACE FUNCTION f(a INT, b TEXT DEFAULT 'x')
+RETURNS TABLE (x INT, y TEXT)
+LANGUAGE plpgsql
+STABLE
+SECURITY DEFINER
+AS $$
+DECLARE
+  v INT := 1;
+BEGIN
+  IF a > 1 THEN
+    RETURN QUERY
+    SELECT
+      a,
+      b;
+  ELSIF a = 0 THEN
+    NULL;
+  ELSE
+    LOOP
+      v := v + 1;
+      EXIT WHEN v > 10;
+    END LOOP;
+  END IF;
+END;
+$$`,
+		},
+		{
+			name: "CREATE FUNCTION with loops, CASE statement and exception handlers",
+			sql:  `create function g(inout y int) returns setof record language plpgsql not leakproof set search_path = public as $f$ declare r t%rowtype; begin for r in select * from t loop raise notice 'row %', r.a; case r.a when 1 then perform h(1); else null; end case; end loop; begin update t set a = 1; exception when others then null; end; end $f$`,
+			want: `CREATE FUNCTION g(INOUT y INT)
+RETURNS SETOF record
+LANGUAGE plpgsql
+NOT LEAKPROOF
+SET search_path = public
+AS $f$
+DECLARE
+  r t%ROWTYPE;
+BEGIN
+  FOR r IN SELECT * FROM t LOOP
+    RAISE NOTICE 'row %', r.a;
+    CASE r.a
+      WHEN 1 THEN
+        PERFORM h(1);
+      ELSE
+        NULL;
+    END CASE;
+  END LOOP;
+  BEGIN
+    UPDATE t
+    SET a = 1;
+  EXCEPTION
+    WHEN OTHERS THEN
+      NULL;
+  END;
+END
+$f$`,
+		},
+		{
+			name: "CREATE PROCEDURE with SQL body",
+			sql:  `create procedure p(a int) language sql as $body$ insert into t values (a); delete from u where b = a; $body$`,
+			want: `CREATE PROCEDURE p(a INT)
+LANGUAGE sql
+AS $body$
+INSERT INTO t
+VALUES
+  (a);
+DELETE FROM u
+WHERE b = a;
+$body$`,
+		},
+		{
+			name: "CREATE FUNCTION with body of object file",
+			sql:  `create function h() returns int as 'obj.so', 'h' language c strict`,
+			want: `CREATE FUNCTION h()
+RETURNS INT
+AS 'obj.so', 'h'
+LANGUAGE c
+STRICT`,
+		},
+		{
+			name: "CREATE FUNCTION with invalid PL/pgSQL body",
+			sql:  `create function f() returns void language plpgsql as $$ begin if x then end; $$`,
+			want: `CREATE FUNCTION f()
+RETURNS void
+LANGUAGE plpgsql
+AS $$ begin if x then end; $$`,
+		},
+		{
+			name: "CREATE FUNCTION named like keyword with labeled loop",
+			sql:  `create function add(a int, b int) returns int language plpgsql as $$ begin <<outer>> loop exit outer when a > b; continue outer; end loop outer; return a + b; end $$`,
+			want: `CREATE FUNCTION add(a INT, b INT)
+RETURNS INT
+LANGUAGE plpgsql
+AS $$
+BEGIN
+  <<outer>>
+  LOOP
+    EXIT outer WHEN a > b;
+    CONTINUE outer;
+  END LOOP outer;
+  RETURN a + b;
+END
+$$`,
+		},
+		{
+			name: "DO statement",
+			sql:  `do $$ begin perform 1; end $$`,
+			want: `DO $$
+BEGIN
+  PERFORM 1;
+END
+$$`,
+		},
+
+		/*
+		 * CREATE TRIGGER and DROP TRIGGER statements
+		 */
+		{
+			name: "CREATE TRIGGER with condition",
+			sql:  `create or replace trigger trg before insert or update of a, b on public.t for each row when (old.a is distinct from new.a and coalesce(new.b, 0) > 1) execute function f('x', 1)`,
+			want: `CREATE OR REPLACE TRIGGER trg
+BEFORE INSERT OR UPDATE OF a, b
+ON public.t
+FOR EACH ROW
+WHEN (old.a IS DISTINCT FROM new.a AND COALESCE(new.b, 0) > 1)
+EXECUTE FUNCTION f('x', 1)`,
+		},
+		{
+			name: "CREATE TRIGGER with transition relation",
+			sql:  `create trigger trg after delete on t referencing old table as o for each statement execute procedure audit.log_changes()`,
+			want: `CREATE TRIGGER trg
+AFTER DELETE
+ON t
+REFERENCING OLD TABLE AS o
+FOR EACH STATEMENT
+EXECUTE PROCEDURE audit.log_changes()`,
+		},
+		{
+			name: "CREATE TRIGGER INSTEAD OF",
+			sql:  `create trigger trg instead of insert on v for each row execute function f()`,
+			want: `CREATE TRIGGER trg
+INSTEAD OF INSERT
+ON v
+FOR EACH ROW
+EXECUTE FUNCTION f()`,
+		},
+		{
+			name: "CREATE EVENT TRIGGER",
+			sql:  `create event trigger et on ddl_command_start when tag in ('CREATE TABLE', 'DROP TABLE') execute function g()`,
+			want: `CREATE EVENT TRIGGER et
+ON ddl_command_start
+WHEN TAG IN ('CREATE TABLE', 'DROP TABLE')
+EXECUTE FUNCTION g()`,
+		},
+		{
+			name: "CREATE TRIGGER with statement of MySQL",
+			sql:  "create trigger trg before insert on t for each row set new.a = upper(new.a)",
+			want: `CREATE TRIGGER trg
+BEFORE INSERT
+ON t
+FOR EACH ROW
+SET new.a = UPPER(new.a)`,
+		},
+		{
+			name: "DROP TRIGGER",
+			sql:  `drop trigger if exists trg on t`,
+			want: `DROP TRIGGER IF EXISTS trg ON t`,
+		},
+
+		{
+			name: "Trigger keywords as column names",
+			sql:  `insert into log (event, before, after) select event, before, after from t where each > 1`,
+			want: `INSERT INTO log
+  (event, before, after)
+SELECT
+  event,
+  before,
+  after
+FROM t
+WHERE each > 1`,
+		},
+		{
+			name: "Trigger keywords as column names within CREATE TRIGGER",
+			sql:  `create trigger trg before update on t for each row when (new.after is null) execute function f()`,
+			want: `CREATE TRIGGER trg
+BEFORE UPDATE
+ON t
+FOR EACH ROW
+WHEN (new.after IS NULL)
+EXECUTE FUNCTION f()`,
+		},
+
+		/*
+		 * GRANT, REVOKE, CREATE ROLE and CREATE POLICY statements
+		 */
+		{
+			name: "GRANT on all tables in schema",
+			sql:  `grant select, insert on all tables in schema public to app_user, public with grant option`,
+			want: `GRANT SELECT, INSERT ON ALL TABLES IN SCHEMA public
+TO app_user, PUBLIC
+WITH GRANT OPTION`,
+		},
+		{
+			name: "GRANT with column list",
+			sql:  `grant update (a, b) on t to bob`,
+			want: `GRANT UPDATE (a, b) ON t
+TO bob`,
+		},
+		{
+			name: "REVOKE on function",
+			sql:  `revoke all privileges on function f(int, text) from public cascade`,
+			want: `REVOKE ALL PRIVILEGES ON FUNCTION f(INT, TEXT)
+FROM PUBLIC CASCADE`,
+		},
+		{
+			name: "GRANT role",
+			sql:  `grant r1, r2 to bob with admin option granted by admin`,
+			want: `GRANT r1, r2
+TO bob
+WITH ADMIN OPTION
+GRANTED BY admin`,
+		},
+		{
+			name: "ALTER DEFAULT PRIVILEGES",
+			sql:  `alter default privileges in schema public grant select on tables to app_user`,
+			want: `ALTER DEFAULT PRIVILEGES IN SCHEMA public
+GRANT SELECT ON TABLES
+TO app_user`,
+		},
+		{
+			name: "CREATE ROLE",
+			sql:  `create role app with login password 'secret' valid until 'infinity' in role readers, writers`,
+			want: `CREATE ROLE app WITH
+  LOGIN
+  PASSWORD 'secret'
+  VALID UNTIL 'infinity'
+  IN ROLE readers, writers`,
+		},
+		{
+			name: "CREATE USER",
+			sql:  `create user bob identified by 'pw'`,
+			want: `CREATE USER bob
+  IDENTIFIED BY 'pw'`,
+		},
+		{
+			name: "CREATE POLICY",
+			sql:  `create policy tenant_isolation on orders as restrictive for select to app_user using (tenant_id = current_setting('app.tenant') and not deleted) with check (owner = current_user)`,
+			want: `CREATE POLICY tenant_isolation ON orders
+AS RESTRICTIVE
+FOR SELECT
+TO app_user
+USING (
+  tenant_id = CURRENT_SETTING('app.tenant')
+  AND NOT deleted
+)
+WITH CHECK (owner = CURRENT_USER)`,
+		},
+		{
+			name: "ALTER POLICY",
+			sql:  `alter policy p on t rename to q`,
+			want: `ALTER POLICY p ON t RENAME TO q`,
+		},
+
+		{
+			name: "Privilege keywords as column names",
+			sql:  `select role, policy, grant, revoke from users where role = 'admin'`,
+			want: `SELECT
+  role,
+  policy,
+  grant,
+  revoke
+FROM users
+WHERE role = 'admin'`,
+		},
+		{
+			name: "ALTER DEFAULT PRIVILEGES for role",
+			sql:  `alter default privileges for role admin revoke execute on functions from public`,
+			want: `ALTER DEFAULT PRIVILEGES FOR ROLE admin
+REVOKE EXECUTE ON FUNCTIONS
+FROM PUBLIC`,
+		},
+
+		{
+			name: "MERGE keywords as column names",
+			sql:  `create table t (id int, merge text); select (merge), coalesce(merge, 1), matched from t; update t set merge = 1 where a = 2`,
+			want: `CREATE TABLE t ( id INT,
+  merge TEXT);
+
+SELECT (merge),
+  COALESCE(merge, 1),
+  matched
+FROM t;
+
+UPDATE t
+SET merge = 1
+WHERE a = 2`,
+		},
+
+		{
+			name: "Routine keywords as names",
+			sql:  `insert into t (function, role) values (1, 2); select p.id from procedure p`,
+			want: `INSERT INTO t
+  (function, role)
+VALUES
+  (1, 2);
+
+SELECT
+  p.id
+FROM procedure p`,
+		},
+
+		{
+			name: "Conflict keywords as column names",
+			sql:  `select a from t join u on conflict = u.x join v on duplicate = v.x where (conflict = 1); insert into t (a) values (1) on conflict (a) do nothing`,
+			want: `SELECT
+  a
+FROM t
+JOIN u ON conflict = u.x
+JOIN v ON duplicate = v.x
+WHERE (conflict = 1);
+
+INSERT INTO t
+  (a)
+VALUES
+  (1)
+ON CONFLICT (a) DO NOTHING`,
+		},
+
+		{
+			name: "Storage keywords as column names",
+			sql:  `select a from t where tablespace = 1 and materialized = 2 and concurrently = 3; alter table t set tablespace fast`,
+			want: `SELECT
+  a
+FROM t
+WHERE
+  tablespace = 1
+  AND materialized = 2
+  AND concurrently = 3;
+
+ALTER TABLE t SET TABLESPACE fast`,
+		},
+
 		/*
 		 * END
 		 */
@@ -1659,6 +2559,282 @@ FROM archived_hosts`,
 	}
 }
 
+func TestFormat_Dialect(t *testing.T) {
+	sql := `select dump(a), nvl(b, 0) from user where c ilike 'x'`
+	tests := []struct {
+		dialect lexer.Dialect
+		want    string
+	}{
+		{
+			dialect: lexer.PostgreSQL,
+			want: `SELECT
+  dump (a),
+  nvl (b, 0)
+FROM USER
+WHERE c ILIKE 'x'`,
+		},
+		{
+			dialect: lexer.MySQL,
+			want: `SELECT
+  dump (a),
+  nvl (b, 0)
+FROM user
+WHERE c ilike 'x'`,
+		},
+		{
+			dialect: lexer.Oracle,
+			want: `SELECT
+  DUMP(a),
+  NVL(b, 0)
+FROM USER
+WHERE c ilike 'x'`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.dialect.String(), func(t *testing.T) {
+			options := formatters.DefaultOptions()
+			options.Dialect = tt.dialect
+			got, err := Format(sql, options)
+			if err != nil {
+				t.Errorf("%v", err)
+			} else if tt.want != got {
+				t.Errorf("\n=======================\n=== GOT ==============>\n%s\n=======================\n=== WANT =============>\n%s\n=======================", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormat_DialectOperators(t *testing.T) {
+	tests := []struct {
+		dialect lexer.Dialect
+		sql     string
+		want    string
+	}{
+		{dialect: lexer.ANSI, sql: `select a->>'b' from t`, want: "SELECT\n  a ->> 'b'\nFROM t"},
+		{dialect: lexer.SQLServer, sql: `select a->>'b' from t`, want: "SELECT\n  a ->> 'b'\nFROM t"},
+		{dialect: lexer.Oracle, sql: `select a->>'b' from t`, want: "SELECT\n  a ->> 'b'\nFROM t"},
+		{dialect: lexer.PostgreSQL, sql: `select * from t where a <=> b`, want: "SELECT\n  *\nFROM t\nWHERE a <=> b"},
+		{dialect: lexer.SQLServer, sql: `update t set a += 1, b -= 2`, want: "UPDATE t\nSET\n  a += 1,\n  b -= 2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.dialect.String()+": "+tt.sql, func(t *testing.T) {
+			options := formatters.DefaultOptions()
+			options.Dialect = tt.dialect
+			got, err := Format(tt.sql, options)
+			if err != nil {
+				t.Errorf("%v", err)
+			} else if tt.want != got {
+				t.Errorf("\n=======================\n=== GOT ==============>\n%s\n=======================\n=== WANT =============>\n%s\n=======================", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormat_DisableFunctionKeywords(t *testing.T) {
+	sql := `select current_date from user`
+	tests := []struct {
+		disable bool
+		want    string
+	}{
+		{disable: false, want: "SELECT\n  CURRENT_DATE\nFROM USER"},
+		{disable: true, want: "SELECT\n  current_date\nFROM user"},
+	}
+
+	// Format concurrently with different settings, which must not affect each other
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		tt := tests[i%len(tests)]
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			options := formatters.DefaultOptions()
+			options.DisableFunctionKeywords = tt.disable
+			got, err := Format(sql, options)
+			if err != nil {
+				t.Errorf("%v", err)
+			} else if tt.want != got {
+				t.Errorf("disable=%t: got %q, want %q", tt.disable, got, tt.want)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestFormat_Registry(t *testing.T) {
+	registry := lexer.NewRegistry()
+	registry.RegisterFunctions("st_distance")
+	registry.RegisterTypes("citext")
+
+	options := formatters.DefaultOptions()
+	options.Registry = registry
+	got, err := Format(`select st_distance(a, b), cast(c as citext) from t`, options)
+	want := `SELECT
+  ST_DISTANCE(a, b),
+  CAST(c AS CITEXT)
+FROM t`
+	if err != nil {
+		t.Errorf("%v", err)
+	} else if want != got {
+		t.Errorf("\n=======================\n=== GOT ==============>\n%s\n=======================\n=== WANT =============>\n%s\n=======================", got, want)
+	}
+}
+
+func TestFormatStream(t *testing.T) {
+	tests := []struct {
+		name    string
+		sql     string
+		padding string
+	}{
+		{
+			name: "Single statement",
+			sql:  `select a, b from t where c = 1`,
+		},
+		{
+			name: "Script with comments and empty statements",
+			sql:  "-- leading\nselect a from t; -- first\n;\nupdate t set a = 1 /* x */; /* y */ delete from t where (a = ';');",
+		},
+		{
+			name:    "Script with padding",
+			sql:     `select a from t; select b from u`,
+			padding: "    ",
+		},
+		{
+			name:    "Script with COPY data",
+			sql:     "copy public.t (id, name) from stdin;\n1\tO'Brien\n2\t-- (\n\\.\nselect a from t;",
+			padding: "  ",
+		},
+		{
+			name: "Empty input",
+			sql:  ``,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			options := formatters.DefaultOptions()
+			options.Padding = tt.padding
+
+			// Format script as a whole for comparison
+			want, errWant := Format(tt.sql, options)
+			if errWant != nil {
+				t.Fatalf("%v", errWant)
+			}
+
+			// Verify that the streamed result equals the one of Format
+			var buf bytes.Buffer
+			err := FormatStream(strings.NewReader(tt.sql), &buf, options)
+			if err != nil {
+				t.Errorf("%v", err)
+			} else if got := buf.String(); want != got {
+				t.Errorf("\n=======================\n=== GOT ==============>\n%s\n=======================\n=== WANT =============>\n%s\n=======================", got, want)
+			}
+		})
+	}
+}
+
+func TestFormatStream_Error(t *testing.T) {
+	var buf bytes.Buffer
+	err := FormatStream(strings.NewReader(`select a from t; select 'open`), &buf, formatters.DefaultOptions())
+
+	// Verify that statements preceding the error are written
+	if err == nil {
+		t.Errorf("expected error")
+	}
+	if want := "SELECT\n  a\nFROM t;"; buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+}
+
+func TestFormatStream_CopyData(t *testing.T) {
+	var buf bytes.Buffer
+	sql := "copy public.t (id, name) from stdin;\n1\tO'Brien\n2\t-- not a comment; (\n\\.\nselect  a from t;"
+	err := FormatStream(strings.NewReader(sql), &buf, formatters.DefaultOptions())
+
+	// Verify that the data is written verbatim on the line following its statement
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	want := "COPY public.t (id, name)\nFROM stdin;\n1\tO'Brien\n2\t-- not a comment; (\n\\.\n\nSELECT\n  a\nFROM t;"
+	if buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+}
+
+func TestFormatWithWarnings(t *testing.T) {
+	options := formatters.DefaultOptions()
+	options.Recover = true
+	tests := []struct {
+		name         string
+		sql          string
+		want         string
+		wantWarnings []string
+	}{
+		{
+			name:         "unsupported statement",
+			sql:          "select a from t; prepare q as select 1;\nselect b from u",
+			want:         "SELECT\n  a\nFROM t;\n\nprepare q as select 1;\n\nSELECT\n  b\nFROM u",
+			wantWarnings: []string{"prepare q as select 1"},
+		},
+		{
+			name:         "unsupported statement lines",
+			sql:          "truncate table t\n  restart identity\n  cascade",
+			want:         "truncate table t\n  restart identity\n  cascade",
+			wantWarnings: []string{"truncate table t restart identity cascade"},
+		},
+		{
+			name:         "unsupported region",
+			sql:          "select a) from t where b = 1",
+			want:         "SELECT\n  a\n)\nFROM t\nWHERE b = 1",
+			wantWarnings: []string{")"},
+		},
+		{
+			name: "supported",
+			sql:  "select a from t",
+			want: "SELECT\n  a\nFROM t",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, warnings, err := FormatWithWarnings(tt.sql, options)
+			if err != nil {
+				t.Fatalf("%v", err)
+			}
+			if got != tt.want {
+				t.Errorf("\n=======================\n=== WANT =============>\n%s\n=======================\n=== GOT ==============>\n%s\n=======================", tt.want, got)
+			}
+			var texts []string
+			for _, warning := range warnings {
+				texts = append(texts, warning.Text)
+			}
+			if !reflect.DeepEqual(texts, tt.wantWarnings) {
+				t.Errorf("got warnings %q, want %q", texts, tt.wantWarnings)
+			}
+		})
+	}
+}
+
+func TestFormatWithWarnings_Disabled(t *testing.T) {
+	if _, _, err := FormatWithWarnings("prepare q as select 1", formatters.DefaultOptions()); err == nil {
+		t.Errorf("expected error")
+	}
+}
+
+func TestParseStatements(t *testing.T) {
+	sql := "-- leading\nselect a from t where b = 1;\n;\nupdate u set a = 1"
+	stmts, err := ParseStatements(sql, formatters.DefaultOptions())
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	if len(stmts) != 2 {
+		t.Fatalf("want 2 statements, got %d", len(stmts))
+	}
+	if stmt, ok := stmts[0].(*ast.SelectStmt); !ok || ast.Text(stmt.Where) != "b = 1" {
+		t.Errorf("want SELECT statement with WHERE condition, got %#v", stmts[0])
+	}
+	if _, ok := stmts[1].(*ast.UpdateStmt); !ok {
+		t.Errorf("want UPDATE statement, got %#v", stmts[1])
+	}
+}
+
 func TestCompareSemantic(t *testing.T) {
 	tests := []struct {
 		name   string
@@ -1726,6 +2902,18 @@ FROM public.all_services`,
 			str:  `SELECT DISTINCT concat(ip,'--',port) FROM public.all_services`,
 			want: `SELECT DISTINCT concat(ip,'--',port) FROM public.all_services`,
 		},
+		{
+			name: "Dash comment in double quotes",
+			str:  `SELECT "col--1" FROM public.all_services`,
+			want: `SELECT "col--1" FROM public.all_services`,
+		},
+		{
+			name: "Dash comment in escape string",
+			str: `SELECT E'it\'s -- x' -- create string
+FROM public.all_services`,
+			want: `SELECT E'it\'s -- x' 
+FROM public.all_services`,
+		},
 		{
 			name: "Slash comment in quotes",
 			str:  `SELECT DISTINCT concat('http://',ip,':',port,'/') FROM public.all_services`,